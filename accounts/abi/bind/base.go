@@ -69,6 +69,10 @@ type TransactOpts struct {
 	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
 
 	NoSend bool // Do all transact steps but do not send the transaction
+
+	// Celo specific
+	FeeCurrency         *common.Address // Fee currency to pay gas in (nil = native currency)
+	MaxFeeInFeeCurrency *big.Int        // Cap of the fee in fee currency, for fees denominated in native currency (CIP-66, not supported yet)
 }
 
 // FilterOpts is the collection of options to fine tune filtering for events
@@ -373,6 +377,9 @@ func (c *BoundContract) estimateGasLimit(opts *TransactOpts, contract *common.Ad
 		GasFeeCap: gasFeeCap,
 		Value:     value,
 		Data:      input,
+
+		FeeCurrency:         opts.FeeCurrency,
+		MaxFeeInFeeCurrency: opts.MaxFeeInFeeCurrency,
 	}
 	return c.transactor.EstimateGas(ensureContext(opts.Context), msg)
 }
@@ -396,7 +403,9 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 		rawTx *types.Transaction
 		err   error
 	)
-	if opts.GasPrice != nil {
+	if opts.FeeCurrency != nil || opts.MaxFeeInFeeCurrency != nil {
+		rawTx, err = c.createCeloTx(opts, contract, input)
+	} else if opts.GasPrice != nil {
 		rawTx, err = c.createLegacyTx(opts, contract, input)
 	} else if opts.GasFeeCap != nil && opts.GasTipCap != nil {
 		rawTx, err = c.createDynamicTx(opts, contract, input, nil)
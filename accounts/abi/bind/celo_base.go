@@ -0,0 +1,112 @@
+package bind
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNoFeeCurrencyGasPricer is returned if fees for a fee currency transaction
+// have to be suggested, but the transactor doesn't implement
+// ethereum.FeeCurrencyGasPricer.
+var ErrNoFeeCurrencyGasPricer = errors.New("transactor does not support gas price suggestions in fee currency")
+
+// ErrCIP66Unsupported is returned if opts.MaxFeeInFeeCurrency is set, as
+// transactions with fees denominated in the native currency (CIP-66) can't be
+// signed yet.
+var ErrCIP66Unsupported = errors.New("fee currency transactions with maxFeeInFeeCurrency (CIP-66) are not supported")
+
+// createCeloTx creates a CeloDynamicFeeTxV2 (CIP-64) transaction paying for
+// gas in opts.FeeCurrency, with the fees denominated in the fee currency.
+func (c *BoundContract) createCeloTx(opts *TransactOpts, contract *common.Address, input []byte) (*types.Transaction, error) {
+	if opts.MaxFeeInFeeCurrency != nil {
+		return nil, ErrCIP66Unsupported
+	}
+	if opts.FeeCurrency == nil {
+		return nil, errors.New("maxFeeInFeeCurrency specified without feeCurrency")
+	}
+	if opts.GasPrice != nil {
+		return nil, errors.New("gasPrice specified for fee currency transaction")
+	}
+	// Normalize value
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	// Estimate TipCap and FeeCap
+	gasTipCap, gasFeeCap, err := c.suggestFeeCurrencyFees(opts)
+	if err != nil {
+		return nil, err
+	}
+	if gasFeeCap.Cmp(gasTipCap) < 0 {
+		return nil, fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", gasFeeCap, gasTipCap)
+	}
+	// Estimate GasLimit
+	gasLimit := opts.GasLimit
+	if opts.GasLimit == 0 {
+		gasLimit, err = c.estimateGasLimit(opts, contract, input, nil, gasTipCap, gasFeeCap, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// create the transaction
+	nonce, err := c.getNonce(opts)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewTx(&types.CeloDynamicFeeTxV2{
+		To:          contract,
+		Nonce:       nonce,
+		GasFeeCap:   gasFeeCap,
+		GasTipCap:   gasTipCap,
+		Gas:         gasLimit,
+		Value:       value,
+		Data:        input,
+		AccessList:  opts.AccessList,
+		FeeCurrency: opts.FeeCurrency,
+	}), nil
+}
+
+// suggestFeeCurrencyFees returns the tip and fee cap, denominated in the fee
+// currency, for fields not set in opts. The suggestions are taken from the
+// eth_gasPrice and eth_maxPriorityFeePerGas overrides of the celo API, which
+// convert them to the fee currency.
+func (c *BoundContract) suggestFeeCurrencyFees(opts *TransactOpts) (*big.Int, *big.Int, error) {
+	if opts.GasTipCap != nil && opts.GasFeeCap != nil {
+		return opts.GasTipCap, opts.GasFeeCap, nil
+	}
+	pricer, ok := c.transactor.(ethereum.FeeCurrencyGasPricer)
+	if !ok {
+		return nil, nil, ErrNoFeeCurrencyGasPricer
+	}
+	suggestedTip, err := pricer.SuggestGasTipCapForCurrency(ensureContext(opts.Context), opts.FeeCurrency)
+	if err != nil {
+		return nil, nil, err
+	}
+	gasTipCap := opts.GasTipCap
+	if gasTipCap == nil {
+		gasTipCap = suggestedTip
+	}
+	gasFeeCap := opts.GasFeeCap
+	if gasFeeCap == nil {
+		// The suggested gas price is the sum of the suggested tip and the
+		// base fee, both converted to the fee currency.
+		gasPrice, err := pricer.SuggestGasPriceForCurrency(ensureContext(opts.Context), opts.FeeCurrency)
+		if err != nil {
+			return nil, nil, err
+		}
+		baseFee := new(big.Int).Sub(gasPrice, suggestedTip)
+		if baseFee.Sign() < 0 {
+			baseFee.SetUint64(0)
+		}
+		gasFeeCap = new(big.Int).Add(
+			gasTipCap,
+			new(big.Int).Mul(baseFee, big.NewInt(basefeeWiggleMultiplier)),
+		)
+	}
+	return gasTipCap, gasFeeCap, nil
+}
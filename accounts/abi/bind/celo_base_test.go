@@ -0,0 +1,152 @@
+package bind_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/contracts/celo/abigen"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockFeeCurrencyTransactor struct {
+	mockTransactor
+	gasTipCapInCurrency *big.Int
+	gasPriceInCurrency  *big.Int
+	estimateGasMsg      ethereum.CallMsg
+}
+
+func (mt *mockFeeCurrencyTransactor) SuggestGasPriceForCurrency(ctx context.Context, feeCurrency *common.Address) (*big.Int, error) {
+	return mt.gasPriceInCurrency, nil
+}
+
+func (mt *mockFeeCurrencyTransactor) SuggestGasTipCapForCurrency(ctx context.Context, feeCurrency *common.Address) (*big.Int, error) {
+	return mt.gasTipCapInCurrency, nil
+}
+
+func (mt *mockFeeCurrencyTransactor) EstimateGas(ctx context.Context, call ethereum.CallMsg) (gas uint64, err error) {
+	mt.estimateGasMsg = call
+	return 50000, nil
+}
+
+func TestTransactFeeCurrency(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	feeCurrency := common.HexToAddress("0xce16")
+	chainID := big.NewInt(1337)
+	keyedOpts := func() *bind.TransactOpts {
+		opts, err := bind.NewKeyedTransactorWithChainID(testKey, chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts.FeeCurrency = &feeCurrency
+		return opts
+	}
+
+	// CIP-64: fees are suggested in the fee currency
+	mt := &mockFeeCurrencyTransactor{
+		mockTransactor:      mockTransactor{baseFee: big.NewInt(100), gasTipCap: big.NewInt(5)},
+		gasTipCapInCurrency: big.NewInt(10),
+		gasPriceInCurrency:  big.NewInt(210),
+	}
+	bc := bind.NewBoundContract(common.Address{}, abi.ABI{}, nil, mt, nil)
+	tx, err := bc.Transact(keyedOpts(), "")
+	assert.Nil(err)
+	assert.Equal(uint8(types.CeloDynamicFeeTxV2Type), tx.Type())
+	assert.Equal(&feeCurrency, tx.FeeCurrency())
+	assert.Equal(big.NewInt(10), tx.GasTipCap())
+	assert.Equal(big.NewInt(410), tx.GasFeeCap())
+	assert.Equal(uint64(50000), tx.Gas())
+	assert.Equal(&feeCurrency, mt.estimateGasMsg.FeeCurrency)
+	assert.False(mt.suggestGasTipCapCalled)
+
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	assert.Nil(err)
+	assert.Equal(crypto.PubkeyToAddress(testKey.PublicKey), sender)
+
+	// CIP-66 transactions can't be signed yet
+	opts := keyedOpts()
+	opts.MaxFeeInFeeCurrency = big.NewInt(1_000_000)
+	_, err = bc.Transact(opts, "")
+	assert.ErrorIs(err, bind.ErrCIP66Unsupported)
+
+	// Suggestions in fee currency require a supporting transactor
+	bc = bind.NewBoundContract(common.Address{}, abi.ABI{}, nil, &mockTransactor{baseFee: big.NewInt(100)}, nil)
+	_, err = bc.Transact(keyedOpts(), "")
+	assert.ErrorIs(err, bind.ErrNoFeeCurrencyGasPricer)
+
+	// Legacy gas price can't be combined with a fee currency
+	opts = keyedOpts()
+	opts.GasPrice = big.NewInt(1)
+	_, err = bc.Transact(opts, "")
+	assert.NotNil(err)
+}
+
+// Tests that generated bindings can pay for gas in a fee currency when run
+// against a simulated backend.
+func TestTransactFeeCurrencySimulated(t *testing.T) {
+	t.Parallel()
+	var (
+		addr        = crypto.PubkeyToAddress(testKey.PublicKey)
+		recipient   = common.HexToAddress("0xaaaa")
+		feeCurrency = common.HexToAddress("0x000000000000000000000000000000000000ce16")
+		funds       = big.NewInt(params.Ether)
+	)
+	backend := simulated.NewBackend(
+		types.GenesisAlloc{addr: {Balance: big.NewInt(10000000000000000)}},
+		simulated.WithCeloGenesis(&core.CeloGenesisConfig{
+			FeeCurrencies: []core.CeloGenesisFeeCurrency{{
+				Address:         feeCurrency,
+				RateNumerator:   math.NewHexOrDecimal256(2),
+				RateDenominator: math.NewHexOrDecimal256(1),
+				Balances:        map[common.Address]*math.HexOrDecimal256{addr: (*math.HexOrDecimal256)(funds)},
+			}},
+		}),
+	)
+	defer backend.Close()
+
+	token, err := abigen.NewFeeCurrency(feeCurrency, backend.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(testKey, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.FeeCurrency = &feeCurrency
+	tx, err := token.Transfer(opts, recipient, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	if tx.Type() != types.CeloDynamicFeeTxV2Type {
+		t.Fatalf("unexpected transaction type: %d", tx.Type())
+	}
+	backend.Commit()
+
+	receipt, err := bind.WaitMined(context.Background(), backend.Client(), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("transaction failed")
+	}
+	balance, err := token.BalanceOf(nil, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The sender paid for both the transfer and the fees in the fee currency
+	spent := new(big.Int).Sub(funds, balance)
+	if spent.Cmp(big.NewInt(1000)) <= 0 {
+		t.Errorf("fees not paid in fee currency: spent %v", spent)
+	}
+}
@@ -235,6 +235,12 @@ func (api *ExternalSigner) SignTx(account accounts.Account, tx *types.Transactio
 		accessList := tx.AccessList()
 		args.AccessList = &accessList
 	}
+	if tx.Type() == types.CeloDynamicFeeTxV2Type || tx.Type() == types.CeloDenominatedTxType {
+		args.FeeCurrency = tx.FeeCurrency()
+	}
+	if tx.Type() == types.CeloDenominatedTxType {
+		args.MaxFeeInFeeCurrency = (*hexutil.Big)(tx.MaxFeeInFeeCurrency())
+	}
 	if tx.Type() == types.BlobTxType {
 		args.BlobHashes = tx.BlobHashes()
 		sidecar := tx.BlobTxSidecar()
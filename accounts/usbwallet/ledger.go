@@ -72,6 +72,10 @@ var errLedgerReplyInvalidHeader = errors.New("ledger: invalid reply header")
 // when a response does arrive, but it does not contain the expected data.
 var errLedgerInvalidVersionReply = errors.New("ledger: invalid version reply")
 
+// errLedgerCIP66Unsupported is the error message returned when attempting to sign
+// a CIP-66 transaction, which the signer can't handle yet.
+var errLedgerCIP66Unsupported = errors.New("ledger: CIP-66 transactions are not supported")
+
 // ledgerDriver implements the communication with a Ledger hardware wallet.
 type ledgerDriver struct {
 	device  io.ReadWriter // USB device connection to communicate through
@@ -328,19 +332,16 @@ func (w *ledgerDriver) ledgerSign(derivationPath []uint32, tx *types.Transaction
 	for i, component := range derivationPath {
 		binary.BigEndian.PutUint32(path[1+4*i:], component)
 	}
-	// Create the transaction RLP based on whether legacy or EIP155 signing was requested
-	var (
-		txrlp []byte
-		err   error
-	)
-	if chainID == nil {
-		if txrlp, err = rlp.EncodeToBytes([]interface{}{tx.Nonce(), tx.GasPrice(), tx.Gas(), tx.To(), tx.Value(), tx.Data()}); err != nil {
-			return common.Address{}, nil, err
-		}
-	} else {
-		if txrlp, err = rlp.EncodeToBytes([]interface{}{tx.Nonce(), tx.GasPrice(), tx.Gas(), tx.To(), tx.Value(), tx.Data(), chainID, big.NewInt(0), big.NewInt(0)}); err != nil {
-			return common.Address{}, nil, err
-		}
+	// Create the transaction RLP based on the transaction type and on whether
+	// legacy or EIP155 signing was requested
+	txrlp, err := ledgerEncodeTx(tx, chainID)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	// Make sure typed transactions can actually be signed before asking the user
+	// for confirmation, there's no point otherwise
+	if tx.Type() != types.LegacyTxType && crypto.Keccak256Hash(txrlp) != types.LatestSignerForChainID(chainID).Hash(tx) {
+		return common.Address{}, nil, fmt.Errorf("ledger: transaction type %#x not supported by the signer", tx.Type())
 	}
 	payload := append(path, txrlp...)
 
@@ -377,10 +378,15 @@ func (w *ledgerDriver) ledgerSign(derivationPath []uint32, tx *types.Transaction
 	signature := append(reply[1:], reply[0])
 
 	// Create the correct signer and signature transform based on the chain ID
+	// and the transaction type. Typed transactions are signed with the plain
+	// recovery id, so there's nothing to transform.
 	var signer types.Signer
-	if chainID == nil {
+	switch {
+	case chainID == nil:
 		signer = new(types.HomesteadSigner)
-	} else {
+	case tx.Type() != types.LegacyTxType:
+		signer = types.LatestSignerForChainID(chainID)
+	default:
 		signer = types.NewEIP155Signer(chainID)
 		signature[64] -= byte(chainID.Uint64()*2 + 35)
 	}
@@ -395,6 +401,52 @@ func (w *ledgerDriver) ledgerSign(derivationPath []uint32, tx *types.Transaction
 	return sender, signed, nil
 }
 
+// ledgerEncodeTx creates the payload the Ledger app expects for signing the
+// given transaction, which is the same data the transaction's signing hash is
+// calculated over.
+//
+// Apart from legacy transactions, typed transactions are sent in their typed
+// envelope format, i.e. the transaction type followed by the RLP list of the
+// fields covered by the signature. Besides the Ethereum access list and dynamic
+// fee transactions, the Celo Ledger app supports CIP-64 fee currency transactions:
+//
+//	0x7b || rlp([chainId, nonce, maxPriorityFeePerGas, maxFeePerGas, gas, to, value, data, accessList, feeCurrency])
+//
+// CIP-66 transactions are rejected, as they can't be signed yet.
+func ledgerEncodeTx(tx *types.Transaction, chainID *big.Int) ([]byte, error) {
+	var fields []interface{}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		if chainID == nil {
+			return rlp.EncodeToBytes([]interface{}{tx.Nonce(), tx.GasPrice(), tx.Gas(), tx.To(), tx.Value(), tx.Data()})
+		}
+		return rlp.EncodeToBytes([]interface{}{tx.Nonce(), tx.GasPrice(), tx.Gas(), tx.To(), tx.Value(), tx.Data(), chainID, big.NewInt(0), big.NewInt(0)})
+
+	case types.AccessListTxType:
+		fields = []interface{}{chainID, tx.Nonce(), tx.GasPrice(), tx.Gas(), tx.To(), tx.Value(), tx.Data(), tx.AccessList()}
+
+	case types.DynamicFeeTxType:
+		fields = []interface{}{chainID, tx.Nonce(), tx.GasTipCap(), tx.GasFeeCap(), tx.Gas(), tx.To(), tx.Value(), tx.Data(), tx.AccessList()}
+
+	case types.CeloDynamicFeeTxV2Type:
+		fields = []interface{}{chainID, tx.Nonce(), tx.GasTipCap(), tx.GasFeeCap(), tx.Gas(), tx.To(), tx.Value(), tx.Data(), tx.AccessList(), tx.FeeCurrency()}
+
+	case types.CeloDenominatedTxType:
+		return nil, errLedgerCIP66Unsupported
+
+	default:
+		return nil, types.ErrTxTypeNotSupported
+	}
+	if chainID == nil {
+		return nil, fmt.Errorf("ledger: chain ID required for transaction type %#x", tx.Type())
+	}
+	txrlp, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{tx.Type()}, txrlp...), nil
+}
+
 // ledgerSignTypedMessage sends the transaction to the Ledger wallet, and waits for the user
 // to confirm or deny the transaction.
 //
@@ -0,0 +1,294 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package usbwallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// mockLedger is a fake USB transport speaking the Ledger HID framing. It
+// implements just enough of the Ethereum/Celo app to derive an address and
+// to sign transactions with a local key.
+type mockLedger struct {
+	key     *ecdsa.PrivateKey
+	chainID *big.Int
+
+	apdu    []byte       // APDU currently being reassembled
+	apduLen int          // Total length of the APDU being reassembled
+	txdata  []byte       // Transaction payload being streamed for signing
+	replies bytes.Buffer // Reply chunks waiting to be read
+}
+
+func newMockLedger(t *testing.T, chainID *big.Int) *mockLedger {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &mockLedger{key: key, chainID: chainID}
+}
+
+// Write implements io.Writer, collecting the chunks of an APDU and processing
+// it once complete.
+func (l *mockLedger) Write(chunk []byte) (int, error) {
+	if len(chunk) < 5 || chunk[0] != 0x01 || chunk[1] != 0x01 || chunk[2] != 0x05 {
+		return 0, errors.New("invalid chunk header")
+	}
+	data := chunk[5:]
+	if binary.BigEndian.Uint16(chunk[3:]) == 0 {
+		l.apdu, l.apduLen = nil, int(binary.BigEndian.Uint16(chunk[5:]))
+		data = chunk[7:]
+	}
+	if left := l.apduLen - len(l.apdu); len(data) > left {
+		data = data[:left]
+	}
+	l.apdu = append(l.apdu, data...)
+	if len(l.apdu) == l.apduLen {
+		l.reply(l.handle(l.apdu[1], l.apdu[2], l.apdu[5:]))
+	}
+	return len(chunk), nil
+}
+
+// Read implements io.Reader, returning the pending reply chunks.
+func (l *mockLedger) Read(p []byte) (int, error) {
+	return l.replies.Read(p)
+}
+
+// handle processes a single APDU, returning the reply data without the status.
+func (l *mockLedger) handle(ins byte, p1 byte, data []byte) []byte {
+	switch ledgerOpcode(ins) {
+	case ledgerOpGetConfiguration:
+		return []byte{0x01, 1, 9, 0}
+
+	case ledgerOpRetrieveAddress:
+		pubkey := crypto.FromECDSAPub(&l.key.PublicKey)
+		addr := []byte(hex.EncodeToString(crypto.PubkeyToAddress(l.key.PublicKey).Bytes()))
+
+		reply := append([]byte{byte(len(pubkey))}, pubkey...)
+		reply = append(reply, byte(len(addr)))
+		return append(reply, addr...)
+
+	case ledgerOpSignTransaction:
+		if ledgerParam1(p1) == ledgerP1InitTransactionData {
+			l.txdata = append([]byte{}, data[1+4*int(data[0]):]...)
+		} else {
+			l.txdata = append(l.txdata, data...)
+		}
+		// Typed transactions are prefixed with their type, reply only once the
+		// whole RLP list has been received
+		payload := l.txdata
+		if payload[0] < 0x7f {
+			payload = payload[1:]
+		}
+		if _, _, _, err := rlp.Split(payload); err != nil {
+			return nil
+		}
+		sig, err := crypto.Sign(crypto.Keccak256(l.txdata), l.key)
+		if err != nil {
+			panic(err)
+		}
+		v := sig[64]
+		if l.txdata[0] >= 0xc0 {
+			v += byte(l.chainID.Uint64()*2 + 35)
+		}
+		return append([]byte{v}, sig[:64]...)
+	}
+	panic("unexpected opcode")
+}
+
+// reply splits the reply into chunks and queues them for reading.
+func (l *mockLedger) reply(data []byte) {
+	data = append(data, 0x90, 0x00)
+
+	msg := make([]byte, 2, 2+len(data))
+	binary.BigEndian.PutUint16(msg, uint16(len(data)))
+	msg = append(msg, data...)
+
+	for i := 0; len(msg) > 0; i++ {
+		chunk := make([]byte, 64)
+		copy(chunk, []byte{0x01, 0x01, 0x05})
+		binary.BigEndian.PutUint16(chunk[3:], uint16(i))
+		msg = msg[copy(chunk[5:], msg):]
+		l.replies.Write(chunk)
+	}
+}
+
+func (l *mockLedger) address() common.Address {
+	return crypto.PubkeyToAddress(l.key.PublicKey)
+}
+
+func newTestLedgerDriver(t *testing.T, device *mockLedger) *ledgerDriver {
+	driver := newLedgerDriver(log.Root()).(*ledgerDriver)
+	if err := driver.Open(device, ""); err != nil {
+		t.Fatal(err)
+	}
+	if driver.offline() {
+		t.Fatal("ledger offline")
+	}
+	return driver
+}
+
+func TestLedgerDerive(t *testing.T) {
+	device := newMockLedger(t, big.NewInt(1))
+	driver := newTestLedgerDriver(t, device)
+
+	addr, err := driver.Derive(accounts.DefaultBaseDerivationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != device.address() {
+		t.Errorf("address mismatch: have %v, want %v", addr, device.address())
+	}
+}
+
+func TestLedgerSignTx(t *testing.T) {
+	var (
+		chainID     = big.NewInt(44787)
+		to          = common.HexToAddress("0x000000000000000000000000000000000000dead")
+		feeCurrency = common.HexToAddress("0x000000000000000000000000000000000000ce16")
+		data        = bytes.Repeat([]byte{0xff}, 500) // Force multiple chunks
+	)
+	device := newMockLedger(t, chainID)
+	driver := newTestLedgerDriver(t, device)
+
+	for _, tc := range []struct {
+		name string
+		tx   types.TxData
+		err  error
+	}{
+		{
+			name: "legacy",
+			tx:   &types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(100), Gas: 21000, To: &to, Value: big.NewInt(1)},
+		},
+		{
+			name: "cip64",
+			tx: &types.CeloDynamicFeeTxV2{
+				ChainID: chainID, Nonce: 2, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(200), Gas: 100000,
+				To: &to, Value: big.NewInt(1), Data: data, FeeCurrency: &feeCurrency,
+			},
+		},
+		{
+			name: "cip64-contract-creation",
+			tx: &types.CeloDynamicFeeTxV2{
+				ChainID: chainID, Nonce: 3, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(200), Gas: 100000,
+				Data: data[:100], FeeCurrency: &feeCurrency,
+			},
+		},
+		{
+			name: "access-list",
+			tx:   &types.AccessListTx{ChainID: chainID, Nonce: 4, GasPrice: big.NewInt(100), Gas: 30000, To: &to, AccessList: types.AccessList{{Address: to}}},
+		},
+		{
+			name: "dynamic-fee",
+			tx:   &types.DynamicFeeTx{ChainID: chainID, Nonce: 5, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(200), Gas: 21000, To: &to, Data: data},
+		},
+		{
+			// CIP-66 transactions are not yet accepted by the signer, they
+			// must be rejected before reaching the device.
+			name: "cip66",
+			tx: &types.CeloDenominatedTx{
+				ChainID: chainID, Nonce: 6, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(200), Gas: 100000,
+				To: &to, Value: big.NewInt(1), FeeCurrency: &feeCurrency, MaxFeeInFeeCurrency: big.NewInt(1e18),
+			},
+			err: errLedgerCIP66Unsupported,
+		},
+		{
+			name: "blob",
+			tx:   &types.BlobTx{Nonce: 7, Gas: 21000, To: to},
+			err:  types.ErrTxTypeNotSupported,
+		},
+	} {
+		device.txdata = nil
+
+		tx := types.NewTx(tc.tx)
+		sender, signed, err := driver.SignTx(accounts.DefaultBaseDerivationPath, tx, chainID)
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				t.Errorf("%s: error mismatch: have %v, want %v", tc.name, err, tc.err)
+			}
+			if device.txdata != nil {
+				t.Errorf("%s: transaction sent to device", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: failed to sign: %v", tc.name, err)
+		}
+		if sender != device.address() {
+			t.Errorf("%s: sender mismatch: have %v, want %v", tc.name, sender, device.address())
+		}
+		if signed.Type() != tx.Type() {
+			t.Errorf("%s: type mismatch: have %d, want %d", tc.name, signed.Type(), tx.Type())
+		}
+		if signed.FeeCurrency() != nil && *signed.FeeCurrency() != feeCurrency {
+			t.Errorf("%s: fee currency mismatch: have %v, want %v", tc.name, signed.FeeCurrency(), feeCurrency)
+		}
+		// Make sure the signature is valid for the chain's signer too
+		have, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+		if err != nil {
+			t.Fatalf("%s: failed to recover sender: %v", tc.name, err)
+		}
+		if have != device.address() {
+			t.Errorf("%s: recovered sender mismatch: have %v, want %v", tc.name, have, device.address())
+		}
+	}
+}
+
+func TestLedgerEncodeTx(t *testing.T) {
+	var (
+		chainID     = big.NewInt(44787)
+		to          = common.HexToAddress("0x000000000000000000000000000000000000dead")
+		feeCurrency = common.HexToAddress("0x000000000000000000000000000000000000ce16")
+		signer      = types.LatestSignerForChainID(chainID)
+	)
+	for _, txdata := range []types.TxData{
+		&types.AccessListTx{ChainID: chainID, Nonce: 1, GasPrice: big.NewInt(100), Gas: 30000, To: &to, AccessList: types.AccessList{{Address: to}}},
+		&types.DynamicFeeTx{ChainID: chainID, Nonce: 2, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(200), Gas: 21000, To: &to, Value: big.NewInt(1)},
+		&types.CeloDynamicFeeTxV2{ChainID: chainID, Nonce: 3, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(200), Gas: 100000, To: &to, FeeCurrency: &feeCurrency},
+	} {
+		tx := types.NewTx(txdata)
+		if _, err := ledgerEncodeTx(tx, nil); err == nil {
+			t.Errorf("type %#x: expected error for missing chain ID", tx.Type())
+		}
+		payload, err := ledgerEncodeTx(tx, chainID)
+		if err != nil {
+			t.Fatalf("type %#x: failed to encode: %v", tx.Type(), err)
+		}
+		if have, want := crypto.Keccak256Hash(payload), signer.Hash(tx); have != want {
+			t.Errorf("type %#x: signing hash mismatch: have %x, want %x", tx.Type(), have, want)
+		}
+	}
+	tx := types.NewTx(&types.CeloDenominatedTx{
+		ChainID: chainID, Nonce: 4, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(200), Gas: 100000,
+		To: &to, Value: big.NewInt(1), FeeCurrency: &feeCurrency, MaxFeeInFeeCurrency: big.NewInt(1e18),
+	})
+	if _, err := ledgerEncodeTx(tx, chainID); !errors.Is(err, errLedgerCIP66Unsupported) {
+		t.Errorf("CIP-66 error mismatch: have %v, want %v", err, errLedgerCIP66Unsupported)
+	}
+}
@@ -44,6 +44,10 @@ var ErrTrezorPINNeeded = errors.New("trezor: pin needed")
 // ErrTrezorPassphraseNeeded is returned if opening the trezor requires a passphrase
 var ErrTrezorPassphraseNeeded = errors.New("trezor: passphrase needed")
 
+// ErrTrezorCeloTxUnsupported is returned if a Celo specific transaction is
+// requested to be signed, which the Trezor firmware doesn't know how to encode.
+var ErrTrezorCeloTxUnsupported = errors.New("trezor: celo transaction types not supported")
+
 // errTrezorReplyInvalidHeader is the error message returned by a Trezor data exchange
 // if the device replies with a mismatching header. This usually means the device
 // is in browser mode.
@@ -182,6 +186,12 @@ func (w *trezorDriver) SignTx(path accounts.DerivationPath, tx *types.Transactio
 	if w.device == nil {
 		return common.Address{}, nil, accounts.ErrWalletClosed
 	}
+	// The device would show and sign an Ethereum payload without the fee
+	// currency fields, so don't even ask the user to confirm those.
+	switch {
+	case tx.Type() == types.CeloDynamicFeeTxV2Type, tx.Type() == types.CeloDenominatedTxType, tx.Type() == types.CeloDynamicFeeTxType, tx.IsCeloLegacy():
+		return common.Address{}, nil, ErrTrezorCeloTxUnsupported
+	}
 	return w.trezorSign(path, tx, chainID)
 }
 
@@ -0,0 +1,54 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package usbwallet
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Tests that Celo transaction types are refused without talking to the device.
+func TestTrezorSignCeloTx(t *testing.T) {
+	var (
+		chainID     = big.NewInt(44787)
+		to          = common.HexToAddress("0x000000000000000000000000000000000000dead")
+		feeCurrency = common.HexToAddress("0x000000000000000000000000000000000000ce16")
+		device      = new(bytes.Buffer)
+	)
+	driver := newTrezorDriver(log.Root()).(*trezorDriver)
+	driver.device = device
+
+	for _, txdata := range []types.TxData{
+		&types.CeloDynamicFeeTxV2{ChainID: chainID, Gas: 21000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1), To: &to, FeeCurrency: &feeCurrency},
+		&types.CeloDenominatedTx{ChainID: chainID, Gas: 21000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1), To: &to, FeeCurrency: &feeCurrency, MaxFeeInFeeCurrency: big.NewInt(1)},
+	} {
+		tx := types.NewTx(txdata)
+		if _, _, err := driver.SignTx(accounts.DefaultBaseDerivationPath, tx, chainID); !errors.Is(err, ErrTrezorCeloTxUnsupported) {
+			t.Errorf("tx type %#x: have error %v, want %v", tx.Type(), err, ErrTrezorCeloTxUnsupported)
+		}
+	}
+	if device.Len() != 0 {
+		t.Errorf("unexpected data sent to device: %x", device.Bytes())
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/tests"
+	"github.com/ethereum/go-ethereum/tests/celo"
+	"github.com/urfave/cli/v2"
+)
+
+var celoTestCommand = &cli.Command{
+	Action:    celoTestCmd,
+	Name:      "celotest",
+	Usage:     "Executes the given Celo tests. Filenames can be fed via standard input (batch mode) or as an argument (one-off execution).",
+	ArgsUsage: "<file>",
+}
+
+func celoTestCmd(ctx *cli.Context) error {
+	// Configure the EVM logger
+	config := &logger.Config{
+		EnableMemory:     !ctx.Bool(DisableMemoryFlag.Name),
+		DisableStack:     ctx.Bool(DisableStackFlag.Name),
+		DisableStorage:   ctx.Bool(DisableStorageFlag.Name),
+		EnableReturnData: !ctx.Bool(DisableReturnDataFlag.Name),
+	}
+	var cfg vm.Config
+	switch {
+	case ctx.Bool(MachineFlag.Name):
+		cfg.Tracer = logger.NewJSONLogger(config, os.Stderr)
+
+	case ctx.Bool(DebugFlag.Name):
+		cfg.Tracer = logger.NewStructLogger(config).Hooks()
+	}
+	// Load the test content from the input file
+	if len(ctx.Args().First()) != 0 {
+		return runCeloTest(ctx.Args().First(), cfg, ctx.Bool(DumpFlag.Name))
+	}
+	// Read filenames from stdin and execute back-to-back
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		fname := scanner.Text()
+		if len(fname) == 0 {
+			return nil
+		}
+		if err := runCeloTest(fname, cfg, ctx.Bool(DumpFlag.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCeloTest loads the Celo tests given by fname, and executes them.
+func runCeloTest(fname string, cfg vm.Config, dump bool) error {
+	src, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+	var testsByName map[string]*celo.CeloTest
+	if err := json.Unmarshal(src, &testsByName); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(testsByName))
+	for name := range testsByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Iterate over all the tests, run them and aggregate the results
+	results := make([]StatetestResult, 0, len(testsByName))
+	for _, name := range names {
+		test := testsByName[name]
+		result := &StatetestResult{Name: name, Fork: test.Fork(), Pass: true}
+		test.Run(cfg, rawdb.HashScheme, func(err error, tstate *tests.StateTestState) {
+			if tstate.StateDB != nil {
+				root := tstate.StateDB.IntermediateRoot(false)
+				result.Root = &root
+				fmt.Fprintf(os.Stderr, "{\"stateRoot\": \"%#x\"}\n", root)
+				if dump { // Dump any state to aid debugging
+					cpy, _ := state.New(root, tstate.StateDB.Database(), nil)
+					dump := cpy.RawDump(nil)
+					result.State = &dump
+				}
+			}
+			if err != nil {
+				// Test failed, mark as so
+				result.Pass, result.Error = false, err.Error()
+			}
+		})
+		results = append(results, *result)
+	}
+	out, _ := json.MarshalIndent(results, "", "  ")
+	fmt.Println(string(out))
+	return nil
+}
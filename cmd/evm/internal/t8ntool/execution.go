@@ -23,10 +23,12 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/exchange"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/contracts"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -95,6 +97,7 @@ type stEnv struct {
 	ParentExcessBlobGas   *uint64                             `json:"parentExcessBlobGas,omitempty"`
 	ParentBlobGasUsed     *uint64                             `json:"parentBlobGasUsed,omitempty"`
 	ParentBeaconBlockRoot *common.Hash                        `json:"parentBeaconBlockRoot"`
+	FeeCurrencyContext    *common.FeeCurrencyContext          `json:"feeCurrencyContext,omitempty"`
 }
 
 type stEnvMarshaling struct {
@@ -196,6 +199,18 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		evm := vm.NewEVM(vmContext, vm.TxContext{}, statedb, chainConfig, vmConfig)
 		core.ProcessBeaconBlockRoot(*beaconRoot, evm, statedb)
 	}
+	// Celo fee-currency transactions need the exchange rates and intrinsic gas
+	// costs of the registered fee currencies. If they are not given in the env,
+	// read them from the FeeCurrencyDirectory in the prestate.
+	if pre.Env.FeeCurrencyContext != nil {
+		vmContext.FeeCurrencyContext = *pre.Env.FeeCurrencyContext
+	} else if chainConfig.IsCel2(pre.Env.Timestamp) {
+		feeCurrencyContext, err := contracts.GetFeeCurrencyContext(&contracts.CeloBackend{ChainConfig: chainConfig, State: statedb})
+		if err != nil {
+			log.Warn("Could not read fee currency context from prestate", "err", err)
+		}
+		vmContext.FeeCurrencyContext = feeCurrencyContext
+	}
 
 	for i := 0; txIt.Next(); i++ {
 		tx, err := txIt.Tx()
@@ -210,11 +225,6 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 			rejectedTxs = append(rejectedTxs, &rejectedTx{i, errMsg})
 			continue
 		}
-		// NOTE: we can't provide exchange rates
-		// for fee-currencies here, since those are dynamically changing
-		// based on the oracle's exchange rates.
-		// When a Celo transaction with specified fee-currency is validated with this tool,
-		// this will thus result in a ErrUnregisteredFeeCurrency error for now.
 		msg, err := core.TransactionToMessage(tx, signer, pre.Env.BaseFee, vmContext.FeeCurrencyContext.ExchangeRates)
 		if err != nil {
 			log.Warn("rejected tx", "index", i, "hash", tx.Hash(), "error", err)
@@ -293,6 +303,16 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 			}
 			receipt.TxHash = tx.Hash()
 			receipt.GasUsed = msgResult.UsedGas
+			if tx.Type() == types.CeloDynamicFeeTxV2Type {
+				// The base fee is part of the receipt, denominated in the fee currency.
+				receipt.BaseFee = new(big.Int).Set(vmContext.BaseFee)
+				if msg.FeeCurrency != nil {
+					receipt.BaseFee, err = exchange.ConvertCeloToCurrency(vmContext.FeeCurrencyContext.ExchangeRates, msg.FeeCurrency, vmContext.BaseFee)
+					if err != nil {
+						return nil, nil, nil, err
+					}
+				}
+			}
 
 			// If the transaction created a contract, store the creation address in the receipt.
 			if msg.To == nil {
@@ -105,6 +105,10 @@ var (
 			"The '.rlp' format is identical to the output.body format.",
 		Value: "txs.json",
 	}
+	InputFeeCurrencyContextFlag = &cli.StringFlag{
+		Name:  "input.feecurrencycontext",
+		Usage: "File name of where to find the exchange rates and intrinsic gas costs of the Celo fee currencies.",
+	}
 	InputHeaderFlag = &cli.StringFlag{
 		Name:  "input.header",
 		Usage: "`stdin` or file name of where to find the block header to use.",
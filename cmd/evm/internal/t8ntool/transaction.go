@@ -106,6 +106,16 @@ func Transaction(ctx *cli.Context) error {
 			return NewError(ErrorIO, errors.New("only rlp supported"))
 		}
 	}
+	// Load the fee currency context, if any, for Celo fee-currency transactions
+	var feeCurrencyContext common.FeeCurrencyContext
+	if inputData.Env != nil && inputData.Env.FeeCurrencyContext != nil {
+		feeCurrencyContext = *inputData.Env.FeeCurrencyContext
+	}
+	if fcStr := ctx.String(InputFeeCurrencyContextFlag.Name); fcStr != "" {
+		if err := readFile(fcStr, "fee currency context", &feeCurrencyContext); err != nil {
+			return err
+		}
+	}
 	signer := types.MakeSigner(chainConfig, new(big.Int), 0)
 	// We now have the transactions in 'body', which is supposed to be an
 	// rlp list of transactions
@@ -133,14 +143,8 @@ func Transaction(ctx *cli.Context) error {
 			r.Address = sender
 		}
 		// Check intrinsic gas
-		// NOTE: we can't provide specific intrinsic gas costs
-		// for fee-currencies here, since those are written to the
-		// FeeCurrencyDirectory contract and are chain-specific.
-		// When a Celo transaction with specified fee-currency is validated with this tool,
-		// this will thus result in a ErrUnregisteredFeeCurrency error for now.
-		var feeIntrinsic common.IntrinsicGasCosts
 		if gas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil,
-			chainConfig.IsHomestead(new(big.Int)), chainConfig.IsIstanbul(new(big.Int)), chainConfig.IsShanghai(new(big.Int), 0), tx.FeeCurrency(), feeIntrinsic); err != nil {
+			chainConfig.IsHomestead(new(big.Int)), chainConfig.IsIstanbul(new(big.Int)), chainConfig.IsShanghai(new(big.Int), 0), tx.FeeCurrency(), feeCurrencyContext.IntrinsicGasCosts); err != nil {
 			r.Error = err
 			results = append(results, r)
 			continue
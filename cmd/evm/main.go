@@ -173,6 +173,7 @@ var transactionCommand = &cli.Command{
 	Action:  t8ntool.Transaction,
 	Flags: []cli.Flag{
 		t8ntool.InputTxsFlag,
+		t8ntool.InputFeeCurrencyContextFlag,
 		t8ntool.ChainIDFlag,
 		t8ntool.ForknameFlag,
 	},
@@ -232,6 +233,8 @@ func init() {
 		runCommand,
 		blockTestCommand,
 		stateTestCommand,
+		celoTestCommand,
+		witnessCommand,
 		stateTransitionCommand,
 		transactionCommand,
 		blockBuilderCommand,
@@ -287,6 +287,22 @@ func TestT8n(t *testing.T) {
 			output: t8nOutput{alloc: true, result: true},
 			expOut: "exp.json",
 		},
+		{ // Celo fee-currency txs, fee currency context read from the prestate
+			base: "./testdata/33",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.json", "Cel2", "",
+			},
+			output: t8nOutput{alloc: true, result: true},
+			expOut: "exp.json",
+		},
+		{ // Celo fee-currency txs, fee currency context given in the env
+			base: "./testdata/33",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env-feecurrencycontext.json", "Cel2", "",
+			},
+			output: t8nOutput{alloc: true, result: true},
+			expOut: "exp2.json",
+		},
 	} {
 		args := []string{"t8n"}
 		args = append(args, tc.output.get()...)
@@ -434,8 +450,9 @@ func TestT8nTracing(t *testing.T) {
 }
 
 type t9nInput struct {
-	inTxs  string
-	stFork string
+	inTxs                string
+	inFeeCurrencyContext string
+	stFork               string
 }
 
 func (args *t9nInput) get(base string) []string {
@@ -444,6 +461,10 @@ func (args *t9nInput) get(base string) []string {
 		out = append(out, "--input.txs")
 		out = append(out, fmt.Sprintf("%v/%v", base, opt))
 	}
+	if opt := args.inFeeCurrencyContext; opt != "" {
+		out = append(out, "--input.feecurrencycontext")
+		out = append(out, fmt.Sprintf("%v/%v", base, opt))
+	}
 	if opt := args.stFork; opt != "" {
 		out = append(out, "--state.fork", opt)
 	}
@@ -508,6 +529,23 @@ func TestT9n(t *testing.T) {
 			},
 			expExitCode: t8ntool.ErrorIO,
 		},
+		{ // Celo fee-currency tx without fee currency context
+			base: "./testdata/33",
+			input: t9nInput{
+				inTxs:  "signed_txs.rlp",
+				stFork: "Cel2",
+			},
+			expOut: "exp_t9n.json",
+		},
+		{ // Celo fee-currency tx with fee currency context
+			base: "./testdata/33",
+			input: t9nInput{
+				inTxs:                "signed_txs.rlp",
+				inFeeCurrencyContext: "feecurrencycontext.json",
+				stFork:               "Cel2",
+			},
+			expOut: "exp2_t9n.json",
+		},
 	} {
 		args := []string{"t9n"}
 		args = append(args, tc.input.get(tc.base)...)
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	WitnessStateRootFlag = &cli.StringFlag{
+		Name:  "stateroot",
+		Usage: "expected state root of the block, instead of the one of the witness block header",
+	}
+	WitnessReceiptRootFlag = &cli.StringFlag{
+		Name:  "receiptroot",
+		Usage: "expected receipt root of the block, instead of the one of the witness block header",
+	}
+)
+
+var witnessCommand = &cli.Command{
+	Action:    witnessCmd,
+	Name:      "witness",
+	Usage:     "Executes a block statelessly from the given execution witness (as returned by debug_executionWitness), prints the resulting state and receipt roots and verifies them against the block header.",
+	ArgsUsage: "<file>",
+	Flags: []cli.Flag{
+		WitnessStateRootFlag,
+		WitnessReceiptRootFlag,
+	},
+}
+
+// witnessResult contains the roots computed by the stateless execution.
+type witnessResult struct {
+	Number      uint64      `json:"number"`
+	StateRoot   common.Hash `json:"stateRoot"`
+	ReceiptRoot common.Hash `json:"receiptsRoot"`
+}
+
+func witnessCmd(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return errors.New("witness file required")
+	}
+	if ctx.String(GenesisFlag.Name) == "" {
+		return fmt.Errorf("genesis file required to load the chain config (--%s)", GenesisFlag.Name)
+	}
+	config := readGenesis(ctx.String(GenesisFlag.Name)).Config
+
+	src, err := os.ReadFile(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	witness := new(stateless.Witness)
+	if err := json.Unmarshal(src, witness); err != nil {
+		return fmt.Errorf("invalid witness: %v", err)
+	}
+	receiptRoot, stateRoot, err := core.ExecuteStateless(config, witness)
+	if err != nil {
+		return fmt.Errorf("stateless execution failed: %v", err)
+	}
+	result := witnessResult{
+		Number:      witness.Block.NumberU64(),
+		StateRoot:   stateRoot,
+		ReceiptRoot: receiptRoot,
+	}
+	out, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(out))
+
+	// Verify the roots against the block header, unless given explicitly
+	wantState, wantReceipt := witness.Block.Root(), witness.Block.ReceiptHash()
+	if ctx.IsSet(WitnessStateRootFlag.Name) {
+		wantState = common.HexToHash(ctx.String(WitnessStateRootFlag.Name))
+	}
+	if ctx.IsSet(WitnessReceiptRootFlag.Name) {
+		wantReceipt = common.HexToHash(ctx.String(WitnessReceiptRootFlag.Name))
+	}
+	if wantState == (common.Hash{}) || wantReceipt == (common.Hash{}) {
+		return fmt.Errorf("witness block header lacks the roots to verify (--%s, --%s)", WitnessStateRootFlag.Name, WitnessReceiptRootFlag.Name)
+	}
+	if stateRoot != wantState {
+		return fmt.Errorf("state root mismatch: have %x, want %x", stateRoot, wantState)
+	}
+	if receiptRoot != wantReceipt {
+		return fmt.Errorf("receipt root mismatch: have %x, want %x", receiptRoot, wantReceipt)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/urfave/cli/v2"
+)
+
+var celoGenesisCommand = &cli.Command{
+	Action:    celoGenesis,
+	Name:      "celo-genesis",
+	Usage:     "Generates a Celo devnet genesis JSON from a declarative config",
+	ArgsUsage: "<configPath>",
+	Description: `
+The celo-genesis command generates the genesis of a Celo devnet and prints it
+to stdout, ready to be used with 'geth init'.
+
+The config file is a JSON document describing the chain config, the funded
+accounts, the owner of the FeeCurrencyDirectory and the fee currencies to
+register in it, e.g.
+
+{
+  "config": { "chainId": 1337, ... },
+  "eip1559BaseFeeFloor": 25000000000,
+  "feeCurrencyDirectoryOwner": "0x42cf1bbc38baaa3c4898ce8790e21ed2738c6a4a",
+  "accounts": { "0x42cf1bbc38baaa3c4898ce8790e21ed2738c6a4a": "100000000000000000000" },
+  "feeCurrencies": [{
+    "address": "0x000000000000000000000000000000000000ce16",
+    "rateNumerator": "2",
+    "rateDenominator": "1",
+    "intrinsicGas": 50000,
+    "balances": { "0x42cf1bbc38baaa3c4898ce8790e21ed2738c6a4a": "100000000000000000000" }
+  }]
+}
+
+The exchange rate is given as the amount of fee currency per unit of CELO. If
+no oracle address is given for a fee currency, a mock oracle is deployed at an
+address derived from its position in the list. The storage layout of the token,
+oracle and directory contracts is computed from the config.`,
+}
+
+func celoGenesis(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("need celo genesis config file as the only argument")
+	}
+	file, err := os.Open(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Failed to read celo genesis config: %v", err)
+	}
+	defer file.Close()
+
+	config := new(core.CeloGenesisConfig)
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		utils.Fatalf("invalid celo genesis config: %v", err)
+	}
+	genesis, err := config.ToGenesis()
+	if err != nil {
+		utils.Fatalf("could not generate genesis: %v", err)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(genesis); err != nil {
+		utils.Fatalf("could not encode genesis: %s", err)
+	}
+	return nil
+}
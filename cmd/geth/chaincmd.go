@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"sort"
 	"strconv"
 	"sync/atomic"
 	"time"
@@ -167,6 +168,23 @@ The import-preimages command imports hash preimages from an RLP encoded stream.
 It's deprecated, please use "geth db import" instead.
 `,
 	}
+	pruneHistoryCommand = &cli.Command{
+		Action: pruneHistory,
+		Name:   "prune-history",
+		Usage:  "Prune block bodies and receipts of the chain history",
+		Flags: flags.Merge([]cli.Flag{
+			utils.HistoryRetainFlag,
+		}, utils.DatabaseFlags),
+		Description: `
+The prune-history command removes the block bodies and receipts of old blocks
+from the ancient store, keeping their headers. By default the history before
+the Celo L2 migration is pruned, --history.retain prunes all but the given
+number of recent blocks instead. Requests for pruned data are refused by the
+RPC server afterwards.
+
+This is a destructive action, the pruned history can only be restored by
+resyncing the node.`,
+	}
 
 	dumpCommand = &cli.Command{
 		Action:    dump,
@@ -598,6 +616,75 @@ func dump(ctx *cli.Context) error {
 	return nil
 }
 
+func pruneHistory(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	head := rawdb.ReadHeadHeader(db)
+	if head == nil {
+		return errors.New("no chain head found")
+	}
+	var target uint64
+	if ctx.IsSet(utils.HistoryRetainFlag.Name) {
+		retain := ctx.Uint64(utils.HistoryRetainFlag.Name)
+		if retain == 0 {
+			return errors.New("nothing to prune when retaining the entire chain")
+		}
+		if head.Number.Uint64() < retain {
+			return fmt.Errorf("chain of %d blocks is shorter than the retained history", head.Number.Uint64()+1)
+		}
+		target = head.Number.Uint64() - retain + 1
+	} else {
+		config := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0))
+		if config == nil {
+			return errors.New("no chain config found")
+		}
+		if !config.IsCel2(head.Time) {
+			return errors.New("chain is not migrated to Celo L2 yet")
+		}
+		// Find the first L2 block, all blocks before it are L1 history
+		target = uint64(sort.Search(int(head.Number.Uint64()), func(i int) bool {
+			header := rawdb.ReadHeader(db, rawdb.ReadCanonicalHash(db, uint64(i)), uint64(i))
+			return header == nil || config.IsCel2(header.Time)
+		}))
+		if target == 0 {
+			return errors.New("no pre-migration history to prune")
+		}
+	}
+	// Only the history moved to the ancient store can be pruned
+	frozen, err := db.Ancients()
+	if err != nil {
+		return err
+	}
+	if target > frozen {
+		log.Warn("Limiting pruning to the ancient store", "target", target, "frozen", frozen)
+		target = frozen
+	}
+	tail, err := db.Tail()
+	if err != nil {
+		return err
+	}
+	if target <= tail {
+		log.Info("Chain history already pruned", "tail", tail)
+		return nil
+	}
+	start := time.Now()
+	log.Info("Pruning chain history", "from", tail, "to", target)
+
+	// Remove the transaction indexes first, they are resolved via the bodies
+	if txTail := rawdb.ReadTxIndexTail(db); txTail != nil && *txTail < target {
+		rawdb.UnindexTransactions(db, *txTail, target, nil, true)
+	}
+	if _, err := db.TruncateTail(target); err != nil {
+		return fmt.Errorf("failed to prune chain history: %v", err)
+	}
+	log.Info("Pruned chain history", "tail", target, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
 // hashish returns true for strings that look like hashes.
 func hashish(x string) bool {
 	_, err := strconv.Atoi(x)
@@ -88,7 +88,8 @@ var tomlSettings = toml.Config{
 }
 
 type ethstatsConfig struct {
-	URL string `toml:",omitempty"`
+	URL  string `toml:",omitempty"`
+	Celo bool   `toml:",omitempty"`
 }
 
 type gethConfig struct {
@@ -162,6 +163,9 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, gethConfig) {
 	if ctx.IsSet(utils.EthStatsURLFlag.Name) {
 		cfg.Ethstats.URL = ctx.String(utils.EthStatsURLFlag.Name)
 	}
+	if ctx.IsSet(utils.EthStatsCeloFlag.Name) {
+		cfg.Ethstats.Celo = ctx.Bool(utils.EthStatsCeloFlag.Name)
+	}
 	applyMetricConfig(ctx, &cfg)
 
 	return stack, cfg
@@ -240,7 +244,7 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 	}
 	// Add the Ethereum Stats daemon if requested.
 	if cfg.Ethstats.URL != "" {
-		utils.RegisterEthStatsService(stack, backend, cfg.Ethstats.URL)
+		utils.RegisterEthStatsService(stack, backend, cfg.Ethstats.URL, cfg.Ethstats.Celo)
 	}
 	// Configure full-sync tester service if requested
 	if ctx.IsSet(utils.SyncTargetFlag.Name) {
@@ -97,6 +97,8 @@ var (
 		utils.TxLookupLimitFlag, // deprecated
 		utils.TransactionHistoryFlag,
 		utils.StateHistoryFlag,
+		utils.LogIndexFlag,
+		utils.HistoryRetainFlag,
 		utils.LightServeFlag,    // deprecated
 		utils.LightIngressFlag,  // deprecated
 		utils.LightEgressFlag,   // deprecated
@@ -134,6 +136,15 @@ var (
 		utils.MinerNewPayloadTimeoutFlag, // deprecated
 		utils.CeloFeeCurrencyDefault,
 		utils.CeloFeeCurrencyLimits,
+		utils.CeloFeeCurrencyReservedDefault,
+		utils.CeloFeeCurrencyReserved,
+		utils.CeloFeeCurrencyHealthWindow,
+		utils.CeloFeeCurrencyHealthMinSamples,
+		utils.CeloFeeCurrencyHealthBlockThreshold,
+		utils.CeloFeeCurrencyHealthRecoverThreshold,
+		utils.CeloFeeCurrencyHealthOverrunWeight,
+		utils.CeloFeeCurrencyHealthBlockTimeout,
+		utils.CeloFeeCurrencyHealthMaxBlockTimeout,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV4Flag,
@@ -151,6 +162,7 @@ var (
 		utils.VMTraceJsonConfigFlag,
 		utils.NetworkIdFlag,
 		utils.EthStatsURLFlag,
+		utils.EthStatsCeloFlag,
 		utils.NoCompactionFlag,
 		utils.GpoBlocksFlag,
 		utils.GpoPercentileFlag,
@@ -164,6 +176,8 @@ var (
 		utils.RollupComputePendingBlock,
 		utils.RollupHaltOnIncompatibleProtocolVersionFlag,
 		utils.RollupSuperchainUpgradesFlag,
+		utils.RollupSequencerTxConditionalEnabledFlag,
+		utils.RollupSequencerTxConditionalCostRateLimitFlag,
 		configFileFlag,
 		utils.LogDebugFlag,
 		utils.LogBacktraceAtFlag,
@@ -205,6 +219,7 @@ var (
 		utils.RPCGlobalGasCapFlag,
 		utils.RPCGlobalEVMTimeoutFlag,
 		utils.RPCGlobalTxFeeCapFlag,
+		utils.RPCTraceCacheFlag,
 		utils.AllowUnprotectedTxs,
 		utils.BatchRequestLimit,
 		utils.BatchResponseMaxSize,
@@ -242,6 +257,7 @@ func init() {
 		exportHistoryCommand,
 		importPreimagesCommand,
 		removedbCommand,
+		pruneHistoryCommand,
 		dumpCommand,
 		dumpGenesisCommand,
 		// See accountcmd.go:
@@ -265,6 +281,8 @@ func init() {
 		snapshotCommand,
 		// See verkle.go
 		verkleCommand,
+		// See celogenesiscmd.go
+		celoGenesisCommand,
 	}
 	if logTestCommand != nil {
 		app.Commands = append(app.Commands, logTestCommand)
@@ -58,6 +58,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb/remotedb"
 	"github.com/ethereum/go-ethereum/ethstats"
 	"github.com/ethereum/go-ethereum/graphql"
+	"github.com/ethereum/go-ethereum/internal/celoapi"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/log"
@@ -328,6 +329,16 @@ var (
 		Value:    ethconfig.Defaults.TransactionHistory,
 		Category: flags.StateCategory,
 	}
+	LogIndexFlag = &cli.BoolFlag{
+		Name:     "history.logs.index",
+		Usage:    "Maintain a log index to speed up log searches, in addition to the bloom bits",
+		Category: flags.StateCategory,
+	}
+	HistoryRetainFlag = &cli.Uint64Flag{
+		Name:     "history.retain",
+		Usage:    "Number of recent blocks to retain block bodies and receipts for, older ones are pruned from the ancient store (0 = entire chain)",
+		Category: flags.StateCategory,
+	}
 	// Beacon client light sync settings
 	BeaconApiFlag = &cli.StringSliceFlag{
 		Name:     "beacon.api",
@@ -574,6 +585,59 @@ var (
 		Usage:    "Comma separated currency address-to-block percentage mappings (<address>=<fraction>)",
 		Category: flags.MinerCategory,
 	}
+	CeloFeeCurrencyReservedDefault = &cli.Float64Flag{
+		Name:     "celo.feecurrency.reserved.default",
+		Usage:    "Default fraction of block gas limit reserved for TXs paid with each whitelisted alternative currency, if there are any pending",
+		Value:    ethconfig.Defaults.Miner.FeeCurrencyReservedDefault,
+		Category: flags.MinerCategory,
+	}
+	CeloFeeCurrencyReserved = &cli.StringFlag{
+		Name:     "celo.feecurrency.reserved",
+		Usage:    "Comma separated currency address-to-reserved block percentage mappings (<address>=<fraction>)",
+		Category: flags.MinerCategory,
+	}
+	CeloFeeCurrencyHealthWindow = &cli.Uint64Flag{
+		Name:     "celo.feecurrency.health.window",
+		Usage:    "Sliding window (in seconds) over which the debit/credit outcomes of fee currencies are scored",
+		Value:    ethconfig.Defaults.Miner.FeeCurrencyHealth.Window,
+		Category: flags.MinerCategory,
+	}
+	CeloFeeCurrencyHealthMinSamples = &cli.Uint64Flag{
+		Name:     "celo.feecurrency.health.minsamples",
+		Usage:    "Minimum number of scored transactions before a fee currency can be blocked",
+		Value:    ethconfig.Defaults.Miner.FeeCurrencyHealth.MinSamples,
+		Category: flags.MinerCategory,
+	}
+	CeloFeeCurrencyHealthBlockThreshold = &cli.Float64Flag{
+		Name:     "celo.feecurrency.health.blockthreshold",
+		Usage:    "Weighted failure rate above which a fee currency is temporarily blocked",
+		Value:    ethconfig.Defaults.Miner.FeeCurrencyHealth.BlockThreshold,
+		Category: flags.MinerCategory,
+	}
+	CeloFeeCurrencyHealthRecoverThreshold = &cli.Float64Flag{
+		Name:     "celo.feecurrency.health.recoverthreshold",
+		Usage:    "Weighted failure rate below which a fee currency's blocking backoff is reset",
+		Value:    ethconfig.Defaults.Miner.FeeCurrencyHealth.RecoverThreshold,
+		Category: flags.MinerCategory,
+	}
+	CeloFeeCurrencyHealthOverrunWeight = &cli.Float64Flag{
+		Name:     "celo.feecurrency.health.overrunweight",
+		Usage:    "Weight of a debit/credit gas overrun relative to a failure when scoring fee currencies",
+		Value:    ethconfig.Defaults.Miner.FeeCurrencyHealth.OverrunWeight,
+		Category: flags.MinerCategory,
+	}
+	CeloFeeCurrencyHealthBlockTimeout = &cli.Uint64Flag{
+		Name:     "celo.feecurrency.health.blocktimeout",
+		Usage:    "Time (in seconds) an unhealthy fee currency is first blocked for, doubled on each consecutive block",
+		Value:    ethconfig.Defaults.Miner.FeeCurrencyHealth.BlockTimeout,
+		Category: flags.MinerCategory,
+	}
+	CeloFeeCurrencyHealthMaxBlockTimeout = &cli.Uint64Flag{
+		Name:     "celo.feecurrency.health.maxblocktimeout",
+		Usage:    "Maximum time (in seconds) an unhealthy fee currency is blocked for",
+		Value:    ethconfig.Defaults.Miner.FeeCurrencyHealth.MaxBlockTimeout,
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -635,6 +699,11 @@ var (
 		Value:    ethconfig.Defaults.RPCTxFeeCap,
 		Category: flags.APICategory,
 	}
+	RPCTraceCacheFlag = &cli.IntFlag{
+		Name:     "rpc.tracecache",
+		Usage:    "Megabytes of disk space used to cache debug_traceBlock results (0 = disabled)",
+		Category: flags.APICategory,
+	}
 	// Authenticated RPC HTTP settings
 	AuthListenFlag = &cli.StringFlag{
 		Name:     "authrpc.addr",
@@ -666,6 +735,11 @@ var (
 		Usage:    "Reporting URL of a ethstats service (nodename:secret@host:port)",
 		Category: flags.MetricsCategory,
 	}
+	EthStatsCeloFlag = &cli.BoolFlag{
+		Name:     "ethstats.celo",
+		Usage:    "Report Celo fee currency data (exchange rates, gas per fee currency, blocked fee currencies) to the ethstats service",
+		Category: flags.MetricsCategory,
+	}
 	NoCompactionFlag = &cli.BoolFlag{
 		Name:     "nocompaction",
 		Usage:    "Disables db compaction after import",
@@ -985,6 +1059,17 @@ var (
 		Usage:    "Opt-in option to halt on incompatible protocol version requirements of the given level (major/minor/patch/none), as signaled through the Engine API by the rollup node",
 		Category: flags.RollupCategory,
 	}
+	RollupSequencerTxConditionalEnabledFlag = &cli.BoolFlag{
+		Name:     "rollup.sequencertxconditionalenabled",
+		Usage:    "Serve the eth_sendRawTransactionConditional endpoint and apply the transaction preconditions during block building",
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerTxConditionalCostRateLimitFlag = &cli.IntFlag{
+		Name:     "rollup.sequencertxconditionalcostratelimit",
+		Usage:    "Maximum cost per second of the preconditions of eth_sendRawTransactionConditional requests",
+		Value:    ethconfig.Defaults.RollupSequencerTxConditionalCostRateLimit,
+		Category: flags.RollupCategory,
+	}
 	RollupSuperchainUpgradesFlag = &cli.BoolFlag{
 		Name:     "rollup.superchain-upgrades",
 		Aliases:  []string{"beta.rollup.superchain-upgrades"},
@@ -1746,26 +1831,59 @@ func setCeloMiner(ctx *cli.Context, cfg *miner.Config, networkId uint64) {
 	cfg.FeeCurrencyLimits = defaultLimits
 
 	if ctx.IsSet(CeloFeeCurrencyLimits.Name) {
-		feeCurrencyLimits := ctx.String(CeloFeeCurrencyLimits.Name)
+		for address, fraction := range parseFeeCurrencyFractions(ctx.String(CeloFeeCurrencyLimits.Name)) {
+			cfg.FeeCurrencyLimits[address] = fraction
+		}
+	}
 
-		for _, entry := range strings.Split(feeCurrencyLimits, ",") {
-			parts := strings.Split(entry, "=")
-			if len(parts) != 2 {
-				Fatalf("Invalid fee currency limits entry: %s", entry)
-			}
-			var address common.Address
-			if err := address.UnmarshalText([]byte(parts[0])); err != nil {
-				Fatalf("Invalid fee currency address hash %s: %v", parts[0], err)
-			}
+	cfg.FeeCurrencyReservedDefault = ctx.Float64(CeloFeeCurrencyReservedDefault.Name)
+	if ctx.IsSet(CeloFeeCurrencyReserved.Name) {
+		cfg.FeeCurrencyReserved = parseFeeCurrencyFractions(ctx.String(CeloFeeCurrencyReserved.Name))
+	}
 
-			fraction, err := strconv.ParseFloat(parts[1], 64)
-			if err != nil {
-				Fatalf("Invalid block limit fraction %s: %v", parts[1], err)
-			}
+	if ctx.IsSet(CeloFeeCurrencyHealthWindow.Name) {
+		cfg.FeeCurrencyHealth.Window = ctx.Uint64(CeloFeeCurrencyHealthWindow.Name)
+	}
+	if ctx.IsSet(CeloFeeCurrencyHealthMinSamples.Name) {
+		cfg.FeeCurrencyHealth.MinSamples = ctx.Uint64(CeloFeeCurrencyHealthMinSamples.Name)
+	}
+	if ctx.IsSet(CeloFeeCurrencyHealthBlockThreshold.Name) {
+		cfg.FeeCurrencyHealth.BlockThreshold = ctx.Float64(CeloFeeCurrencyHealthBlockThreshold.Name)
+	}
+	if ctx.IsSet(CeloFeeCurrencyHealthRecoverThreshold.Name) {
+		cfg.FeeCurrencyHealth.RecoverThreshold = ctx.Float64(CeloFeeCurrencyHealthRecoverThreshold.Name)
+	}
+	if ctx.IsSet(CeloFeeCurrencyHealthOverrunWeight.Name) {
+		cfg.FeeCurrencyHealth.OverrunWeight = ctx.Float64(CeloFeeCurrencyHealthOverrunWeight.Name)
+	}
+	if ctx.IsSet(CeloFeeCurrencyHealthBlockTimeout.Name) {
+		cfg.FeeCurrencyHealth.BlockTimeout = ctx.Uint64(CeloFeeCurrencyHealthBlockTimeout.Name)
+	}
+	if ctx.IsSet(CeloFeeCurrencyHealthMaxBlockTimeout.Name) {
+		cfg.FeeCurrencyHealth.MaxBlockTimeout = ctx.Uint64(CeloFeeCurrencyHealthMaxBlockTimeout.Name)
+	}
+}
 
-			cfg.FeeCurrencyLimits[address] = fraction
+// parseFeeCurrencyFractions parses comma separated <address>=<fraction> mappings.
+func parseFeeCurrencyFractions(mappings string) map[common.Address]float64 {
+	fractions := make(map[common.Address]float64)
+	for _, entry := range strings.Split(mappings, ",") {
+		parts := strings.Split(entry, "=")
+		if len(parts) != 2 {
+			Fatalf("Invalid fee currency mapping entry: %s", entry)
+		}
+		var address common.Address
+		if err := address.UnmarshalText([]byte(parts[0])); err != nil {
+			Fatalf("Invalid fee currency address hash %s: %v", parts[0], err)
+		}
+
+		fraction, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			Fatalf("Invalid block limit fraction %s: %v", parts[1], err)
 		}
+		fractions[address] = fraction
 	}
+	return fractions
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1906,6 +2024,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(StateHistoryFlag.Name) {
 		cfg.StateHistory = ctx.Uint64(StateHistoryFlag.Name)
 	}
+	if ctx.IsSet(LogIndexFlag.Name) {
+		cfg.LogIndex = ctx.Bool(LogIndexFlag.Name)
+	}
+	if ctx.IsSet(HistoryRetainFlag.Name) {
+		cfg.HistoryRetain = ctx.Uint64(HistoryRetainFlag.Name)
+	}
 	if ctx.IsSet(StateSchemeFlag.Name) {
 		cfg.StateScheme = ctx.String(StateSchemeFlag.Name)
 	}
@@ -1980,6 +2104,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.Float64(RPCGlobalTxFeeCapFlag.Name)
 	}
+	if ctx.IsSet(RPCTraceCacheFlag.Name) {
+		cfg.TraceCache = ctx.Int(RPCTraceCacheFlag.Name)
+	}
 	if ctx.IsSet(NoDiscoverFlag.Name) {
 		cfg.EthDiscoveryURLs, cfg.SnapDiscoveryURLs = []string{}, []string{}
 	} else if ctx.IsSet(DNSDiscoveryFlag.Name) {
@@ -2004,6 +2131,10 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	cfg.RollupDisableTxPoolAdmission = cfg.RollupSequencerHTTP != "" && !ctx.Bool(RollupEnableTxPoolAdmissionFlag.Name)
 	cfg.RollupHaltOnIncompatibleProtocolVersion = ctx.String(RollupHaltOnIncompatibleProtocolVersionFlag.Name)
 	cfg.ApplySuperchainUpgrades = ctx.Bool(RollupSuperchainUpgradesFlag.Name)
+	cfg.RollupSequencerTxConditionalEnabled = ctx.Bool(RollupSequencerTxConditionalEnabledFlag.Name)
+	if ctx.IsSet(RollupSequencerTxConditionalCostRateLimitFlag.Name) {
+		cfg.RollupSequencerTxConditionalCostRateLimit = ctx.Int(RollupSequencerTxConditionalCostRateLimitFlag.Name)
+	}
 	// Override any default configs for hard coded networks.
 	switch {
 	case ctx.Bool(MainnetFlag.Name):
@@ -2167,7 +2298,11 @@ func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (ethapi.Backend
 }
 
 // RegisterEthStatsService configures the Ethereum Stats daemon and adds it to the node.
-func RegisterEthStatsService(stack *node.Node, backend ethapi.Backend, url string) {
+// If celo is set, Celo fee currency data is included in the reports.
+func RegisterEthStatsService(stack *node.Node, backend ethapi.Backend, url string, celo bool) {
+	if celo {
+		backend = celoapi.NewCeloAPIBackend(backend)
+	}
 	if err := ethstats.New(stack, backend, backend.Engine(), url); err != nil {
 		Fatalf("Failed to register the Ethereum Stats service: %v", err)
 	}
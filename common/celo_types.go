@@ -20,7 +20,23 @@ type FeeCurrencyContext struct {
 	IntrinsicGasCosts IntrinsicGasCosts
 }
 
-// Only used in tracer tests
+// MarshalJSON encodes the exchange rates as [numerator, denominator] pairs, the
+// inverse of UnmarshalJSON.
+func (fc FeeCurrencyContext) MarshalJSON() ([]byte, error) {
+	var raw struct {
+		ExchangeRates     map[Address][]json.Number `json:"exchangeRates"`
+		IntrinsicGasCosts map[Address]uint64        `json:"intrinsicGasCosts"`
+	}
+	raw.ExchangeRates = make(map[Address][]json.Number, len(fc.ExchangeRates))
+	for addr, rate := range fc.ExchangeRates {
+		raw.ExchangeRates[addr] = []json.Number{json.Number(rate.Num().String()), json.Number(rate.Denom().String())}
+	}
+	raw.IntrinsicGasCosts = fc.IntrinsicGasCosts
+	return json.Marshal(&raw)
+}
+
+// UnmarshalJSON decodes the exchange rates from [numerator, denominator] pairs.
+// Used by the tracer tests and the evm t8n tool.
 func (fc *FeeCurrencyContext) UnmarshalJSON(data []byte) error {
 	var raw struct {
 		ExchangeRates     map[Address][]json.Number `json:"exchangeRates"`
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"encoding/json"
 	"math/big"
 	"testing"
 )
@@ -45,3 +46,26 @@ func TestIsCurrencyAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestFeeCurrencyContextJSON(t *testing.T) {
+	want := FeeCurrencyContext{
+		ExchangeRates:     exchangeRates,
+		IntrinsicGasCosts: IntrinsicGasCosts{currA: 50000, currB: 60000},
+	}
+	enc, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have FeeCurrencyContext
+	if err := json.Unmarshal(enc, &have); err != nil {
+		t.Fatal(err)
+	}
+	for addr, rate := range want.ExchangeRates {
+		if have.ExchangeRates[addr] == nil || have.ExchangeRates[addr].Cmp(rate) != 0 {
+			t.Errorf("exchange rate mismatch for %v: have %v, want %v", addr, have.ExchangeRates[addr], rate)
+		}
+		if have.IntrinsicGasCosts[addr] != want.IntrinsicGasCosts[addr] {
+			t.Errorf("intrinsic gas mismatch for %v: have %d, want %d", addr, have.IntrinsicGasCosts[addr], want.IntrinsicGasCosts[addr])
+		}
+	}
+}
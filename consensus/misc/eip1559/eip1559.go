@@ -62,7 +62,7 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, time uint64)
 	defer func() {
 		// If the base fee response is below the floor, intercept the return and return the floor instead.
 		if config.Celo != nil {
-			response = math.BigMax(response, new(big.Int).SetUint64(config.Celo.EIP1559BaseFeeFloor))
+			response = math.BigMax(response, new(big.Int).SetUint64(config.BaseFeeFloor(time)))
 		}
 	}()
 
@@ -75,7 +75,7 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, time uint64)
 		return new(big.Int).SetUint64(params.InitialBaseFee)
 	}
 
-	parentGasTarget := parent.GasLimit / config.ElasticityMultiplier()
+	parentGasTarget := parent.GasLimit / config.BaseFeeElasticityMultiplier(time)
 	// If the parent gasUsed is the same as the target, the baseFee remains unchanged.
 	if parent.GasUsed == parentGasTarget {
 		return new(big.Int).Set(parent.BaseFee)
@@ -212,3 +212,37 @@ func TestCalcBaseFeeCeloFloor(t *testing.T) {
 		}
 	}
 }
+
+// TestCalcBaseFeeCeloSchedule checks that scheduled EIP-1559 parameters apply
+// from their activation time on.
+func TestCalcBaseFeeCeloSchedule(t *testing.T) {
+	config := celoConfig()
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 2, EIP1559Denominator: 8}
+	config.Celo.EIP1559Schedule = []params.CeloEIP1559Config{
+		{Time: 20, BaseFeeFloor: 2 * params.InitialBaseFee, Elasticity: 2, Denominator: 8},
+		{Time: 30, BaseFeeFloor: params.InitialBaseFee / 2, Elasticity: 4, Denominator: 50},
+	}
+	tests := []struct {
+		time            uint64
+		parentGasUsed   uint64
+		expectedBaseFee int64
+	}{
+		{12, 7000000, params.InitialBaseFee},     // initial floor
+		{20, 7000000, 2 * params.InitialBaseFee}, // raised floor
+		{29, 11000000, 2 * params.InitialBaseFee},
+		{30, 10000000, 1020000000}, // usage above the lowered target, larger denominator
+		{31, 5000000, params.InitialBaseFee},
+	}
+	for i, test := range tests {
+		parent := &types.Header{
+			Number:   common.Big32,
+			GasLimit: 20000000,
+			GasUsed:  test.parentGasUsed,
+			BaseFee:  big.NewInt(params.InitialBaseFee),
+			Time:     test.time - 1,
+		}
+		if have, want := CalcBaseFee(config, parent, test.time), big.NewInt(test.expectedBaseFee); have.Cmp(want) != 0 {
+			t.Errorf("test %d: have %d  want %d, ", i, have, want)
+		}
+	}
+}
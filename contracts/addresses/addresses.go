@@ -10,5 +10,18 @@ var (
 	CeloTokenAlfajoresAddress  = common.HexToAddress("0xF194afDf50B03e69Bd7D057c1Aa9e10c9954E4C9")
 	FeeHandlerAlfajoresAddress = common.HexToAddress("0xEAaFf71AB67B5d0eF34ba62Ea06Ac3d3E2dAAA38")
 
+	CeloTokenBaklavaAddress = common.HexToAddress("0xdDc9bE57f553fe75752D61606B94CBD7e0264eF8")
+
+	MainnetChainID   uint64 = 42220
 	AlfajoresChainID uint64 = 44787
+	BaklavaChainID   uint64 = 62320
 )
+
+// CeloTokenAddresses holds the default CELO token address for known Celo
+// networks, keyed by chain ID. Chains not listed here use CeloTokenAddress
+// unless their chain config specifies a different address.
+var CeloTokenAddresses = map[uint64]common.Address{
+	MainnetChainID:   CeloTokenAddress,
+	AlfajoresChainID: CeloTokenAlfajoresAddress,
+	BaklavaChainID:   CeloTokenBaklavaAddress,
+}
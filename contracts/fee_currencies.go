@@ -88,7 +88,7 @@ func DebitFees(evm *vm.EVM, feeCurrency *common.Address, address common.Address,
 	return gasUsed, err
 }
 
-// Credits fees to the respective parties
+// Credits fees to the respective parties and returns the gas used for it
 // - the base fee goes to the fee handler
 // - the transaction tip goes to the miner
 // - the l1 data fee goes the the data fee receiver, is the node runs in rollup mode
@@ -99,7 +99,7 @@ func CreditFees(
 	txSender, tipReceiver, baseFeeReceiver, l1DataFeeReceiver common.Address,
 	refund, feeTip, baseFee, l1DataFee *big.Int,
 	gasUsedDebit uint64,
-) error {
+) (uint64, error) {
 	// Hide this function from traces
 	if evm.Config.Tracer != nil && !evm.Config.Tracer.TraceDebitCredit {
 		origTracer := evm.Config.Tracer
@@ -124,7 +124,7 @@ func CreditFees(
 	}
 	maxAllowedGasForDebitAndCredit, ok := common.MaxAllowedIntrinsicGasCost(evm.Context.FeeCurrencyContext.IntrinsicGasCosts, feeCurrency)
 	if !ok {
-		return fmt.Errorf("%w: %x", exchange.ErrUnregisteredFeeCurrency, feeCurrency)
+		return 0, fmt.Errorf("%w: %x", exchange.ErrUnregisteredFeeCurrency, feeCurrency)
 	}
 
 	maxAllowedGasForCredit := maxAllowedGasForDebitAndCredit - gasUsedDebit
@@ -146,13 +146,13 @@ func CreditFees(
 		if errors.Is(err, vm.ErrOutOfGas) {
 			// This is a configuration / contract error, since
 			// the contract itself used way more gas than was expected (including grace limit)
-			return fmt.Errorf(
+			return 0, fmt.Errorf(
 				"%w: surpassed maximum allowed intrinsic gas for CreditFees() in fee-currency: %w",
 				ErrFeeCurrencyEVMCall,
 				err,
 			)
 		}
-		return fmt.Errorf(
+		return 0, fmt.Errorf(
 			"%w: CreditFees() call error: %w",
 			ErrFeeCurrencyEVMCall,
 			err,
@@ -165,7 +165,7 @@ func CreditFees(
 	intrinsicGas, ok := common.CurrencyIntrinsicGasCost(evm.Context.FeeCurrencyContext.IntrinsicGasCosts, feeCurrency)
 	if !ok {
 		// this will never happen
-		return fmt.Errorf("%w: %x", exchange.ErrUnregisteredFeeCurrency, feeCurrency)
+		return 0, fmt.Errorf("%w: %x", exchange.ErrUnregisteredFeeCurrency, feeCurrency)
 	}
 	gasUsedForDebitAndCredit := gasUsedDebit + gasUsed
 	if gasUsedForDebitAndCredit > intrinsicGas {
@@ -176,7 +176,7 @@ func CreditFees(
 			"feeCurrency", feeCurrency,
 		)
 	}
-	return err
+	return gasUsed, err
 }
 
 func GetRegisteredCurrencies(caller *abigen.FeeCurrencyDirectoryCaller) ([]common.Address, error) {
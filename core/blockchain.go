@@ -142,6 +142,7 @@ type CacheConfig struct {
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
 	Preimages           bool          // Whether to store preimage of trie key to the disk
 	StateHistory        uint64        // Number of blocks from head whose state histories are reserved.
+	ChainHistory        uint64        // Number of blocks from head whose bodies and receipts are reserved, 0 for the entire chain
 	StateScheme         string        // Scheme used to store ethereum states and merkle tree nodes on top
 
 	SnapshotNoBuild bool // Whether the background generation is allowed
@@ -1929,9 +1930,19 @@ func (bc *BlockChain) processBlock(block *types.Block, statedb *state.StateDB, s
 		}()
 	}
 
+	// Collect the fee currency metrics of imported blocks. This is not done in
+	// the state processor, as it also re-executes historical blocks.
+	var (
+		vmConfig           = bc.vmConfig
+		feeCurrencyMetrics *feeCurrencyMetrics
+	)
+	if metrics.Enabled && bc.chainConfig.IsCel2(block.Time()) {
+		feeCurrencyMetrics = newFeeCurrencyMetrics(GetExchangeRates(block.Header(), bc.chainConfig, statedb))
+		vmConfig.Tracer = feeCurrencyMetrics.hooks(vmConfig.Tracer)
+	}
 	// Process block using the parent state as reference point
 	pstart := time.Now()
-	receipts, logs, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig)
+	receipts, logs, usedGas, err := bc.processor.Process(block, statedb, vmConfig)
 	if err != nil {
 		bc.reportBlock(block, receipts, err)
 		return nil, err
@@ -1951,6 +1962,9 @@ func (bc *BlockChain) processBlock(block *types.Block, statedb *state.StateDB, s
 			return nil, fmt.Errorf("cross verification failed: %v", err)
 		}
 	}
+	if feeCurrencyMetrics != nil {
+		feeCurrencyMetrics.update(block, receipts)
+	}
 	proctime := time.Since(start) // processing + validation
 
 	// Update the metrics touched during block processing and validation
@@ -416,6 +416,13 @@ func (bc *BlockChain) TxIndexProgress() (TxIndexProgress, error) {
 	return bc.txIndexer.txIndexProgress()
 }
 
+// HistoryPruningCutoff returns the number of the first block whose body and
+// receipts are retained, the ones of earlier blocks were pruned.
+func (bc *BlockChain) HistoryPruningCutoff() uint64 {
+	tail, _ := bc.db.Tail()
+	return tail
+}
+
 // TrieDB retrieves the low level trie database used for data storage.
 func (bc *BlockChain) TrieDB() *triedb.Database {
 	return bc.triedb
@@ -1950,6 +1950,69 @@ func testInsertReceiptChainRollback(t *testing.T, scheme string) {
 	}
 }
 
+// Tests that a chain can be reopened after its history was pruned, including
+// the genesis block whose body and receipts are kept in the key-value store.
+func TestReopenPrunedChain(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 32, func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), common.Address{0xaa}, big.NewInt(1), params.TxGas, b.BaseFee(), nil), signer, key)
+		b.AddTx(tx)
+	})
+	db, _ := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	defer db.Close()
+
+	// Import the chain into the ancient store and prune half of it
+	chain, err := NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	headers := make([]*types.Header, len(blocks))
+	for i, block := range blocks {
+		headers[i] = block.Header()
+	}
+	if n, err := chain.InsertHeaderChain(headers); err != nil {
+		t.Fatalf("failed to insert header %d: %v", n, err)
+	}
+	if n, err := chain.InsertReceiptChain(blocks, receipts, uint64(len(blocks))); err != nil {
+		t.Fatalf("failed to insert receipt %d: %v", n, err)
+	}
+	chain.Stop()
+
+	if _, err := db.TruncateTail(16); err != nil {
+		t.Fatalf("failed to prune chain history: %v", err)
+	}
+	// Reopen the chain and check the retained data
+	chain, err = NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to reopen pruned chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if genesis := chain.GetBlockByNumber(0); genesis == nil || genesis.Hash() != gspec.ToBlock().Hash() {
+		t.Fatalf("genesis block missing after pruning")
+	}
+	if receipts := rawdb.ReadRawReceipts(db, chain.Genesis().Hash(), 0); receipts == nil {
+		t.Errorf("genesis receipts missing after pruning")
+	}
+	for _, block := range blocks {
+		if have := chain.GetBlockByNumber(block.NumberU64()); (have != nil) != (block.NumberU64() >= 16) {
+			t.Errorf("unexpected body presence of block %d", block.NumberU64())
+		}
+	}
+	if head := chain.CurrentSnapBlock(); head.Hash() != blocks[len(blocks)-1].Hash() {
+		t.Errorf("snap head mismatch: have %d, want %d", head.Number, blocks[len(blocks)-1].NumberU64())
+	}
+}
+
 // Tests that importing a very large side fork, which is larger than the canon chain,
 // but where the difficulty per block is kept low: this means that it will not
 // overtake the 'canon' chain until after it's passed canon by about 200 blocks.
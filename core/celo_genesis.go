@@ -6,9 +6,12 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/contracts/addresses"
 	"github.com/ethereum/go-ethereum/contracts/celo"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // Decode 0x prefixed hex string from file (including trailing newline)
@@ -53,113 +56,269 @@ var (
 	FaucetAddr          = common.HexToAddress("0xfcf982bb4015852e706100b14e21f947a5bb718e")
 )
 
-func celoGenesisAccounts(fundedAddr common.Address) GenesisAlloc {
+// DefaultFeeCurrencyIntrinsicGas is the intrinsic gas registered in the
+// FeeCurrencyDirectory for fee currencies which don't specify one.
+const DefaultFeeCurrencyIntrinsicGas = 50000
+
+// mockOracleBaseAddr is the base from which oracle addresses are derived for
+// fee currencies which don't specify one.
+var mockOracleBaseAddr = common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb0000")
+
+// CeloGenesisFeeCurrency describes a fee currency registered in the
+// FeeCurrencyDirectory at genesis, backed by a mock oracle.
+type CeloGenesisFeeCurrency struct {
+	Address common.Address `json:"address"`
+	// Oracle is the address of the mock oracle providing the exchange rate.
+	// If nil, an address is derived from the position in the currency list.
+	Oracle *common.Address `json:"oracle,omitempty"`
+	// The exchange rate is the amount of fee currency per unit of CELO,
+	// given as RateNumerator / RateDenominator.
+	RateNumerator   *math.HexOrDecimal256 `json:"rateNumerator"`
+	RateDenominator *math.HexOrDecimal256 `json:"rateDenominator"`
+	// IntrinsicGas defaults to DefaultFeeCurrencyIntrinsicGas if zero.
+	IntrinsicGas uint64                                   `json:"intrinsicGas,omitempty"`
+	Balances     map[common.Address]*math.HexOrDecimal256 `json:"balances,omitempty"`
+}
+
+// CeloGenesisConfig declaratively describes the Celo specific genesis state of
+// a devnet: the CELO token, the registered fee currencies with their oracles,
+// the FeeCurrencyDirectory and funded accounts.
+type CeloGenesisConfig struct {
+	// Config is the chain config of the devnet. If nil, the dev chain config
+	// (params.AllDevChainProtocolChanges) is used.
+	Config    *params.ChainConfig `json:"config,omitempty"`
+	GasLimit  uint64              `json:"gasLimit,omitempty"`
+	Timestamp uint64              `json:"timestamp,omitempty"`
+
+	// EIP1559BaseFeeFloor overrides CeloConfig.EIP1559BaseFeeFloor if set.
+	EIP1559BaseFeeFloor *uint64 `json:"eip1559BaseFeeFloor,omitempty"`
+
+	FeeCurrencyDirectoryOwner common.Address                           `json:"feeCurrencyDirectoryOwner"`
+	FeeCurrencies             []CeloGenesisFeeCurrency                 `json:"feeCurrencies,omitempty"`
+	Accounts                  map[common.Address]*math.HexOrDecimal256 `json:"accounts,omitempty"`
+}
+
+// ChainConfig returns the chain config described by c, with the CeloConfig
+// populated.
+func (c *CeloGenesisConfig) ChainConfig() *params.ChainConfig {
+	config := *params.AllDevChainProtocolChanges
+	if c.Config != nil {
+		config = *c.Config
+	}
+	populateCeloConfig(&config)
+	if c.EIP1559BaseFeeFloor != nil {
+		config.Celo.EIP1559BaseFeeFloor = *c.EIP1559BaseFeeFloor
+	}
+	return &config
+}
+
+// ToGenesis assembles the genesis block described by c.
+func (c *CeloGenesisConfig) ToGenesis() (*Genesis, error) {
+	config := c.ChainConfig()
+	alloc, err := c.Alloc(config)
+	if err != nil {
+		return nil, err
+	}
+	alloc[params.BeaconRootsAddress] = types.Account{Nonce: 1, Code: params.BeaconRootsCode, Balance: common.Big0}
+
+	gasLimit := c.GasLimit
+	if gasLimit == 0 {
+		gasLimit = params.DefaultGasLimit
+	}
+	return &Genesis{
+		Config:     config,
+		Timestamp:  c.Timestamp,
+		GasLimit:   gasLimit,
+		BaseFee:    big.NewInt(params.InitialBaseFee),
+		Difficulty: big.NewInt(0),
+		Alloc:      alloc,
+	}, nil
+}
+
+// Alloc returns the genesis allocation for the Celo core contracts and funded
+// accounts described by c. The CELO token is deployed at the token address
+// configured for the chain, so that token duality works on any Celo-derived
+// chain. The storage layout of the contracts is computed from their slots.
+func (c *CeloGenesisConfig) Alloc(config *params.ChainConfig) (types.GenesisAlloc, error) {
 	// Initialize Bytecodes
 	celoTokenBytecode, err := DecodeHex(celo.CeloTokenBytecodeRaw)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	feeCurrencyBytecode, err := DecodeHex(celo.FeeCurrencyBytecodeRaw)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	feeCurrencyDirectoryBytecode, err := DecodeHex(celo.FeeCurrencyDirectoryBytecodeRaw)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	mockOracleBytecode, err := DecodeHex(celo.MockOracleBytecodeRaw)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	var devBalance32 common.Hash
-	DevBalance.FillBytes(devBalance32[:])
-
-	arrayAtSlot2 := crypto.Keccak256Hash(common.HexToHash("0x2").Bytes())
-
-	faucetBalance, ok := new(big.Int).SetString("500000000000000000000000000", 10) // 500M
-	if !ok {
-		panic("Couldn not set faucet balance!")
-	}
-	genesisAccounts := map[common.Address]GenesisAccount{
-		addresses.CeloTokenAddress: {
+	genesisAccounts := types.GenesisAlloc{
+		config.CeloTokenAddress(): {
 			Code:    celoTokenBytecode,
 			Balance: big.NewInt(0),
 		},
-		DevFeeCurrencyAddr: {
-			Code:    feeCurrencyBytecode,
-			Balance: big.NewInt(0),
-			Storage: map[common.Hash]common.Hash{
-				CalcMapAddr(common.HexToHash("0x0"), DevAddr32):                              devBalance32, // _balances[DevAddr]
-				CalcMapAddr(common.HexToHash("0x0"), common.BytesToHash(fundedAddr.Bytes())): devBalance32, // _balances[fund]
-				common.HexToHash("0x2"): devBalance32, // _totalSupply
-			},
-		},
-		DevFeeCurrencyAddr2: {
+	}
+	// The core contracts can't be replaced by plain accounts
+	reserved := func(addr common.Address) bool {
+		_, ok := genesisAccounts[addr]
+		return ok || addr == addresses.FeeCurrencyDirectoryAddress
+	}
+	for addr, balance := range c.Accounts {
+		if balance == nil {
+			return nil, fmt.Errorf("missing balance for account %s", addr)
+		}
+		if reserved(addr) {
+			return nil, fmt.Errorf("genesis account %s collides with a core contract", addr)
+		}
+		genesisAccounts[addr] = types.Account{Balance: (*big.Int)(balance)}
+	}
+
+	// FeeCurrencyDirectory
+	ownerOffset1 := common.Hash{}
+	copy(ownerOffset1[11:], c.FeeCurrencyDirectoryOwner.Bytes())
+	feeCurrencyDirectoryStorage := map[common.Hash]common.Hash{
+		// owner, slot 0 offset 1
+		common.HexToHash("0x0"): ownerOffset1,
+		// currencyList array length at slot 2
+		common.HexToHash("0x2"): common.BigToHash(big.NewInt(int64(len(c.FeeCurrencies)))),
+	}
+	arrayAtSlot2 := crypto.Keccak256Hash(common.HexToHash("0x2").Bytes())
+
+	for i, fc := range c.FeeCurrencies {
+		if reserved(fc.Address) {
+			return nil, fmt.Errorf("duplicate genesis account for fee currency %s", fc.Address)
+		}
+		if fc.RateNumerator == nil || fc.RateDenominator == nil ||
+			(*big.Int)(fc.RateNumerator).Sign() <= 0 || (*big.Int)(fc.RateDenominator).Sign() <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate for fee currency %s", fc.Address)
+		}
+		oracleAddr := common.BigToAddress(new(big.Int).Add(mockOracleBaseAddr.Big(), big.NewInt(int64(i+1))))
+		if fc.Oracle != nil {
+			oracleAddr = *fc.Oracle
+		}
+		if reserved(oracleAddr) {
+			return nil, fmt.Errorf("duplicate genesis account for oracle %s of fee currency %s", oracleAddr, fc.Address)
+		}
+		intrinsicGas := fc.IntrinsicGas
+		if intrinsicGas == 0 {
+			intrinsicGas = DefaultFeeCurrencyIntrinsicGas
+		}
+
+		// ERC20 balances at slot 0, total supply at slot 2
+		totalSupply := new(big.Int)
+		feeCurrencyStorage := make(map[common.Hash]common.Hash)
+		for holder, balance := range fc.Balances {
+			if balance == nil {
+				return nil, fmt.Errorf("missing balance for %s in fee currency %s", holder, fc.Address)
+			}
+			feeCurrencyStorage[CalcMapAddr(common.HexToHash("0x0"), common.BytesToHash(holder.Bytes()))] = common.BigToHash((*big.Int)(balance))
+			totalSupply.Add(totalSupply, (*big.Int)(balance))
+		}
+		feeCurrencyStorage[common.HexToHash("0x2")] = common.BigToHash(totalSupply)
+		genesisAccounts[fc.Address] = types.Account{
 			Code:    feeCurrencyBytecode,
 			Balance: big.NewInt(0),
-			Storage: map[common.Hash]common.Hash{
-				CalcMapAddr(common.HexToHash("0x0"), DevAddr32):                              devBalance32, // _balances[DevAddr]
-				CalcMapAddr(common.HexToHash("0x0"), common.BytesToHash(fundedAddr.Bytes())): devBalance32, // _balances[fund]
-				common.HexToHash("0x2"): devBalance32, // _totalSupply
-			},
-		},
-		mockOracleAddr: {
+			Storage: feeCurrencyStorage,
+		}
+
+		genesisAccounts[oracleAddr] = types.Account{
 			Code:    mockOracleBytecode,
 			Balance: big.NewInt(0),
 			Storage: map[common.Hash]common.Hash{
-				common.HexToHash("0x0"): common.BigToHash(rateNumerator),
-				common.HexToHash("0x1"): common.BigToHash(rateDenominator),
-				common.HexToHash("0x3"): common.BytesToHash(DevFeeCurrencyAddr.Bytes()),
+				common.HexToHash("0x0"): common.BigToHash((*big.Int)(fc.RateNumerator)),
+				common.HexToHash("0x1"): common.BigToHash((*big.Int)(fc.RateDenominator)),
+				common.HexToHash("0x3"): common.BytesToHash(fc.Address.Bytes()),
 			},
-		},
-		mockOracleAddr2: {
-			Code:    mockOracleBytecode,
-			Balance: big.NewInt(0),
-			Storage: map[common.Hash]common.Hash{
-				common.HexToHash("0x0"): common.BigToHash(rateNumerator2),
-				common.HexToHash("0x1"): common.BigToHash(rateDenominator),
-				common.HexToHash("0x3"): common.BytesToHash(DevFeeCurrencyAddr2.Bytes()),
+		}
+
+		// add entries to currencyList and currencyConfig
+		feeCurrencyDirectoryStorage[incHash(arrayAtSlot2, int64(i))] = common.BytesToHash(fc.Address.Bytes())
+		addFeeCurrencyToStorage(fc.Address, oracleAddr, intrinsicGas, feeCurrencyDirectoryStorage)
+	}
+	genesisAccounts[addresses.FeeCurrencyDirectoryAddress] = types.Account{
+		Code:    feeCurrencyDirectoryBytecode,
+		Balance: big.NewInt(0),
+		Storage: feeCurrencyDirectoryStorage,
+	}
+
+	return genesisAccounts, nil
+}
+
+// devCeloGenesisConfig returns the Celo genesis config used for dev chains.
+func devCeloGenesisConfig(fundedAddr common.Address) *CeloGenesisConfig {
+	faucetBalance, ok := new(big.Int).SetString("500000000000000000000000000", 10) // 500M
+	if !ok {
+		panic("Couldn not set faucet balance!")
+	}
+	devBalances := map[common.Address]*math.HexOrDecimal256{
+		DevAddr:    (*math.HexOrDecimal256)(DevBalance),
+		fundedAddr: (*math.HexOrDecimal256)(DevBalance),
+	}
+	return &CeloGenesisConfig{
+		FeeCurrencyDirectoryOwner: DevAddr,
+		FeeCurrencies: []CeloGenesisFeeCurrency{
+			{
+				Address:         DevFeeCurrencyAddr,
+				Oracle:          &mockOracleAddr,
+				RateNumerator:   (*math.HexOrDecimal256)(rateNumerator),
+				RateDenominator: (*math.HexOrDecimal256)(rateDenominator),
+				Balances:        devBalances,
+			},
+			{
+				Address:         DevFeeCurrencyAddr2,
+				Oracle:          &mockOracleAddr2,
+				RateNumerator:   (*math.HexOrDecimal256)(rateNumerator2),
+				RateDenominator: (*math.HexOrDecimal256)(rateDenominator),
+				Balances:        devBalances,
 			},
 		},
-		mockOracleAddr3: {
-			Code:    mockOracleBytecode,
-			Balance: big.NewInt(0),
-			// This oracle is available for tests of contracts outside the celo_genesis, so no initialization is done at this point
-		},
-		DevAddr: {
-			Balance: DevBalance,
-		},
-		FaucetAddr: {
-			Balance: faucetBalance,
+		Accounts: map[common.Address]*math.HexOrDecimal256{
+			DevAddr:    (*math.HexOrDecimal256)(DevBalance),
+			FaucetAddr: (*math.HexOrDecimal256)(faucetBalance),
 		},
 	}
+}
 
-	// FeeCurrencyDirectory
-	devAddrOffset1 := common.Hash{}
-	copy(devAddrOffset1[11:], DevAddr.Bytes())
-	feeCurrencyDirectoryStorage := map[common.Hash]common.Hash{
-		// owner, slot 0 offset 1
-		common.HexToHash("0x0"): devAddrOffset1,
-		// add entries to currencyList at slot 2
-		common.HexToHash("0x2"):  common.HexToHash("0x2"),                         // array length 2
-		arrayAtSlot2:             common.BytesToHash(DevFeeCurrencyAddr.Bytes()),  // FeeCurrency
-		incHash(arrayAtSlot2, 1): common.BytesToHash(DevFeeCurrencyAddr2.Bytes()), // FeeCurrency2
-	}
-	// add entries to currencyConfig mapping
-	addFeeCurrencyToStorage(DevFeeCurrencyAddr, mockOracleAddr, feeCurrencyDirectoryStorage)
-	addFeeCurrencyToStorage(DevFeeCurrencyAddr2, mockOracleAddr2, feeCurrencyDirectoryStorage)
-	genesisAccounts[addresses.FeeCurrencyDirectoryAddress] = GenesisAccount{
-		Code:    feeCurrencyDirectoryBytecode,
+// celoGenesisAccounts returns the dev allocation for the Celo core contracts.
+func celoGenesisAccounts(config *params.ChainConfig, fundedAddr common.Address) GenesisAlloc {
+	genesisAccounts, err := devCeloGenesisConfig(fundedAddr).Alloc(config)
+	if err != nil {
+		panic(err)
+	}
+	mockOracleBytecode, err := DecodeHex(celo.MockOracleBytecodeRaw)
+	if err != nil {
+		panic(err)
+	}
+	// This oracle is available for tests of contracts outside the celo_genesis, so no initialization is done at this point
+	genesisAccounts[mockOracleAddr3] = GenesisAccount{
+		Code:    mockOracleBytecode,
 		Balance: big.NewInt(0),
-		Storage: feeCurrencyDirectoryStorage,
 	}
-
 	return genesisAccounts
 }
 
-func addFeeCurrencyToStorage(feeCurrencyAddr common.Address, oracleAddr common.Address, storage map[common.Hash]common.Hash) {
+func addFeeCurrencyToStorage(feeCurrencyAddr common.Address, oracleAddr common.Address, intrinsicGas uint64, storage map[common.Hash]common.Hash) {
 	structStart := CalcMapAddr(common.HexToHash("0x1"), common.BytesToHash(feeCurrencyAddr.Bytes()))
-	storage[structStart] = common.BytesToHash(oracleAddr.Bytes())          // oracle
-	storage[incHash(structStart, 1)] = common.BigToHash(big.NewInt(50000)) // intrinsicGas
+	storage[structStart] = common.BytesToHash(oracleAddr.Bytes())                             // oracle
+	storage[incHash(structStart, 1)] = common.BigToHash(new(big.Int).SetUint64(intrinsicGas)) // intrinsicGas
+}
+
+// populateCeloConfig makes the CELO token address explicit in the chain
+// config, so that it is stored alongside the genesis and does not depend on
+// the chain ID based defaults.
+func populateCeloConfig(config *params.ChainConfig) {
+	celoTokenAddr := config.CeloTokenAddress()
+	if config.Celo == nil {
+		config.Celo = &params.CeloConfig{}
+	} else {
+		celoConfig := *config.Celo
+		config.Celo = &celoConfig
+	}
+	config.Celo.CeloTokenAddress = &celoTokenAddr
 }
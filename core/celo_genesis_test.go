@@ -0,0 +1,112 @@
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/contracts"
+	"github.com/ethereum/go-ethereum/contracts/addresses"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+func TestCeloGenesisConfig(t *testing.T) {
+	var (
+		owner     = common.HexToAddress("0x42cf1bbc38baaa3c4898ce8790e21ed2738c6a4a")
+		currency1 = common.HexToAddress("0x000000000000000000000000000000000000ce16")
+		currency2 = common.HexToAddress("0x000000000000000000000000000000000000ce17")
+		oracle2   = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	)
+	input := `{
+		"eip1559BaseFeeFloor": 1000,
+		"feeCurrencyDirectoryOwner": "0x42cf1bbc38baaa3c4898ce8790e21ed2738c6a4a",
+		"accounts": {"0x42cf1bbc38baaa3c4898ce8790e21ed2738c6a4a": "1000"},
+		"feeCurrencies": [{
+			"address": "0x000000000000000000000000000000000000ce16",
+			"rateNumerator": "2",
+			"rateDenominator": "1",
+			"balances": {"0x42cf1bbc38baaa3c4898ce8790e21ed2738c6a4a": "100", "0x0000000000000000000000000000000000000001": "50"}
+		}, {
+			"address": "0x000000000000000000000000000000000000ce17",
+			"oracle": "0x00000000000000000000000000000000000000aa",
+			"rateNumerator": "0x1",
+			"rateDenominator": "0x4",
+			"intrinsicGas": 60000
+		}]
+	}`
+	var config CeloGenesisConfig
+	if err := json.Unmarshal([]byte(input), &config); err != nil {
+		t.Fatalf("failed to decode config: %v", err)
+	}
+	gspec, err := config.ToGenesis()
+	if err != nil {
+		t.Fatalf("failed to generate genesis: %v", err)
+	}
+	if gspec.Config.Celo == nil || gspec.Config.Celo.EIP1559BaseFeeFloor != 1000 {
+		t.Fatalf("base fee floor not set in chain config: %v", gspec.Config.Celo)
+	}
+	if _, ok := gspec.Alloc[oracle2]; !ok {
+		t.Fatalf("missing configured oracle %v in genesis alloc", oracle2)
+	}
+
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+	state, _ := chain.State()
+	backend := &contracts.CeloBackend{ChainConfig: chain.Config(), State: state}
+
+	feeCurrencyContext, err := contracts.GetFeeCurrencyContext(backend)
+	if err != nil {
+		t.Fatalf("failed to get fee currency context: %v", err)
+	}
+	if rate := feeCurrencyContext.ExchangeRates[currency1]; rate == nil || rate.Cmp(big.NewRat(2, 1)) != 0 {
+		t.Errorf("exchange rate mismatch for %v: have %v, want 2", currency1, rate)
+	}
+	if rate := feeCurrencyContext.ExchangeRates[currency2]; rate == nil || rate.Cmp(big.NewRat(1, 4)) != 0 {
+		t.Errorf("exchange rate mismatch for %v: have %v, want 1/4", currency2, rate)
+	}
+	if gas := feeCurrencyContext.IntrinsicGasCosts[currency1]; gas != DefaultFeeCurrencyIntrinsicGas {
+		t.Errorf("intrinsic gas mismatch for %v: have %d, want %d", currency1, gas, DefaultFeeCurrencyIntrinsicGas)
+	}
+	if gas := feeCurrencyContext.IntrinsicGasCosts[currency2]; gas != 60000 {
+		t.Errorf("intrinsic gas mismatch for %v: have %d, want %d", currency2, gas, 60000)
+	}
+	balance, err := contracts.GetBalanceERC20(backend, owner, currency1)
+	if err != nil || balance.Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("fee currency balance mismatch: have %v (err %v), want 100", balance, err)
+	}
+	if balance := state.GetBalance(owner); balance.Uint64() != 1000 {
+		t.Errorf("native balance mismatch: have %v, want 1000", balance)
+	}
+}
+
+func TestCeloGenesisConfigInvalidRate(t *testing.T) {
+	config := CeloGenesisConfig{
+		FeeCurrencies: []CeloGenesisFeeCurrency{{Address: common.HexToAddress("0xce16")}},
+	}
+	if _, err := config.ToGenesis(); err == nil {
+		t.Fatal("expected error for missing exchange rate")
+	}
+}
+
+func TestCeloGenesisConfigAccountCollision(t *testing.T) {
+	var base CeloGenesisConfig
+	base.Accounts = map[common.Address]*math.HexOrDecimal256{{1}: math.NewHexOrDecimal256(1)}
+	if _, err := base.ToGenesis(); err != nil {
+		t.Fatalf("failed to create genesis: %v", err)
+	}
+	for _, addr := range []common.Address{base.ChainConfig().CeloTokenAddress(), addresses.FeeCurrencyDirectoryAddress} {
+		config := CeloGenesisConfig{
+			Accounts: map[common.Address]*math.HexOrDecimal256{addr: math.NewHexOrDecimal256(1)},
+		}
+		if _, err := config.ToGenesis(); err == nil {
+			t.Errorf("expected error for account colliding with core contract %s", addr)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/exchange"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// feeCurrencyGasGaugeName tracks the gas used by the transactions of a fee
+	// currency in the last processed block.
+	feeCurrencyGasGaugeName = "chain/feecurrency/%s/gas"
+	// feeCurrencyTxsCounterName counts the processed transactions of a fee currency.
+	feeCurrencyTxsCounterName = "chain/feecurrency/%s/txs"
+	// feeCurrencyFeesCounterName sums up the fees paid in a fee currency, in
+	// the smallest unit of the currency.
+	feeCurrencyFeesCounterName = "chain/feecurrency/%s/fees"
+	// feeCurrencyCeloFeesCounterName sums up the fees paid in a fee currency,
+	// converted to CELO wei.
+	feeCurrencyCeloFeesCounterName = "chain/feecurrency/%s/fees/celo"
+	// feeCurrencyOverheadHistName tracks the gas used for debiting and crediting
+	// the fees of a transaction.
+	feeCurrencyOverheadHistName = "chain/feecurrency/%s/overhead"
+)
+
+// maxFeeCurrencyMetrics caps the number of fee currencies with metrics of their
+// own. Metric names contain the currency, so without a cap every fee currency
+// ever seen would add metrics that are never removed. Any further currencies
+// are accounted together under feeCurrencyMetricOther.
+const maxFeeCurrencyMetrics = 32
+
+// feeCurrencyMetricOther replaces the currency in the metric names of the fee
+// currencies beyond maxFeeCurrencyMetrics.
+const feeCurrencyMetricOther = "other"
+
+var (
+	feeCurrencyMetricKeys     = make(map[common.Address]string)
+	feeCurrencyMetricKeysLock sync.Mutex
+)
+
+// FeeCurrencyMetricKey returns the name of the fee currency to be used in
+// metric names. This is the currency address for the first fee currencies
+// seen, and a shared name once the cap on the number of currencies is reached.
+func FeeCurrencyMetricKey(currency common.Address) string {
+	feeCurrencyMetricKeysLock.Lock()
+	defer feeCurrencyMetricKeysLock.Unlock()
+
+	if key, ok := feeCurrencyMetricKeys[currency]; ok {
+		return key
+	}
+	if len(feeCurrencyMetricKeys) >= maxFeeCurrencyMetrics {
+		return feeCurrencyMetricOther
+	}
+	key := currency.Hex()
+	feeCurrencyMetricKeys[currency] = key
+	return key
+}
+
+func feeCurrencyMetricName(name string, currency common.Address) string {
+	return fmt.Sprintf(name, FeeCurrencyMetricKey(currency))
+}
+
+// feeCurrencyMetrics collects the fee currency metrics of a block while it is
+// imported. The metrics are only updated once the block is validated, so that
+// invalid blocks and re-executions of historical blocks are not accounted.
+type feeCurrencyMetrics struct {
+	rates     common.ExchangeRates        // Exchange rates at the start of the block
+	overheads map[common.Address][]uint64 // Debit and credit gas of the transactions
+}
+
+func newFeeCurrencyMetrics(rates common.ExchangeRates) *feeCurrencyMetrics {
+	return &feeCurrencyMetrics{
+		rates:     rates,
+		overheads: make(map[common.Address][]uint64),
+	}
+}
+
+// hooks extends the tracing hooks to collect the gas used for debiting and
+// crediting fee currencies. The given hooks are not modified.
+func (m *feeCurrencyMetrics) hooks(hooks *tracing.Hooks) *tracing.Hooks {
+	var extended tracing.Hooks
+	if hooks != nil {
+		extended = *hooks
+	}
+	inner := extended.OnFeeCurrencyGas
+	extended.OnFeeCurrencyGas = func(feeCurrency common.Address, gasUsed, intrinsicGas uint64) {
+		m.overheads[feeCurrency] = append(m.overheads[feeCurrency], gasUsed)
+		if inner != nil {
+			inner(feeCurrency, gasUsed, intrinsicGas)
+		}
+	}
+	return &extended
+}
+
+// update records the collected metrics of the validated block.
+func (m *feeCurrencyMetrics) update(block *types.Block, receipts types.Receipts) {
+	sampler := func() metrics.Sample {
+		return metrics.ResettingSample(metrics.NewExpDecaySample(1028, 0.015))
+	}
+	for currency, overheads := range m.overheads {
+		hist := metrics.GetOrRegisterHistogramLazy(feeCurrencyMetricName(feeCurrencyOverheadHistName, currency), nil, sampler)
+		for _, gas := range overheads {
+			hist.Update(int64(gas))
+		}
+	}
+	updateFeeCurrencyMetrics(block, receipts, m.rates)
+}
+
+// updateFeeCurrencyMetrics updates the per fee currency metrics with the
+// transactions of a processed block.
+func updateFeeCurrencyMetrics(block *types.Block, receipts types.Receipts, rates common.ExchangeRates) {
+	// Gas is summed up per metric, as currencies beyond the cap share theirs
+	gas := make(map[string]uint64, len(rates))
+	for currency := range rates {
+		gas[feeCurrencyMetricName(feeCurrencyGasGaugeName, currency)] = 0
+	}
+	for i, tx := range block.Transactions() {
+		currency := tx.FeeCurrency()
+		if currency == nil {
+			continue
+		}
+		gasUsed := new(big.Int).SetUint64(receipts[i].GasUsed)
+		gas[feeCurrencyMetricName(feeCurrencyGasGaugeName, *currency)] += receipts[i].GasUsed
+		metrics.GetOrRegisterCounter(feeCurrencyMetricName(feeCurrencyTxsCounterName, *currency), nil).Inc(1)
+
+		if block.BaseFee() == nil {
+			continue
+		}
+		// Fees of CIP-66 transactions are denominated in CELO, all others in
+		// the fee currency.
+		var fee, celoFee *big.Int
+		if tx.Type() == types.CeloDenominatedTxType {
+			tip, _ := tx.EffectiveGasTip(block.BaseFee())
+			celoFee = new(big.Int).Mul(gasUsed, tip.Add(tip, block.BaseFee()))
+			converted, err := exchange.ConvertCeloToCurrency(rates, currency, celoFee)
+			if err != nil {
+				continue
+			}
+			fee = converted
+		} else {
+			baseFee, err := exchange.ConvertCeloToCurrency(rates, currency, block.BaseFee())
+			if err != nil {
+				continue
+			}
+			tip, _ := tx.EffectiveGasTip(baseFee)
+			fee = new(big.Int).Mul(gasUsed, tip.Add(tip, baseFee))
+			if celoFee, err = exchange.ConvertCurrencyToCelo(rates, currency, fee); err != nil {
+				continue
+			}
+		}
+		feeF, _ := new(big.Float).SetInt(fee).Float64()
+		celoFeeF, _ := new(big.Float).SetInt(celoFee).Float64()
+		metrics.GetOrRegisterCounterFloat64(feeCurrencyMetricName(feeCurrencyFeesCounterName, *currency), nil).Inc(feeF)
+		metrics.GetOrRegisterCounterFloat64(feeCurrencyMetricName(feeCurrencyCeloFeesCounterName, *currency), nil).Inc(celoFeeF)
+	}
+	for name, used := range gas {
+		metrics.GetOrRegisterGauge(name, nil).Update(int64(used))
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeCurrencyMetrics(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	var (
+		currency = common.HexToAddress("0x00000000000000000000000000000000000fee01")
+		idle     = common.HexToAddress("0x00000000000000000000000000000000000fee02")
+		rates    = common.ExchangeRates{currency: big.NewRat(2, 1), idle: big.NewRat(1, 1)}
+		to       = common.HexToAddress("0xdeadbeef")
+	)
+	txs := types.Transactions{
+		types.NewTx(&types.CeloDynamicFeeTxV2{
+			GasFeeCap: big.NewInt(1000), GasTipCap: big.NewInt(10), Gas: 100000, To: &to, FeeCurrency: &currency,
+		}),
+		types.NewTx(&types.DynamicFeeTx{GasFeeCap: big.NewInt(1000), GasTipCap: big.NewInt(10), Gas: 21000, To: &to}),
+	}
+	receipts := types.Receipts{{GasUsed: 50000}, {GasUsed: 21000}}
+	block := types.NewBlockWithHeader(&types.Header{BaseFee: big.NewInt(100)}).WithBody(types.Body{Transactions: txs})
+
+	metrics.GetOrRegisterGauge(feeCurrencyMetricName(feeCurrencyGasGaugeName, idle), nil).Update(1)
+	// The debit/credit overhead is collected by the tracing hooks, which must
+	// still call the original ones
+	var called bool
+	recorder := newFeeCurrencyMetrics(rates)
+	hooks := recorder.hooks(&tracing.Hooks{OnFeeCurrencyGas: func(common.Address, uint64, uint64) { called = true }})
+	hooks.OnFeeCurrencyGas(currency, 60000, 50000)
+	hooks.OnFeeCurrencyGas(currency, 40000, 50000)
+	assert.True(t, called)
+	overheadName := feeCurrencyMetricName(feeCurrencyOverheadHistName, currency)
+	assert.Nil(t, metrics.DefaultRegistry.Get(overheadName), "overhead recorded before the block is validated")
+
+	recorder.update(block, receipts)
+	assert.Equal(t, int64(2), metrics.DefaultRegistry.Get(overheadName).(metrics.Histogram).Snapshot().Count())
+
+	// The base fee is 200 in the fee currency, plus a tip of 10
+	assert.Equal(t, int64(50000), metrics.GetOrRegisterGauge(feeCurrencyMetricName(feeCurrencyGasGaugeName, currency), nil).Snapshot().Value())
+	assert.Equal(t, int64(0), metrics.GetOrRegisterGauge(feeCurrencyMetricName(feeCurrencyGasGaugeName, idle), nil).Snapshot().Value())
+	assert.Equal(t, int64(1), metrics.GetOrRegisterCounter(feeCurrencyMetricName(feeCurrencyTxsCounterName, currency), nil).Snapshot().Count())
+	assert.Equal(t, float64(50000*210), metrics.GetOrRegisterCounterFloat64(feeCurrencyMetricName(feeCurrencyFeesCounterName, currency), nil).Snapshot().Count())
+	assert.Equal(t, float64(50000*105), metrics.GetOrRegisterCounterFloat64(feeCurrencyMetricName(feeCurrencyCeloFeesCounterName, currency), nil).Snapshot().Count())
+}
+
+func TestFeeCurrencyMetricKeyCap(t *testing.T) {
+	feeCurrencyMetricKeysLock.Lock()
+	keys := feeCurrencyMetricKeys
+	feeCurrencyMetricKeys = make(map[common.Address]string)
+	feeCurrencyMetricKeysLock.Unlock()
+	defer func() {
+		feeCurrencyMetricKeysLock.Lock()
+		feeCurrencyMetricKeys = keys
+		feeCurrencyMetricKeysLock.Unlock()
+	}()
+
+	for i := 0; i < maxFeeCurrencyMetrics; i++ {
+		currency := common.BigToAddress(big.NewInt(int64(i + 1)))
+		assert.Equal(t, currency.Hex(), FeeCurrencyMetricKey(currency))
+	}
+	// Further currencies share their metrics, known ones keep their own
+	assert.Equal(t, feeCurrencyMetricOther, FeeCurrencyMetricKey(common.HexToAddress("0xfee")))
+	assert.Equal(t, common.BigToAddress(common.Big1).Hex(), FeeCurrencyMetricKey(common.BigToAddress(common.Big1)))
+	assert.Len(t, feeCurrencyMetricKeys, maxFeeCurrencyMetrics)
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package filtermaps implements a log index mapping the addresses and topics of
+// logs to their positions in the canonical chain.
+//
+// Every log is assigned a consecutive range of log value positions: the first
+// one belongs to the address of the log, the following ones to its topics. The
+// position space is split into filter maps of a fixed number of log values, and
+// a log never spans the boundary of two maps. Each value is hashed into one of
+// the rows of its map, and a row stores the in-map offsets of the values hashed
+// into it. Looking up a value thus requires reading a single row per map, which
+// yields the positions of all logs containing the value, plus a few false
+// positives caused by other values hashed into the same row. The position of a
+// value within its log is part of the hash, so a topic only matches at the
+// position it is searched for.
+package filtermaps
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	logMapBits      = 16              // Log2 of the number of log values in a filter map
+	logValuesPerMap = 1 << logMapBits // Number of log values in a filter map, offsets are stored as uint16
+	rowsPerMap      = 1 << 12         // Number of rows of a filter map
+
+	indexBatchSize = 1024 // Maximum number of blocks indexed in a single database batch
+)
+
+// blockchain defines the chain methods needed by the log index.
+type blockchain interface {
+	CurrentBlock() *types.Header
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// FilterMaps is the log index of the canonical chain. It is built and kept in
+// sync with the chain head by a background indexer, rolling back the indexed
+// blocks on reorgs.
+type FilterMaps struct {
+	db    ethdb.Database
+	chain blockchain
+
+	lock sync.RWMutex
+	fmr  *rawdb.FilterMapsRange // Indexed block range, nil if the index is empty
+
+	// Rows of the filter map currently being filled, only accessed by the
+	// indexer. Dirty rows are written along with the next indexed batch.
+	mapIndex uint32
+	rows     map[uint16][]uint16
+	dirty    map[uint16]struct{}
+
+	closeCh chan struct{}
+	closeWg sync.WaitGroup
+}
+
+// NewFilterMaps creates the log index of the given chain. The indexer needs to
+// be started with Start.
+func NewFilterMaps(db ethdb.Database, chain blockchain) *FilterMaps {
+	f := &FilterMaps{
+		db:      db,
+		chain:   chain,
+		fmr:     rawdb.ReadFilterMapsRange(db),
+		closeCh: make(chan struct{}),
+	}
+	if f.fmr != nil {
+		log.Info("Loaded log index", "first", f.fmr.Tail, "last", f.fmr.Next-1)
+	}
+	return f
+}
+
+// Start launches the background indexer.
+func (f *FilterMaps) Start() {
+	f.closeWg.Add(1)
+	go f.loop()
+}
+
+// Stop terminates the background indexer and waits for it to exit.
+func (f *FilterMaps) Stop() {
+	close(f.closeCh)
+	f.closeWg.Wait()
+}
+
+// IndexedRange returns the range of blocks covered by the log index. The range
+// is only reported if its last block is still canonical, i.e. the indexer has
+// caught up with the latest reorg. Blocks whose history was pruned since they
+// were indexed are excluded.
+func (f *FilterMaps) IndexedRange() (first, last uint64, ok bool) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	if f.fmr == nil || f.fmr.Next == f.fmr.Tail {
+		return 0, 0, false
+	}
+	if rawdb.ReadCanonicalHash(f.db, f.fmr.Next-1) != f.fmr.HeadHash {
+		return 0, 0, false
+	}
+	first = f.fmr.Tail
+	if pruned, _ := f.db.Tail(); pruned > first {
+		first = pruned
+	}
+	if first >= f.fmr.Next {
+		return 0, 0, false
+	}
+	return first, f.fmr.Next - 1, true
+}
+
+// rowIndex returns the row of the given filter map a log value is stored in,
+// shift being the position of the value within its log. The row depends on the
+// map index so that values colliding in one map are unlikely to collide in
+// others.
+func rowIndex(mapIndex uint32, shift uint64, value []byte) uint16 {
+	var enc [5]byte
+	binary.BigEndian.PutUint32(enc[:], mapIndex)
+	enc[4] = byte(shift)
+	hash := crypto.Keccak256(value, enc[:])
+	return binary.BigEndian.Uint16(hash[:2]) % rowsPerMap
+}
@@ -0,0 +1,356 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filtermaps
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+var (
+	testAddresses = make([]common.Address, 1000)
+	testTopics    = make([]common.Hash, 4000)
+)
+
+func init() {
+	for i := range testAddresses {
+		testAddresses[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+	}
+	for i := range testTopics {
+		testTopics[i] = common.BigToHash(big.NewInt(int64(i + 1)))
+	}
+}
+
+// testChain is a canonical chain written directly to the database, with the
+// logs of each block kept for reference.
+type testChain struct {
+	db   ethdb.Database
+	feed event.Feed
+	rand *rand.Rand
+
+	lock sync.Mutex
+	head *types.Header
+	logs [][]*types.Log // Logs of the canonical blocks
+}
+
+func newTestChain(db ethdb.Database) *testChain {
+	c := &testChain{db: db, rand: rand.New(rand.NewSource(1))}
+	c.setHead(0, 1, 0)
+	return c
+}
+
+func (c *testChain) CurrentBlock() *types.Header {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.head
+}
+
+func (c *testChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+// setHead replaces the blocks after the given fork point with count blocks
+// with random logs, seed distinguishing the blocks of different forks.
+func (c *testChain) setHead(fork uint64, count int, seed byte) {
+	c.lock.Lock()
+	var parent common.Hash
+	if fork > 0 || c.head != nil {
+		parent = rawdb.ReadCanonicalHash(c.db, fork)
+		c.logs = c.logs[:fork+1]
+	}
+	number := fork + 1
+	if c.head == nil {
+		number = 0
+	}
+	for ; count > 0; count-- {
+		header := &types.Header{
+			Number:      new(big.Int).SetUint64(number),
+			ParentHash:  parent,
+			Extra:       []byte{seed},
+			ReceiptHash: types.EmptyReceiptsHash,
+		}
+		var (
+			receipts []*types.Receipt
+			logs     []*types.Log
+		)
+		if number > 0 {
+			header.ReceiptHash = common.Hash{1}
+			for i := c.rand.Intn(4); i > 0; i-- {
+				receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful}
+				for j := c.rand.Intn(30); j > 0; j-- {
+					l := &types.Log{Address: testAddresses[c.rand.Intn(len(testAddresses))], BlockNumber: number}
+					for k := c.rand.Intn(5); k > 0; k-- {
+						l.Topics = append(l.Topics, testTopics[c.rand.Intn(len(testTopics))])
+					}
+					receipt.Logs = append(receipt.Logs, l)
+					logs = append(logs, l)
+				}
+				receipts = append(receipts, receipt)
+			}
+		}
+		hash := header.Hash()
+		rawdb.WriteHeader(c.db, header)
+		rawdb.WriteReceipts(c.db, hash, number, receipts)
+		rawdb.WriteCanonicalHash(c.db, hash, number)
+		c.logs = append(c.logs, logs)
+		c.head, parent = header, hash
+		number++
+	}
+	for n := number; rawdb.ReadCanonicalHash(c.db, n) != (common.Hash{}); n++ {
+		rawdb.DeleteCanonicalHash(c.db, n)
+	}
+	head := c.head
+	c.lock.Unlock()
+
+	c.feed.Send(core.ChainHeadEvent{Block: types.NewBlockWithHeader(head)})
+}
+
+// matches returns the blocks in the range which contain logs matching the
+// addresses and topics.
+func (c *testChain) matches(first, last uint64, addresses []common.Address, topics [][]common.Hash) []uint64 {
+	var numbers []uint64
+	for number := first; number <= last; number++ {
+		for _, l := range c.logs[number] {
+			if len(addresses) > 0 && !slices.Contains(addresses, l.Address) {
+				continue
+			}
+			if len(topics) > len(l.Topics) {
+				continue
+			}
+			match := true
+			for i, sub := range topics {
+				if len(sub) > 0 && !slices.Contains(sub, l.Topics[i]) {
+					match = false
+				}
+			}
+			if match {
+				numbers = append(numbers, number)
+				break
+			}
+		}
+	}
+	return numbers
+}
+
+// waitIndexed waits until the log index covers the chain up to its head.
+func waitIndexed(t *testing.T, f *FilterMaps, c *testChain) {
+	t.Helper()
+	head := c.CurrentBlock().Number.Uint64()
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(10 * time.Millisecond) {
+		if first, last, ok := f.IndexedRange(); ok && first == 0 && last == head {
+			return
+		}
+	}
+	t.Fatalf("log index not synced to head %d", head)
+}
+
+// checkMatches runs random queries against the index, checking that all
+// matching blocks are reported.
+func checkMatches(t *testing.T, f *FilterMaps, c *testChain) {
+	t.Helper()
+	var (
+		rand = rand.New(rand.NewSource(2))
+		head = c.CurrentBlock().Number.Uint64()
+	)
+	for i := 0; i < 100; i++ {
+		var (
+			first     = uint64(rand.Intn(int(head) + 1))
+			last      = first + uint64(rand.Intn(int(head-first)+1))
+			addresses []common.Address
+			topics    [][]common.Hash
+		)
+		if i%3 != 0 {
+			addresses = []common.Address{testAddresses[rand.Intn(len(testAddresses))]}
+		}
+		if i%3 != 1 {
+			topics = make([][]common.Hash, rand.Intn(3))
+			topics = append(topics, []common.Hash{testTopics[rand.Intn(len(testTopics))], testTopics[rand.Intn(len(testTopics))]})
+		}
+		var have []uint64
+		err := f.Matches(context.Background(), first, last, addresses, topics, func(number uint64) error {
+			if len(have) > 0 && number <= have[len(have)-1] {
+				t.Errorf("query %d: block %d reported after %d", i, number, have[len(have)-1])
+			}
+			if number < first || number > last {
+				t.Errorf("query %d: block %d out of range %d-%d", i, number, first, last)
+			}
+			have = append(have, number)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("query %d: failed to search log index: %v", i, err)
+		}
+		want := c.matches(first, last, addresses, topics)
+		for _, number := range want {
+			if _, found := slices.BinarySearch(have, number); !found {
+				t.Errorf("query %d: matching block %d not reported", i, number)
+			}
+		}
+		// Each row only holds a few unrelated log values per map
+		if len(have) > len(want)+100 {
+			t.Errorf("query %d: too many false positives: have %d blocks, want %d", i, len(have), len(want))
+		}
+	}
+}
+
+func TestIndexMatches(t *testing.T) {
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		chain = newTestChain(db)
+		f     = NewFilterMaps(db, chain)
+	)
+	// Enough logs to fill more than one filter map
+	chain.setHead(0, 2000, 0)
+	f.Start()
+	defer f.Stop()
+
+	waitIndexed(t, f, chain)
+	if lvPointer := f.getRange().NextLvPointer; lvPointer <= logValuesPerMap {
+		t.Fatalf("log values fit into a single map: %d", lvPointer)
+	}
+	checkMatches(t, f, chain)
+
+	if err := f.Matches(context.Background(), 0, 10, nil, [][]common.Hash{nil, nil}, func(uint64) error { return nil }); err != errWildcardQuery {
+		t.Errorf("wildcard query error mismatch: have %v, want %v", err, errWildcardQuery)
+	}
+	if err := f.Matches(context.Background(), 0, 3000, testAddresses[:1], nil, func(uint64) error { return nil }); err == nil {
+		t.Errorf("query of range not covered by index succeeded")
+	}
+}
+
+func TestIndexReorg(t *testing.T) {
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		chain = newTestChain(db)
+		f     = NewFilterMaps(db, chain)
+	)
+	chain.setHead(0, 1500, 0)
+	f.Start()
+	defer f.Stop()
+	waitIndexed(t, f, chain)
+
+	// Reorg to a shorter chain, rolling back across a filter map boundary
+	chain.setHead(200, 100, 1)
+	waitIndexed(t, f, chain)
+	checkMatches(t, f, chain)
+	if _, _, ok := rawdb.ReadFilterMapBlockLV(db, 301); ok {
+		t.Errorf("log value pointer of rolled back block not deleted")
+	}
+	// Reorg to a longer chain
+	chain.setHead(250, 1500, 2)
+	waitIndexed(t, f, chain)
+	checkMatches(t, f, chain)
+}
+
+func TestIndexReorgBelowTail(t *testing.T) {
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		chain = newTestChain(db)
+		f     = NewFilterMaps(db, chain)
+	)
+	chain.setHead(0, 200, 0)
+
+	// Index the chain from a later block on, as if older history was pruned
+	f.setRange(db.NewBatch(), &rawdb.FilterMapsRange{Tail: 100, Next: 100})
+	for !f.indexBatch(200) {
+	}
+	// Reorg below the first indexed block
+	chain.setHead(40, 10, 1)
+	done := make(chan struct{})
+	go func() {
+		f.revertToCanonical(50)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("rolling back the index below its tail did not finish")
+	}
+	if f.fmr != nil {
+		t.Errorf("log index not reset: %+v", f.fmr)
+	}
+	if _, _, ok := rawdb.ReadFilterMapBlockLV(db, 150); ok {
+		t.Errorf("log value pointer of reset index not deleted")
+	}
+}
+
+func TestIndexChangedDuringSearch(t *testing.T) {
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		chain = newTestChain(db)
+		f     = NewFilterMaps(db, chain)
+	)
+	chain.setHead(0, 2000, 0)
+	f.Start()
+	defer f.Stop()
+	waitIndexed(t, f, chain)
+
+	// Reorg to a chain of the same height while searching the first filter map
+	if len(chain.logs[1]) == 0 {
+		t.Fatal("no logs in first block")
+	}
+	var (
+		address = chain.logs[1][0].Address
+		reorged bool
+	)
+	reorg := func(uint64) error {
+		if !reorged {
+			reorged = true
+			chain.setHead(1000, 1000, 1)
+			waitIndexed(t, f, chain)
+		}
+		return nil
+	}
+	if err := f.Matches(context.Background(), 1, 2000, []common.Address{address}, nil, reorg); err != errIndexChanged {
+		t.Errorf("search error mismatch: have %v, want %v", err, errIndexChanged)
+	}
+}
+
+func TestIndexRestart(t *testing.T) {
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		chain = newTestChain(db)
+		f     = NewFilterMaps(db, chain)
+	)
+	chain.setHead(0, 800, 0)
+	f.Start()
+	waitIndexed(t, f, chain)
+	f.Stop()
+
+	// Extend and reorg the chain while the indexer is not running
+	chain.setHead(700, 1000, 1)
+	f = NewFilterMaps(db, chain)
+	if first, last, ok := f.IndexedRange(); ok {
+		t.Errorf("stale index range reported: %d-%d", first, last)
+	}
+	f.Start()
+	defer f.Stop()
+	waitIndexed(t, f, chain)
+	checkMatches(t, f, chain)
+}
@@ -0,0 +1,291 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filtermaps
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// loop is the background indexer, updating the log index on every new chain
+// head until stopped.
+func (f *FilterMaps) loop() {
+	defer f.closeWg.Done()
+
+	var (
+		headCh = make(chan core.ChainHeadEvent, 10)
+		sub    = f.chain.SubscribeChainHeadEvent(headCh)
+	)
+	defer sub.Unsubscribe()
+
+	// stopped drains the head events while indexing, the current head is
+	// re-read before every batch anyway.
+	stopped := func() bool {
+		for {
+			select {
+			case <-headCh:
+			case <-f.closeCh:
+				return true
+			default:
+				return false
+			}
+		}
+	}
+	for {
+		f.update(stopped)
+		select {
+		case <-headCh:
+		case <-sub.Err():
+			return
+		case <-f.closeCh:
+			return
+		}
+	}
+}
+
+// update brings the log index in sync with the canonical chain. Blocks which
+// are no longer canonical are rolled back, then new blocks are indexed until
+// the chain head is reached, receipts are missing or the indexer is stopped.
+func (f *FilterMaps) update(stopped func() bool) {
+	var (
+		start  = time.Now()
+		logged = time.Now()
+		first  = f.nextBlock()
+	)
+	for !stopped() {
+		head := f.chain.CurrentBlock()
+		if head == nil {
+			return
+		}
+		f.revertToCanonical(head.Number.Uint64())
+		if f.indexBatch(head.Number.Uint64()) {
+			break
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Indexing logs", "block", f.nextBlock(), "head", head.Number, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if next := f.nextBlock(); next > first+indexBatchSize {
+		log.Info("Indexed logs", "blocks", next-first, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
+
+// getRange returns a copy of the indexed range. An empty index starting at the
+// first block with retained receipts is returned if nothing was indexed yet.
+func (f *FilterMaps) getRange() rawdb.FilterMapsRange {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	if f.fmr == nil {
+		tail, _ := f.db.Tail()
+		return rawdb.FilterMapsRange{Tail: tail, Next: tail}
+	}
+	return *f.fmr
+}
+
+// nextBlock returns the number of the next block to be indexed.
+func (f *FilterMaps) nextBlock() uint64 {
+	return f.getRange().Next
+}
+
+// setRange writes the batch updating the index along with the new indexed
+// range, atomically for readers of the index.
+func (f *FilterMaps) setRange(batch ethdb.Batch, fmr *rawdb.FilterMapsRange) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if fmr == nil {
+		rawdb.DeleteFilterMapsRange(batch)
+	} else {
+		rawdb.WriteFilterMapsRange(batch, *fmr)
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write log index", "err", err)
+	}
+	f.fmr = fmr
+}
+
+// revertToCanonical rolls back the indexed blocks which are no longer part of
+// the canonical chain with the given head.
+func (f *FilterMaps) revertToCanonical(head uint64) {
+	fmr := f.getRange()
+	if fmr.Next == fmr.Tail {
+		return
+	}
+	last := fmr.Next - 1
+	if last <= head && rawdb.ReadCanonicalHash(f.db, last) == fmr.HeadHash {
+		return
+	}
+	// The head may have been set below the first indexed block
+	if head < fmr.Tail {
+		log.Warn("Resetting log index", "first", fmr.Tail, "last", last, "head", head)
+		f.reset(fmr)
+		return
+	}
+	// Find the last indexed block which is still canonical
+	number := min(last, head)
+	for {
+		if _, hash, ok := rawdb.ReadFilterMapBlockLV(f.db, number); ok && hash == rawdb.ReadCanonicalHash(f.db, number) {
+			break
+		}
+		if number == fmr.Tail {
+			log.Warn("Resetting log index", "first", fmr.Tail, "last", last)
+			f.reset(fmr)
+			return
+		}
+		number--
+	}
+	log.Info("Rolling back log index", "from", last, "to", number)
+	f.rollback(fmr, number)
+}
+
+// reset removes all data of the log index.
+func (f *FilterMaps) reset(fmr rawdb.FilterMapsRange) {
+	batch := f.db.NewBatch()
+	rawdb.DeleteFilterMapRows(f.db, batch, 0)
+	rawdb.DeleteFilterMapBlockLVs(f.db, batch, fmr.Tail)
+	f.setRange(batch, nil)
+	f.rows, f.dirty = nil, nil
+}
+
+// rollback removes the blocks after the given block number from the index.
+func (f *FilterMaps) rollback(fmr rawdb.FilterMapsRange, number uint64) {
+	lvPointer, _, _ := rawdb.ReadFilterMapBlockLV(f.db, number+1)
+	_, hash, _ := rawdb.ReadFilterMapBlockLV(f.db, number)
+
+	// Truncate the rows of the map containing the new head pointer and drop
+	// the maps after it.
+	batch := f.db.NewBatch()
+	f.loadRows(uint32(lvPointer >> logMapBits))
+	offset := uint16(lvPointer % logValuesPerMap)
+	for rowIndex, row := range f.rows {
+		n := sort.Search(len(row), func(i int) bool { return row[i] >= offset })
+		if n < len(row) || lvPointer%logValuesPerMap == 0 {
+			f.rows[rowIndex] = row[:n]
+			f.dirty[rowIndex] = struct{}{}
+		}
+	}
+	f.flushRows(batch)
+	rawdb.DeleteFilterMapRows(f.db, batch, f.mapIndex+1)
+	rawdb.DeleteFilterMapBlockLVs(f.db, batch, number+1)
+
+	fmr.Next, fmr.NextLvPointer, fmr.HeadHash = number+1, lvPointer, hash
+	f.setRange(batch, &fmr)
+}
+
+// indexBatch indexes the next batch of canonical blocks up to the given head.
+// It returns whether indexing should be stopped until the next head event,
+// either because the head was reached or because receipts are missing.
+func (f *FilterMaps) indexBatch(head uint64) bool {
+	fmr := f.getRange()
+	if fmr.Next > head {
+		return true
+	}
+	var (
+		batch = f.db.NewBatch()
+		end   = min(head+1, fmr.Next+indexBatchSize)
+		done  = end > head
+	)
+	f.loadRows(uint32(fmr.NextLvPointer >> logMapBits))
+	for number := fmr.Next; number < end; number++ {
+		hash := rawdb.ReadCanonicalHash(f.db, number)
+		header := rawdb.ReadHeader(f.db, hash, number)
+		if header == nil {
+			done = true
+			break
+		}
+		// Stop at a reorg happening while indexing, it is rolled back before
+		// the next batch.
+		if number > fmr.Tail && header.ParentHash != fmr.HeadHash {
+			break
+		}
+		logs := rawdb.ReadLogs(f.db, hash, number)
+		if logs == nil && header.ReceiptHash != types.EmptyReceiptsHash {
+			log.Debug("Missing receipts for log index", "number", number, "hash", hash)
+			done = true
+			break
+		}
+		rawdb.WriteFilterMapBlockLV(batch, number, fmr.NextLvPointer, hash)
+		for _, receiptLogs := range logs {
+			for _, l := range receiptLogs {
+				fmr.NextLvPointer = f.addLog(batch, fmr.NextLvPointer, l)
+			}
+		}
+		fmr.Next, fmr.HeadHash = number+1, hash
+	}
+	if fmr.Next != f.nextBlock() {
+		f.flushRows(batch)
+		f.setRange(batch, &fmr)
+	}
+	return done
+}
+
+// addLog adds the address and topics of a log to the index at the given log
+// value pointer, and returns the pointer of the next log.
+func (f *FilterMaps) addLog(batch ethdb.KeyValueWriter, lvPointer uint64, l *types.Log) uint64 {
+	// Logs must not span filter maps, skip to the next map if needed
+	count := uint64(1 + len(l.Topics))
+	if lvPointer%logValuesPerMap+count > logValuesPerMap {
+		lvPointer += logValuesPerMap - lvPointer%logValuesPerMap
+	}
+	f.addValue(batch, lvPointer, 0, l.Address.Bytes())
+	for i, topic := range l.Topics {
+		f.addValue(batch, lvPointer, uint64(i+1), topic.Bytes())
+	}
+	return lvPointer + count
+}
+
+// addValue adds a value of the log at the given position to the row it hashes
+// to, shift being the position of the value within the log.
+func (f *FilterMaps) addValue(batch ethdb.KeyValueWriter, lvPointer uint64, shift uint64, value []byte) {
+	lvPointer += shift
+	mapIndex := uint32(lvPointer >> logMapBits)
+	if mapIndex != f.mapIndex {
+		// Maps after the head pointer are empty, no need to read them
+		f.flushRows(batch)
+		f.mapIndex = mapIndex
+		f.rows, f.dirty = make(map[uint16][]uint16), make(map[uint16]struct{})
+	}
+	rowIndex := rowIndex(mapIndex, shift, value)
+	f.rows[rowIndex] = append(f.rows[rowIndex], uint16(lvPointer%logValuesPerMap))
+	f.dirty[rowIndex] = struct{}{}
+}
+
+// loadRows loads the rows of the given filter map, unless they are cached.
+func (f *FilterMaps) loadRows(mapIndex uint32) {
+	if f.rows != nil && f.mapIndex == mapIndex {
+		return
+	}
+	f.mapIndex = mapIndex
+	f.rows, f.dirty = rawdb.ReadFilterMapRows(f.db, mapIndex), make(map[uint16]struct{})
+}
+
+// flushRows adds the dirty rows of the cached filter map to the batch.
+func (f *FilterMaps) flushRows(batch ethdb.KeyValueWriter) {
+	for rowIndex := range f.dirty {
+		rawdb.WriteFilterMapRow(batch, f.mapIndex, rowIndex, f.rows[rowIndex])
+	}
+	clear(f.dirty)
+}
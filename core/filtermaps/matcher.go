@@ -0,0 +1,212 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filtermaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+var (
+	errWildcardQuery = errors.New("log index cannot serve wildcard queries")
+	errIndexChanged  = errors.New("log index rolled back during search")
+)
+
+// clause is a set of alternative log values expected at the given distance
+// from the address of a log.
+type clause struct {
+	shift  uint64
+	values [][]byte
+}
+
+// Matches calls fn with the numbers of the blocks in the range [first, last]
+// which potentially contain logs matching the given addresses and topics, in
+// ascending order. The range must be covered by the index, see IndexedRange.
+// The reported blocks are a superset of the matching ones, the caller needs to
+// check their logs.
+func (f *FilterMaps) Matches(ctx context.Context, first, last uint64, addresses []common.Address, topics [][]common.Hash, fn func(number uint64) error) error {
+	var clauses []clause
+	if len(addresses) > 0 {
+		values := make([][]byte, len(addresses))
+		for i, address := range addresses {
+			values[i] = address.Bytes()
+		}
+		clauses = append(clauses, clause{shift: 0, values: values})
+	}
+	for i, sub := range topics {
+		if len(sub) == 0 {
+			continue // empty rule set == wildcard
+		}
+		values := make([][]byte, len(sub))
+		for j, topic := range sub {
+			values[j] = topic.Bytes()
+		}
+		clauses = append(clauses, clause{shift: uint64(i + 1), values: values})
+	}
+	if len(clauses) == 0 {
+		return errWildcardQuery
+	}
+	// Resolve the log value range of the requested blocks, remembering the
+	// indexed hash of the last one to detect the index changing underneath
+	f.lock.RLock()
+	fmr := f.fmr
+	covered := fmr != nil && first >= fmr.Tail && last < fmr.Next && first <= last
+	var lastHash common.Hash
+	if covered {
+		_, lastHash, _ = rawdb.ReadFilterMapBlockLV(f.db, last)
+	}
+	f.lock.RUnlock()
+	if !covered {
+		return fmt.Errorf("block range %d-%d not covered by log index", first, last)
+	}
+	lvFirst, _, _ := rawdb.ReadFilterMapBlockLV(f.db, first)
+	lvEnd := fmr.NextLvPointer
+	if last+1 < fmr.Next {
+		lvEnd, _, _ = rawdb.ReadFilterMapBlockLV(f.db, last+1)
+	}
+	if lvFirst >= lvEnd {
+		return nil // No logs in range
+	}
+	var (
+		resolver = &blockResolver{f: f, first: first, last: last, cache: make(map[uint64]uint64)}
+		next     = first // Blocks spanning multiple maps are only reported once
+	)
+	for mapIndex := uint32(lvFirst >> logMapBits); mapIndex <= uint32((lvEnd-1)>>logMapBits); mapIndex++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		numbers, err := f.mapMatches(mapIndex, clauses, lvFirst, lvEnd, last, lastHash, resolver)
+		if err != nil {
+			return err
+		}
+		for _, number := range numbers {
+			if number < next {
+				continue
+			}
+			if err := fn(number); err != nil {
+				return err
+			}
+			next = number + 1
+		}
+	}
+	return nil
+}
+
+// mapMatches returns the numbers of the blocks containing potential matches in
+// the given filter map, within the log value range [lvFirst, lvEnd).
+func (f *FilterMaps) mapMatches(mapIndex uint32, clauses []clause, lvFirst, lvEnd uint64, last uint64, lastHash common.Hash, resolver *blockResolver) ([]uint64, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	// The index may have been rolled back and rebuilt since the search started,
+	// possibly up to the same height with a different chain
+	if f.fmr == nil || f.fmr.Next <= last {
+		return nil, errIndexChanged
+	}
+	if _, hash, _ := rawdb.ReadFilterMapBlockLV(f.db, last); hash != lastHash {
+		return nil, errIndexChanged
+	}
+	base := uint64(mapIndex) << logMapBits
+
+	// Collect the positions of the potentially matching logs, as the
+	// intersection of the clauses.
+	var positions []uint64
+	for i, c := range clauses {
+		var matches []uint64
+		for _, value := range c.values {
+			for _, offset := range rawdb.ReadFilterMapRow(f.db, mapIndex, rowIndex(mapIndex, c.shift, value)) {
+				if uint64(offset) < c.shift {
+					continue
+				}
+				position := base + uint64(offset) - c.shift
+				if position < lvFirst || position >= lvEnd {
+					continue
+				}
+				if i > 0 {
+					if _, found := slices.BinarySearch(positions, position); !found {
+						continue
+					}
+				}
+				matches = append(matches, position)
+			}
+		}
+		slices.Sort(matches)
+		positions = slices.Compact(matches)
+		if len(positions) == 0 {
+			return nil, nil
+		}
+	}
+	// Map the positions to the blocks containing them
+	var numbers []uint64
+	for _, position := range positions {
+		number, err := resolver.blockOf(position)
+		if err != nil {
+			return nil, err
+		}
+		if len(numbers) == 0 || numbers[len(numbers)-1] != number {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers, nil
+}
+
+// blockResolver maps log value positions to the blocks containing them.
+// Positions must be resolved in ascending order.
+type blockResolver struct {
+	f           *FilterMaps
+	first, last uint64
+	cache       map[uint64]uint64 // Log value pointers of the blocks looked up
+}
+
+// pointer returns the log value pointer of the first log value of a block.
+func (r *blockResolver) pointer(number uint64) (uint64, error) {
+	if lvPointer, ok := r.cache[number]; ok {
+		return lvPointer, nil
+	}
+	lvPointer, _, ok := rawdb.ReadFilterMapBlockLV(r.f.db, number)
+	if !ok {
+		return 0, fmt.Errorf("missing log value pointer of block %d", number)
+	}
+	r.cache[number] = lvPointer
+	return lvPointer, nil
+}
+
+// blockOf returns the number of the block containing the given position, the
+// last block whose log value pointer does not exceed it.
+func (r *blockResolver) blockOf(position uint64) (uint64, error) {
+	var err error
+	n := sort.Search(int(r.last-r.first), func(i int) bool {
+		lvPointer, perr := r.pointer(r.first + uint64(i) + 1)
+		if perr != nil {
+			err = perr
+			return true
+		}
+		return lvPointer > position
+	})
+	if err != nil {
+		return 0, err
+	}
+	// Later positions can't be in earlier blocks
+	r.first += uint64(n)
+	return r.first, nil
+}
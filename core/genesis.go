@@ -695,7 +695,8 @@ func DeveloperGenesisBlock(gasLimit uint64, faucet *common.Address) *Genesis {
 	}
 
 	// Add state from celoGenesisAccounts
-	for addr, data := range celoGenesisAccounts(common.HexToAddress("0x2")) {
+	populateCeloConfig(&config)
+	for addr, data := range celoGenesisAccounts(&config, common.HexToAddress("0x2")) {
 		genesis.Alloc[addr] = data
 	}
 
@@ -429,10 +429,13 @@ func ReadBodyRLP(db ethdb.Reader, hash common.Hash, number uint64) rlp.RawValue
 	// the canonical data.
 	var data []byte
 	db.ReadAncients(func(reader ethdb.AncientReaderOp) error {
-		// Check if the data is in ancients
+		// Check if the data is in ancients. Pruned blocks are missing from
+		// there, but the genesis is retained in leveldb.
 		if isCanon(reader, number, hash) {
 			data, _ = reader.Ancient(ChainFreezerBodiesTable, number)
-			return nil
+			if len(data) > 0 || number > 0 {
+				return nil
+			}
 		}
 		// If not, try reading from leveldb
 		data, _ = db.Get(blockBodyKey(number, hash))
@@ -572,10 +575,13 @@ func HasReceipts(db ethdb.Reader, hash common.Hash, number uint64) bool {
 func ReadReceiptsRLP(db ethdb.Reader, hash common.Hash, number uint64) rlp.RawValue {
 	var data []byte
 	db.ReadAncients(func(reader ethdb.AncientReaderOp) error {
-		// Check if the data is in ancients
+		// Check if the data is in ancients. Pruned blocks are missing from
+		// there, but the genesis is retained in leveldb.
 		if isCanon(reader, number, hash) {
 			data, _ = reader.Ancient(ChainFreezerReceiptTable, number)
-			return nil
+			if len(data) > 0 || number > 0 {
+				return nil
+			}
 		}
 		// If not, try reading from leveldb
 		data, _ = db.Get(blockReceiptsKey(number, hash))
@@ -18,6 +18,7 @@ package rawdb
 
 import (
 	"bytes"
+	"encoding/binary"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -179,3 +180,162 @@ func DeleteBloombits(db ethdb.Database, bit uint, from uint64, to uint64) {
 		log.Crit("Failed to delete bloom bits", "err", it.Error())
 	}
 }
+
+// FilterMapsRange is the block range covered by the log index.
+type FilterMapsRange struct {
+	Tail, Next    uint64      // Range of indexed blocks [Tail, Next)
+	NextLvPointer uint64      // Log value pointer of the first log value of block Next
+	HeadHash      common.Hash // Hash of the last indexed block, block Next-1
+}
+
+// ReadFilterMapsRange retrieves the block range covered by the log index, or
+// nil if the index is empty.
+func ReadFilterMapsRange(db ethdb.KeyValueReader) *FilterMapsRange {
+	data, _ := db.Get(filterMapsRangeKey)
+	if len(data) == 0 {
+		return nil
+	}
+	var fmr FilterMapsRange
+	if err := rlp.DecodeBytes(data, &fmr); err != nil {
+		log.Error("Invalid log index range RLP", "err", err)
+		return nil
+	}
+	return &fmr
+}
+
+// WriteFilterMapsRange stores the block range covered by the log index.
+func WriteFilterMapsRange(db ethdb.KeyValueWriter, fmr FilterMapsRange) {
+	data, err := rlp.EncodeToBytes(&fmr)
+	if err != nil {
+		log.Crit("Failed to encode log index range", "err", err)
+	}
+	if err := db.Put(filterMapsRangeKey, data); err != nil {
+		log.Crit("Failed to store log index range", "err", err)
+	}
+}
+
+// DeleteFilterMapsRange removes the block range covered by the log index.
+func DeleteFilterMapsRange(db ethdb.KeyValueWriter) {
+	if err := db.Delete(filterMapsRangeKey); err != nil {
+		log.Crit("Failed to delete log index range", "err", err)
+	}
+}
+
+// encodeFilterMapRow encodes the log value offsets of a filter row as a
+// sequence of big endian uint16 values.
+func encodeFilterMapRow(row []uint16) []byte {
+	data := make([]byte, 2*len(row))
+	for i, offset := range row {
+		binary.BigEndian.PutUint16(data[2*i:], offset)
+	}
+	return data
+}
+
+// decodeFilterMapRow decodes the log value offsets of a filter row.
+func decodeFilterMapRow(data []byte) []uint16 {
+	row := make([]uint16, len(data)/2)
+	for i := range row {
+		row[i] = binary.BigEndian.Uint16(data[2*i:])
+	}
+	return row
+}
+
+// ReadFilterMapRow retrieves the log value offsets stored in the given row of
+// a filter map, or nil if the row is empty.
+func ReadFilterMapRow(db ethdb.KeyValueReader, mapIndex uint32, rowIndex uint16) []uint16 {
+	data, _ := db.Get(filterMapRowKey(mapIndex, rowIndex))
+	if len(data) == 0 {
+		return nil
+	}
+	return decodeFilterMapRow(data)
+}
+
+// ReadFilterMapRows retrieves all non-empty rows of a filter map.
+func ReadFilterMapRows(db ethdb.Iteratee, mapIndex uint32) map[uint16][]uint16 {
+	prefix := filterMapRowKey(mapIndex, 0)[:len(filterMapRowPrefix)+4]
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	rows := make(map[uint16][]uint16)
+	for it.Next() {
+		if len(it.Key()) != len(filterMapRowPrefix)+6 {
+			continue
+		}
+		rows[binary.BigEndian.Uint16(it.Key()[len(prefix):])] = decodeFilterMapRow(it.Value())
+	}
+	if it.Error() != nil {
+		log.Crit("Failed to read filter map rows", "err", it.Error())
+	}
+	return rows
+}
+
+// WriteFilterMapRow stores the log value offsets of a filter map row. An empty
+// row is deleted from the database.
+func WriteFilterMapRow(db ethdb.KeyValueWriter, mapIndex uint32, rowIndex uint16, row []uint16) {
+	if len(row) == 0 {
+		if err := db.Delete(filterMapRowKey(mapIndex, rowIndex)); err != nil {
+			log.Crit("Failed to delete filter map row", "err", err)
+		}
+		return
+	}
+	if err := db.Put(filterMapRowKey(mapIndex, rowIndex), encodeFilterMapRow(row)); err != nil {
+		log.Crit("Failed to store filter map row", "err", err)
+	}
+}
+
+// DeleteFilterMapRows removes the rows of all filter maps starting at the given
+// map index.
+func DeleteFilterMapRows(db ethdb.Iteratee, batch ethdb.KeyValueWriter, from uint32) {
+	it := db.NewIterator(filterMapRowPrefix, filterMapRowKey(from, 0)[len(filterMapRowPrefix):])
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(filterMapRowPrefix)+6 {
+			continue
+		}
+		if err := batch.Delete(it.Key()); err != nil {
+			log.Crit("Failed to delete filter map row", "err", err)
+		}
+	}
+	if it.Error() != nil {
+		log.Crit("Failed to delete filter map rows", "err", it.Error())
+	}
+}
+
+// ReadFilterMapBlockLV retrieves the log value pointer of the first log value
+// of the given block, along with the hash of the indexed block.
+func ReadFilterMapBlockLV(db ethdb.KeyValueReader, number uint64) (uint64, common.Hash, bool) {
+	data, _ := db.Get(filterMapBlockLVKey(number))
+	if len(data) != 8+common.HashLength {
+		return 0, common.Hash{}, false
+	}
+	return binary.BigEndian.Uint64(data), common.BytesToHash(data[8:]), true
+}
+
+// WriteFilterMapBlockLV stores the log value pointer of the first log value of
+// the given block, along with the hash of the indexed block.
+func WriteFilterMapBlockLV(db ethdb.KeyValueWriter, number uint64, lvPointer uint64, hash common.Hash) {
+	data := binary.BigEndian.AppendUint64(nil, lvPointer)
+	if err := db.Put(filterMapBlockLVKey(number), append(data, hash.Bytes()...)); err != nil {
+		log.Crit("Failed to store block log value pointer", "err", err)
+	}
+}
+
+// DeleteFilterMapBlockLVs removes the log value pointers of all blocks starting
+// at the given block number.
+func DeleteFilterMapBlockLVs(db ethdb.Iteratee, batch ethdb.KeyValueWriter, from uint64) {
+	it := db.NewIterator(filterMapBlockLVPrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(filterMapBlockLVPrefix)+8 {
+			continue
+		}
+		if err := batch.Delete(it.Key()); err != nil {
+			log.Crit("Failed to delete block log value pointer", "err", err)
+		}
+	}
+	if it.Error() != nil {
+		log.Crit("Failed to delete block log value pointers", "err", it.Error())
+	}
+}
@@ -40,14 +40,21 @@ const (
 	ChainFreezerDifficultyTable = "diffs"
 )
 
-// chainFreezerNoSnappy configures whether compression is disabled for the ancient-tables.
-// Hashes and difficulties don't compress well.
-var chainFreezerNoSnappy = map[string]bool{
-	ChainFreezerHeaderTable:     false,
-	ChainFreezerHashTable:       true,
-	ChainFreezerBodiesTable:     false,
-	ChainFreezerReceiptTable:    false,
-	ChainFreezerDifficultyTable: true,
+// freezerTableConfig contains the settings of a freezer table.
+type freezerTableConfig struct {
+	noSnappy bool // Whether compression is disabled for the table
+	prunable bool // Whether the tail of the table can be pruned by TruncateTail
+}
+
+// chainFreezerTableConfigs configures the settings of the chain freezer tables.
+// Hashes and difficulties don't compress well. Only block bodies and receipts
+// can be pruned, the headers are retained for the entire chain.
+var chainFreezerTableConfigs = map[string]freezerTableConfig{
+	ChainFreezerHeaderTable:     {noSnappy: false, prunable: false},
+	ChainFreezerHashTable:       {noSnappy: true, prunable: false},
+	ChainFreezerBodiesTable:     {noSnappy: false, prunable: true},
+	ChainFreezerReceiptTable:    {noSnappy: false, prunable: true},
+	ChainFreezerDifficultyTable: {noSnappy: true, prunable: false},
 }
 
 const (
@@ -62,12 +69,12 @@ const (
 	stateHistoryStorageData  = "storage.data"
 )
 
-var stateFreezerNoSnappy = map[string]bool{
-	stateHistoryMeta:         true,
-	stateHistoryAccountIndex: false,
-	stateHistoryStorageIndex: false,
-	stateHistoryAccountData:  false,
-	stateHistoryStorageData:  false,
+var stateFreezerTableConfigs = map[string]freezerTableConfig{
+	stateHistoryMeta:         {noSnappy: true, prunable: true},
+	stateHistoryAccountIndex: {noSnappy: false, prunable: true},
+	stateHistoryStorageIndex: {noSnappy: false, prunable: true},
+	stateHistoryAccountData:  {noSnappy: false, prunable: true},
+	stateHistoryStorageData:  {noSnappy: false, prunable: true},
 }
 
 // The list of identifiers of ancient stores.
@@ -88,7 +95,7 @@ var freezers = []string{ChainFreezerName, MerkleStateFreezerName, VerkleStateFre
 //     state freezer.
 func NewStateFreezer(ancientDir string, verkle bool, readOnly bool) (ethdb.ResettableAncientStore, error) {
 	if ancientDir == "" {
-		return NewMemoryFreezer(readOnly, stateFreezerNoSnappy), nil
+		return NewMemoryFreezer(readOnly, stateFreezerTableConfigs), nil
 	}
 	var name string
 	if verkle {
@@ -96,5 +103,5 @@ func NewStateFreezer(ancientDir string, verkle bool, readOnly bool) (ethdb.Reset
 	} else {
 		name = filepath.Join(ancientDir, MerkleStateFreezerName)
 	}
-	return newResettableFreezer(name, "eth/db/state", readOnly, stateHistoryTableSize, stateFreezerNoSnappy)
+	return newResettableFreezer(name, "eth/db/state", readOnly, stateHistoryTableSize, stateFreezerTableConfigs)
 }
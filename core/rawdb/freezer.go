@@ -63,7 +63,7 @@ const freezerTableSize = 2 * 1000 * 1000 * 1000
 //     of Geth, and thus also GC overhead.
 type Freezer struct {
 	frozen atomic.Uint64 // Number of items already frozen
-	tail   atomic.Uint64 // Number of the first stored item in the freezer
+	tail   atomic.Uint64 // Number of the first stored item in the prunable tables
 
 	// This lock synchronizes writers and the truncate operation, as well as
 	// the "atomic" (batched) read operations.
@@ -71,17 +71,19 @@ type Freezer struct {
 	writeBatch *freezerBatch
 
 	readonly     bool
-	tables       map[string]*freezerTable // Data tables for storing everything
-	instanceLock *flock.Flock             // File-system lock to prevent double opens
+	tables       map[string]*freezerTable      // Data tables for storing everything
+	configs      map[string]freezerTableConfig // Settings of the data tables
+	instanceLock *flock.Flock                  // File-system lock to prevent double opens
 	closeOnce    sync.Once
 }
 
 // NewFreezer creates a freezer instance for maintaining immutable ordered
 // data according to the given parameters.
 //
-// The 'tables' argument defines the data tables. If the value of a map
-// entry is true, snappy compression is disabled for the table.
-func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize uint32, tables map[string]bool) (*Freezer, error) {
+// The 'tables' argument defines the data tables along with their settings.
+// Only the tails of prunable tables are truncated by TruncateTail, the other
+// tables retain all their items.
+func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize uint32, tables map[string]freezerTableConfig) (*Freezer, error) {
 	// Create the initial freezer object
 	var (
 		readMeter  = metrics.NewRegisteredMeter(namespace+"ancient/read", nil)
@@ -115,12 +117,13 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 	freezer := &Freezer{
 		readonly:     readonly,
 		tables:       make(map[string]*freezerTable),
+		configs:      tables,
 		instanceLock: lock,
 	}
 
 	// Create the tables.
-	for name, disableSnappy := range tables {
-		table, err := newTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, disableSnappy, readonly)
+	for name, config := range tables {
+		table, err := newTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, config.noSnappy, readonly)
 		if err != nil {
 			for _, table := range freezer.tables {
 				table.Close()
@@ -294,7 +297,8 @@ func (f *Freezer) TruncateHead(items uint64) (uint64, error) {
 	return oitems, nil
 }
 
-// TruncateTail discards any recent data below the provided threshold number.
+// TruncateTail discards any recent data below the provided threshold number
+// from the prunable tables.
 func (f *Freezer) TruncateTail(tail uint64) (uint64, error) {
 	if f.readonly {
 		return 0, errReadOnly
@@ -306,7 +310,10 @@ func (f *Freezer) TruncateTail(tail uint64) (uint64, error) {
 	if old >= tail {
 		return old, nil
 	}
-	for _, table := range f.tables {
+	for kind, table := range f.tables {
+		if !f.configs[kind].prunable {
+			continue
+		}
 		if err := table.truncateTail(tail); err != nil {
 			return 0, err
 		}
@@ -329,31 +336,38 @@ func (f *Freezer) Sync() error {
 	return nil
 }
 
-// validate checks that every table has the same boundary.
-// Used instead of `repair` in readonly mode.
+// validate checks that every table has the same head, and every prunable
+// table the same tail. Used instead of `repair` in readonly mode.
 func (f *Freezer) validate() error {
 	if len(f.tables) == 0 {
 		return nil
 	}
 	var (
-		head uint64
-		tail uint64
-		name string
+		head     uint64
+		tail     uint64
+		name     string
+		tailName string
 	)
 	// Hack to get boundary of any table
 	for kind, table := range f.tables {
 		head = table.items.Load()
-		tail = table.itemHidden.Load()
 		name = kind
 		break
 	}
+	for kind, table := range f.tables {
+		if f.configs[kind].prunable {
+			tail = table.itemHidden.Load()
+			tailName = kind
+			break
+		}
+	}
 	// Now check every table against those boundaries.
 	for kind, table := range f.tables {
 		if head != table.items.Load() {
 			return fmt.Errorf("freezer tables %s and %s have differing head: %d != %d", kind, name, table.items.Load(), head)
 		}
-		if tail != table.itemHidden.Load() {
-			return fmt.Errorf("freezer tables %s and %s have differing tail: %d != %d", kind, name, table.itemHidden.Load(), tail)
+		if f.configs[kind].prunable && tail != table.itemHidden.Load() {
+			return fmt.Errorf("freezer tables %s and %s have differing tail: %d != %d", kind, tailName, table.itemHidden.Load(), tail)
 		}
 	}
 	f.frozen.Store(head)
@@ -361,26 +375,33 @@ func (f *Freezer) validate() error {
 	return nil
 }
 
-// repair truncates all data tables to the same length.
+// repair truncates all data tables to the same length, and all prunable tables
+// to the same tail.
 func (f *Freezer) repair() error {
 	var (
 		head = uint64(math.MaxUint64)
 		tail = uint64(0)
 	)
-	for _, table := range f.tables {
+	for kind, table := range f.tables {
 		items := table.items.Load()
 		if head > items {
 			head = items
 		}
+		if !f.configs[kind].prunable {
+			continue
+		}
 		hidden := table.itemHidden.Load()
 		if hidden > tail {
 			tail = hidden
 		}
 	}
-	for _, table := range f.tables {
+	for kind, table := range f.tables {
 		if err := table.truncateHead(head); err != nil {
 			return err
 		}
+		if !f.configs[kind].prunable {
+			continue
+		}
 		if err := table.truncateTail(tail); err != nil {
 			return err
 		}
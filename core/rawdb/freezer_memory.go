@@ -209,16 +209,17 @@ func (b *memoryBatch) commit(freezer *MemoryFreezer) (items uint64, writeSize in
 // MemoryFreezer is an ephemeral ancient store. It implements the ethdb.AncientStore
 // interface and can be used along with ephemeral key-value store.
 type MemoryFreezer struct {
-	items      uint64                  // Number of items stored
-	tail       uint64                  // Number of the first stored item in the freezer
-	readonly   bool                    // Flag if the freezer is only for reading
-	lock       sync.RWMutex            // Lock to protect fields
-	tables     map[string]*memoryTable // Tables for storing everything
-	writeBatch *memoryBatch            // Pre-allocated write batch
+	items      uint64                        // Number of items stored
+	tail       uint64                        // Number of the first stored item in the freezer
+	readonly   bool                          // Flag if the freezer is only for reading
+	lock       sync.RWMutex                  // Lock to protect fields
+	tables     map[string]*memoryTable       // Tables for storing everything
+	configs    map[string]freezerTableConfig // Settings of the tables
+	writeBatch *memoryBatch                  // Pre-allocated write batch
 }
 
 // NewMemoryFreezer initializes an in-memory freezer instance.
-func NewMemoryFreezer(readonly bool, tableName map[string]bool) *MemoryFreezer {
+func NewMemoryFreezer(readonly bool, tableName map[string]freezerTableConfig) *MemoryFreezer {
 	tables := make(map[string]*memoryTable)
 	for name := range tableName {
 		tables[name] = newMemoryTable(name)
@@ -227,6 +228,7 @@ func NewMemoryFreezer(readonly bool, tableName map[string]bool) *MemoryFreezer {
 		writeBatch: newMemoryBatch(),
 		readonly:   readonly,
 		tables:     tables,
+		configs:    tableName,
 	}
 }
 
@@ -368,7 +370,8 @@ func (f *MemoryFreezer) TruncateHead(items uint64) (uint64, error) {
 	return old, nil
 }
 
-// TruncateTail discards any recent data below the provided threshold number.
+// TruncateTail discards any recent data below the provided threshold number
+// from the prunable tables.
 func (f *MemoryFreezer) TruncateTail(tail uint64) (uint64, error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
@@ -380,7 +383,10 @@ func (f *MemoryFreezer) TruncateTail(tail uint64) (uint64, error) {
 	if old >= tail {
 		return old, nil
 	}
-	for _, table := range f.tables {
+	for kind, table := range f.tables {
+		if !f.configs[kind].prunable {
+			continue
+		}
 		if err := table.truncateTail(tail); err != nil {
 			return 0, err
 		}
@@ -33,7 +33,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-var freezerTestTableDef = map[string]bool{"test": true}
+var freezerTestTableDef = map[string]freezerTableConfig{"test": {noSnappy: true, prunable: true}}
 
 func TestFreezerModify(t *testing.T) {
 	t.Parallel()
@@ -49,7 +49,7 @@ func TestFreezerModify(t *testing.T) {
 		valuesRLP = append(valuesRLP, iv)
 	}
 
-	tables := map[string]bool{"raw": true, "rlp": false}
+	tables := map[string]freezerTableConfig{"raw": {noSnappy: true, prunable: true}, "rlp": {noSnappy: false, prunable: true}}
 	f, _ := newFreezerForTesting(t, tables)
 	defer f.Close()
 
@@ -113,7 +113,7 @@ func TestFreezerModifyRollback(t *testing.T) {
 	f.Close()
 
 	// Reopen and check that the rolled-back data doesn't reappear.
-	tables := map[string]bool{"test": true}
+	tables := map[string]freezerTableConfig{"test": {noSnappy: true, prunable: true}}
 	f2, err := NewFreezer(dir, "", false, 2049, tables)
 	if err != nil {
 		t.Fatalf("can't reopen freezer after failed ModifyAncients: %v", err)
@@ -251,7 +251,7 @@ func TestFreezerConcurrentModifyTruncate(t *testing.T) {
 }
 
 func TestFreezerReadonlyValidate(t *testing.T) {
-	tables := map[string]bool{"a": true, "b": true}
+	tables := map[string]freezerTableConfig{"a": {noSnappy: true, prunable: true}, "b": {noSnappy: true, prunable: true}}
 	dir := t.TempDir()
 	// Open non-readonly freezer and fill individual tables
 	// with different amount of data.
@@ -287,7 +287,7 @@ func TestFreezerReadonlyValidate(t *testing.T) {
 func TestFreezerConcurrentReadonly(t *testing.T) {
 	t.Parallel()
 
-	tables := map[string]bool{"a": true}
+	tables := map[string]freezerTableConfig{"a": {noSnappy: true, prunable: true}}
 	dir := t.TempDir()
 
 	f, err := NewFreezer(dir, "", false, 2049, tables)
@@ -335,7 +335,63 @@ func TestFreezerConcurrentReadonly(t *testing.T) {
 	}
 }
 
-func newFreezerForTesting(t *testing.T, tables map[string]bool) (*Freezer, string) {
+// This checks that tail truncation only affects the prunable tables, and that
+// the differing tails survive a reopen.
+func TestFreezerTruncateTailPrunable(t *testing.T) {
+	t.Parallel()
+
+	tables := map[string]freezerTableConfig{"a": {noSnappy: true, prunable: false}, "b": {noSnappy: true, prunable: true}}
+	dir := t.TempDir()
+	f, err := NewFreezer(dir, "", false, 2049, tables)
+	if err != nil {
+		t.Fatal("can't open freezer", err)
+	}
+	_, err = f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for i := uint64(0); i < 100; i++ {
+			if err := op.AppendRaw("a", i, getChunk(256, int(i))); err != nil {
+				return err
+			}
+			if err := op.AppendRaw("b", i, getChunk(256, int(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("ModifyAncients failed:", err)
+	}
+	if _, err := f.TruncateTail(50); err != nil {
+		t.Fatal("TruncateTail failed:", err)
+	}
+	check := func(f *Freezer) {
+		if tail, _ := f.Tail(); tail != 50 {
+			t.Fatalf("wrong tail: have %d, want 50", tail)
+		}
+		if _, err := f.Ancient("a", 0); err != nil {
+			t.Fatalf("non-prunable item was truncated: %v", err)
+		}
+		if _, err := f.Ancient("b", 49); err == nil {
+			t.Fatal("prunable item was not truncated")
+		}
+		if _, err := f.Ancient("b", 50); err != nil {
+			t.Fatalf("prunable item above tail was truncated: %v", err)
+		}
+	}
+	check(f)
+	require.NoError(t, f.Close())
+
+	// Reopen, both in write mode for repair and in readonly mode for validation
+	for _, readonly := range []bool{false, true} {
+		f, err = NewFreezer(dir, "", readonly, 2049, tables)
+		if err != nil {
+			t.Fatal("can't reopen freezer", err)
+		}
+		check(f)
+		require.NoError(t, f.Close())
+	}
+}
+
+func newFreezerForTesting(t *testing.T, tables map[string]freezerTableConfig) (*Freezer, string) {
 	t.Helper()
 
 	dir := t.TempDir()
@@ -462,7 +518,7 @@ func TestRenameWindows(t *testing.T) {
 
 func TestFreezerCloseSync(t *testing.T) {
 	t.Parallel()
-	f, _ := newFreezerForTesting(t, map[string]bool{"a": true, "b": true})
+	f, _ := newFreezerForTesting(t, map[string]freezerTableConfig{"a": {noSnappy: true, prunable: true}, "b": {noSnappy: true, prunable: true}})
 	defer f.Close()
 
 	// Now, close and sync. This mimics the behaviour if the node is shut down,
@@ -484,17 +540,17 @@ func TestFreezerCloseSync(t *testing.T) {
 
 func TestFreezerSuite(t *testing.T) {
 	ancienttest.TestAncientSuite(t, func(kinds []string) ethdb.AncientStore {
-		tables := make(map[string]bool)
+		tables := make(map[string]freezerTableConfig)
 		for _, kind := range kinds {
-			tables[kind] = true
+			tables[kind] = freezerTableConfig{noSnappy: true, prunable: true}
 		}
 		f, _ := newFreezerForTesting(t, tables)
 		return f
 	})
 	ancienttest.TestResettableAncientSuite(t, func(kinds []string) ethdb.ResettableAncientStore {
-		tables := make(map[string]bool)
+		tables := make(map[string]freezerTableConfig)
 		for _, kind := range kinds {
-			tables[kind] = true
+			tables[kind] = freezerTableConfig{noSnappy: true, prunable: true}
 		}
 		f, _ := newResettableFreezer(t.TempDir(), "", false, 2048, tables)
 		return f
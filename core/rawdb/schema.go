@@ -96,6 +96,9 @@ var (
 	// snapSyncStatusFlagKey flags that status of snap sync.
 	snapSyncStatusFlagKey = []byte("SnapSyncStatus")
 
+	// filterMapsRangeKey tracks the block range covered by the log index.
+	filterMapsRangeKey = []byte("FilterMapsRange")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -112,6 +115,9 @@ var (
 	CodePrefix            = []byte("c") // CodePrefix + code hash -> account code
 	skeletonHeaderPrefix  = []byte("S") // skeletonHeaderPrefix + num (uint64 big endian) -> header
 
+	filterMapRowPrefix     = []byte("fR") // filterMapRowPrefix + mapIndex (uint32 big endian) + rowIndex (uint16 big endian) -> filter row
+	filterMapBlockLVPrefix = []byte("fB") // filterMapBlockLVPrefix + num (uint64 big endian) -> log value pointer (uint64 big endian) + hash
+
 	// Path-based storage scheme of merkle patricia trie.
 	TrieNodeAccountPrefix = []byte("A") // TrieNodeAccountPrefix + hexPath -> trie node
 	TrieNodeStoragePrefix = []byte("O") // TrieNodeStoragePrefix + accountHash + hexPath -> trie node
@@ -233,6 +239,23 @@ func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+// filterMapRowKey = filterMapRowPrefix + mapIndex (uint32 big endian) + rowIndex (uint16 big endian)
+func filterMapRowKey(mapIndex uint32, rowIndex uint16) []byte {
+	key := make([]byte, len(filterMapRowPrefix)+6)
+	copy(key, filterMapRowPrefix)
+	binary.BigEndian.PutUint32(key[len(filterMapRowPrefix):], mapIndex)
+	binary.BigEndian.PutUint16(key[len(filterMapRowPrefix)+4:], rowIndex)
+	return key
+}
+
+// filterMapBlockLVKey = filterMapBlockLVPrefix + num (uint64 big endian)
+func filterMapBlockLVKey(number uint64) []byte {
+	key := make([]byte, len(filterMapBlockLVPrefix)+8)
+	copy(key, filterMapBlockLVPrefix)
+	binary.BigEndian.PutUint64(key[len(filterMapBlockLVPrefix):], number)
+	return key
+}
+
 // skeletonHeaderKey = skeletonHeaderPrefix + num (uint64 big endian)
 func skeletonHeaderKey(number uint64) []byte {
 	return append(skeletonHeaderPrefix, encodeBlockNumber(number)...)
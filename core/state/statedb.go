@@ -364,6 +364,36 @@ func (s *StateDB) GetStorageRoot(addr common.Address) common.Hash {
 	return common.Hash{}
 }
 
+// CheckTransactionConditional checks the known accounts of the conditional
+// against the state.
+//
+// The storage root of an account is only updated when the state root is
+// computed, so an account whose storage was modified since fails a storage
+// root condition.
+func (s *StateDB) CheckTransactionConditional(cond *types.TransactionConditional) error {
+	for addr, account := range cond.KnownAccounts {
+		if account.StorageRoot != nil {
+			root := types.EmptyRootHash
+			if obj := s.getStateObject(addr); obj != nil {
+				if len(obj.dirtyStorage) > 0 || len(obj.uncommittedStorage) > 0 {
+					return fmt.Errorf("storage root of %v modified", addr)
+				}
+				root = obj.Root()
+			}
+			if root != *account.StorageRoot {
+				return fmt.Errorf("storage root mismatch for %v: have %v, want %v", addr, root, *account.StorageRoot)
+			}
+			continue
+		}
+		for slot, want := range account.StorageSlots {
+			if have := s.GetState(addr, slot); have != want {
+				return fmt.Errorf("storage slot %v mismatch for %v: have %v, want %v", slot, addr, have, want)
+			}
+		}
+	}
+	return nil
+}
+
 // TxIndex returns the current transaction index set by SetTxContext.
 func (s *StateDB) TxIndex() int {
 	return s.txIndex
@@ -281,6 +281,8 @@ type StateTransition struct {
 	evm          *vm.EVM
 
 	feeCurrencyGasUsed uint64
+	// Amount debited in the fee currency before execution
+	feeCurrencyDebited *big.Int
 }
 
 // NewStateTransition initialises and returns a new state transition object.
@@ -393,6 +395,7 @@ func (st *StateTransition) subFees(effectiveFee *big.Int) (err error) {
 	} else {
 		gasUsedDebit, err := contracts.DebitFees(st.evm, st.msg.FeeCurrency, st.msg.From, effectiveFee)
 		st.feeCurrencyGasUsed += gasUsedDebit
+		st.feeCurrencyDebited = effectiveFee
 		return err
 	}
 }
@@ -804,7 +807,7 @@ func (st *StateTransition) distributeTxFees() error {
 		if l1Cost != nil {
 			l1Cost, _ = exchange.ConvertCeloToCurrency(st.evm.Context.FeeCurrencyContext.ExchangeRates, feeCurrency, l1Cost)
 		}
-		if err := contracts.CreditFees(
+		gasUsedCredit, err := contracts.CreditFees(
 			st.evm,
 			feeCurrency,
 			from,
@@ -816,10 +819,28 @@ func (st *StateTransition) distributeTxFees() error {
 			baseTxFee,
 			l1Cost,
 			st.feeCurrencyGasUsed,
-		); err != nil {
+		)
+		if err != nil {
 			log.Error("Error crediting", "from", from, "coinbase", st.evm.Context.Coinbase, "feeHandler", feeHandlerAddress, "err", err)
 			return err
 		}
+		st.feeCurrencyGasUsed += gasUsedCredit
+
+		if tracer := st.evm.Config.Tracer; tracer != nil && tracer.OnFeeCurrencyGas != nil {
+			intrinsicGas, _ := common.CurrencyIntrinsicGasCost(st.evm.Context.FeeCurrencyContext.IntrinsicGasCosts, feeCurrency)
+			tracer.OnFeeCurrencyGas(*feeCurrency, st.feeCurrencyGasUsed, intrinsicGas)
+		}
+		if tracer := st.evm.Config.Tracer; tracer != nil && tracer.OnFeeCurrencyFees != nil {
+			tracer.OnFeeCurrencyFees(&tracing.FeeCurrencyFees{
+				FeeCurrency:  *feeCurrency,
+				ExchangeRate: st.evm.Context.FeeCurrencyContext.ExchangeRates[*feeCurrency],
+				Debited:      st.feeCurrencyDebited,
+				Refund:       refund,
+				Tip:          tipTxFee,
+				BaseFee:      baseTxFee,
+				L1Fee:        l1Cost,
+			})
+		}
 	}
 
 	if st.evm.Config.Tracer != nil && st.evm.Config.Tracer.OnGasChange != nil && st.gasRemaining > 0 {
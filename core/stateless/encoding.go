@@ -124,6 +124,29 @@ type extWitness struct {
 
 // extWitnessMarshalling defines the hex marshalling types for a witness.
 type extWitnessMarshalling struct {
+	Block *witnessBlock
 	Codes []hexutil.Bytes
 	State []hexutil.Bytes
 }
+
+// witnessBlock marshals a block as hex encoded RLP, as blocks have no JSON
+// encoding of their own.
+type witnessBlock types.Block
+
+// MarshalText implements encoding.TextMarshaler.
+func (b *witnessBlock) MarshalText() ([]byte, error) {
+	blob, err := rlp.EncodeToBytes((*types.Block)(b))
+	if err != nil {
+		return nil, err
+	}
+	return hexutil.Bytes(blob).MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *witnessBlock) UnmarshalText(input []byte) error {
+	var blob hexutil.Bytes
+	if err := blob.UnmarshalText(input); err != nil {
+		return err
+	}
+	return rlp.DecodeBytes(blob, (*types.Block)(b))
+}
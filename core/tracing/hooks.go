@@ -67,6 +67,18 @@ type BlockEvent struct {
 	Safe      *types.Header
 }
 
+// FeeCurrencyFees contains the amounts moved for the fees of a transaction
+// paid in a fee currency, all denominated in the fee currency.
+type FeeCurrencyFees struct {
+	FeeCurrency  common.Address
+	ExchangeRate *big.Rat // Amount of fee currency per unit of CELO
+	Debited      *big.Int // Debited from the sender before execution
+	Refund       *big.Int // Credited back to the sender
+	Tip          *big.Int // Credited to the coinbase
+	BaseFee      *big.Int // Credited to the fee handler
+	L1Fee        *big.Int // Credited to the L1 fee recipient, nil if not charged
+}
+
 type (
 	/*
 		- VM events -
@@ -167,6 +179,20 @@ type (
 
 	// LogHook is called when a log is emitted.
 	LogHook = func(log *types.Log)
+
+	/*
+		- Celo events -
+	*/
+
+	// FeeCurrencyGasHook is called after the fees of a transaction paid in a fee
+	// currency have been debited and credited. `gasUsed` is the gas consumed by
+	// the debit and credit calls, `intrinsicGas` the amount the transaction was
+	// charged for them.
+	FeeCurrencyGasHook = func(feeCurrency common.Address, gasUsed, intrinsicGas uint64)
+
+	// FeeCurrencyFeesHook is called after the fees of a transaction paid in a
+	// fee currency have been debited and credited, with the amounts moved.
+	FeeCurrencyFeesHook = func(fees *FeeCurrencyFees)
 )
 
 type Hooks struct {
@@ -196,6 +222,9 @@ type Hooks struct {
 
 	// Celo specific: should the tracer be run when fee currencies are debited/credited for gas?
 	TraceDebitCredit bool
+	// Celo events
+	OnFeeCurrencyGas  FeeCurrencyGasHook
+	OnFeeCurrencyFees FeeCurrencyFeesHook
 }
 
 // BalanceChangeReason is used to indicate the reason for a balance change, useful
@@ -37,14 +37,22 @@ func (progress TxIndexProgress) Done() bool {
 }
 
 // txIndexer is the module responsible for maintaining transaction indexes
-// according to the configured indexing range by users.
+// according to the configured indexing range by users. It also prunes the
+// block bodies and receipts beyond the configured chain history, as their
+// transaction indexes need to be removed along with them.
 type txIndexer struct {
 	// limit is the maximum number of blocks from head whose tx indexes
 	// are reserved:
 	//  * 0: means the entire chain should be indexed
 	//  * N: means the latest N blocks [HEAD-N+1, HEAD] should be indexed
 	//       and all others shouldn't.
-	limit    uint64
+	limit uint64
+
+	// history is the maximum number of blocks from head whose bodies and
+	// receipts are retained, 0 meaning the entire chain. Only blocks already
+	// moved to the ancient store are pruned.
+	history uint64
+
 	db       ethdb.Database
 	progress chan chan TxIndexProgress
 	term     chan chan struct{}
@@ -55,6 +63,7 @@ type txIndexer struct {
 func newTxIndexer(limit uint64, chain *BlockChain) *txIndexer {
 	indexer := &txIndexer{
 		limit:    limit,
+		history:  chain.cacheConfig.ChainHistory,
 		db:       chain.db,
 		progress: make(chan chan TxIndexProgress),
 		term:     make(chan chan struct{}),
@@ -69,6 +78,9 @@ func newTxIndexer(limit uint64, chain *BlockChain) *txIndexer {
 		msg = fmt.Sprintf("last %d blocks", limit)
 	}
 	log.Info("Initialized transaction indexer", "range", msg)
+	if indexer.history != 0 {
+		log.Info("Enabled chain history pruning", "retain", indexer.history)
+	}
 
 	return indexer
 }
@@ -83,13 +95,22 @@ func (indexer *txIndexer) run(tail *uint64, head uint64, stop chan struct{}, don
 	if head == 0 {
 		return
 	}
+	indexer.index(tail, head, stop)
+	indexer.prune(head, stop)
+}
+
+// index adjusts the transaction indexes to the configured limit. Blocks whose
+// bodies were pruned are never indexed.
+func (indexer *txIndexer) index(tail *uint64, head uint64, stop chan struct{}) {
+	pruned, _ := indexer.db.Tail()
+
 	// The tail flag is not existent, it means the node is just initialized
 	// and all blocks in the chain (part of them may from ancient store) are
 	// not indexed yet, index the chain according to the configured limit.
 	if tail == nil {
-		from := uint64(0)
+		from := pruned
 		if indexer.limit != 0 && head >= indexer.limit {
-			from = head - indexer.limit + 1
+			from = max(from, head-indexer.limit+1)
 		}
 		rawdb.IndexTransactions(indexer.db, from, head+1, stop, true)
 		return
@@ -97,7 +118,7 @@ func (indexer *txIndexer) run(tail *uint64, head uint64, stop chan struct{}, don
 	// The tail flag is existent (which means indexes in [tail, head] should be
 	// present), while the whole chain are requested for indexing.
 	if indexer.limit == 0 || head < indexer.limit {
-		if *tail > 0 {
+		if *tail > pruned {
 			// It can happen when chain is rewound to a historical point which
 			// is even lower than the indexes tail, recap the indexing target
 			// to new head to avoid reading non-existent block bodies.
@@ -105,7 +126,7 @@ func (indexer *txIndexer) run(tail *uint64, head uint64, stop chan struct{}, don
 			if end > head+1 {
 				end = head + 1
 			}
-			rawdb.IndexTransactions(indexer.db, 0, end, stop, true)
+			rawdb.IndexTransactions(indexer.db, pruned, end, stop, true)
 		}
 		return
 	}
@@ -113,13 +134,44 @@ func (indexer *txIndexer) run(tail *uint64, head uint64, stop chan struct{}, don
 	// limit and the latest chain head.
 	if head-indexer.limit+1 < *tail {
 		// Reindex a part of missing indices and rewind index tail to HEAD-limit
-		rawdb.IndexTransactions(indexer.db, head-indexer.limit+1, *tail, stop, true)
+		if from := max(head-indexer.limit+1, pruned); from < *tail {
+			rawdb.IndexTransactions(indexer.db, from, *tail, stop, true)
+		}
 	} else {
 		// Unindex a part of stale indices and forward index tail to HEAD-limit
 		rawdb.UnindexTransactions(indexer.db, *tail, head-indexer.limit+1, stop, false)
 	}
 }
 
+// prune removes the bodies and receipts of the frozen blocks beyond the
+// configured chain history, unindexing their transactions first.
+func (indexer *txIndexer) prune(head uint64, stop chan struct{}) {
+	if indexer.history == 0 || head < indexer.history {
+		return
+	}
+	frozen, err := indexer.db.Ancients()
+	if err != nil {
+		return
+	}
+	target := min(head-indexer.history+1, frozen)
+	if pruned, err := indexer.db.Tail(); err != nil || target <= pruned {
+		return
+	}
+	if tail := rawdb.ReadTxIndexTail(indexer.db); tail != nil && *tail < target {
+		rawdb.UnindexTransactions(indexer.db, *tail, target, stop, false)
+
+		// Bail out if unindexing was interrupted, bodies are still needed
+		if tail = rawdb.ReadTxIndexTail(indexer.db); tail == nil || *tail < target {
+			return
+		}
+	}
+	if _, err := indexer.db.TruncateTail(target); err != nil {
+		log.Error("Failed to prune chain history", "target", target, "err", err)
+		return
+	}
+	log.Info("Pruned chain history", "tail", target)
+}
+
 // loop is the scheduler of the indexer, assigning indexing/unindexing tasks depending
 // on the received chain event.
 func (indexer *txIndexer) loop(chain *BlockChain) {
@@ -159,7 +211,8 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 			done = nil
 			lastTail = rawdb.ReadTxIndexTail(indexer.db)
 		case ch := <-indexer.progress:
-			ch <- indexer.report(lastHead, lastTail)
+			pruned, _ := indexer.db.Tail()
+			ch <- indexer.report(lastHead, lastTail, pruned)
 		case ch := <-indexer.term:
 			if stop != nil {
 				close(stop)
@@ -174,12 +227,16 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 	}
 }
 
-// report returns the tx indexing progress.
-func (indexer *txIndexer) report(head uint64, tail *uint64) TxIndexProgress {
+// report returns the tx indexing progress. Blocks below the pruned tail can't
+// be indexed and aren't accounted.
+func (indexer *txIndexer) report(head uint64, tail *uint64, pruned uint64) TxIndexProgress {
 	total := indexer.limit
 	if indexer.limit == 0 || total > head {
 		total = head + 1 // genesis included
 	}
+	if pruned <= head && total > head+1-pruned {
+		total = head + 1 - pruned
+	}
 	var indexed uint64
 	if tail != nil {
 		indexed = head - *tail + 1
@@ -84,7 +84,7 @@ func TestTxIndexer(t *testing.T) {
 		for number := *tail; number <= chainHead; number += 1 {
 			verifyIndexes(db, number, true)
 		}
-		progress := indexer.report(chainHead, tail)
+		progress := indexer.report(chainHead, tail, 0)
 		if !progress.Done() {
 			t.Fatalf("Expect fully indexed")
 		}
@@ -238,3 +238,71 @@ func TestTxIndexer(t *testing.T) {
 		db.Close()
 	}
 }
+
+// TestTxIndexerPruning tests that the chain history beyond the configured limit
+// is pruned along with its transaction indexes.
+func TestTxIndexerPruning(t *testing.T) {
+	var (
+		testBankKey, _  = crypto.GenerateKey()
+		testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+		testBankFunds   = big.NewInt(1000000000000000000)
+
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine    = ethash.NewFaker()
+		nonce     = uint64(0)
+		chainHead = uint64(128)
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, engine, int(chainHead), func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.HexToAddress("0xdeadbeef"), big.NewInt(1000), params.TxGas, big.NewInt(10*params.InitialBaseFee), nil), types.HomesteadSigner{}, testBankKey)
+		gen.AddTx(tx)
+		nonce += 1
+	})
+	db, _ := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	defer db.Close()
+	rawdb.WriteAncientBlocks(db, append([]*types.Block{gspec.ToBlock()}, blocks...), append([]types.Receipts{{}}, receipts...), big.NewInt(0))
+
+	indexer := &txIndexer{
+		limit:    0,
+		history:  64,
+		db:       db,
+		progress: make(chan chan TxIndexProgress),
+	}
+	indexer.run(nil, chainHead, make(chan struct{}), make(chan struct{}))
+
+	if tail, _ := db.Tail(); tail != 65 {
+		t.Fatalf("Unexpected chain history tail, want 65, got %d", tail)
+	}
+	if tail := rawdb.ReadTxIndexTail(db); tail == nil || *tail != 65 {
+		t.Fatalf("Unexpected tx index tail, want 65, got %v", tail)
+	}
+	for number := uint64(1); number <= chainHead; number++ {
+		block := blocks[number-1]
+		if body := rawdb.ReadBody(db, block.Hash(), number); (body != nil) != (number >= 65) {
+			t.Fatalf("Unexpected body presence of block %d", number)
+		}
+		if receipts := rawdb.ReadRawReceipts(db, block.Hash(), number); (receipts != nil) != (number >= 65) {
+			t.Fatalf("Unexpected receipts presence of block %d", number)
+		}
+		if header := rawdb.ReadHeader(db, block.Hash(), number); header == nil {
+			t.Fatalf("Missing header of block %d", number)
+		}
+		for _, tx := range block.Transactions() {
+			if lookup := rawdb.ReadTxLookupEntry(db, tx.Hash()); (lookup != nil) != (number >= 65) {
+				t.Fatalf("Unexpected tx index presence of block %d", number)
+			}
+		}
+	}
+	if progress := indexer.report(chainHead, rawdb.ReadTxIndexTail(db), 65); !progress.Done() {
+		t.Fatalf("Expect fully indexed, remaining %d", progress.Remaining)
+	}
+	// Requesting the entire chain to be indexed must not touch the pruned blocks
+	indexer.history = 0
+	indexer.run(rawdb.ReadTxIndexTail(db), chainHead, make(chan struct{}), make(chan struct{}))
+	if tail := rawdb.ReadTxIndexTail(db); tail == nil || *tail != 65 {
+		t.Fatalf("Unexpected tx index tail, want 65, got %v", tail)
+	}
+}
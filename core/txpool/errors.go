@@ -60,4 +60,8 @@ var (
 	// input transaction of non-blob type when a blob transaction from this sender
 	// remains pending (and vice-versa).
 	ErrAlreadyReserved = errors.New("address already reserved")
+
+	// ErrConditionalRejected is returned if the preconditions of a conditional
+	// transaction don't hold.
+	ErrConditionalRejected = errors.New("transaction conditional rejected")
 )
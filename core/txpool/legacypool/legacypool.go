@@ -76,6 +76,8 @@ var (
 	pendingRateLimitMeter = metrics.NewRegisteredMeter("txpool/pending/ratelimit", nil) // Dropped due to rate limiting
 	pendingNofundsMeter   = metrics.NewRegisteredMeter("txpool/pending/nofunds", nil)   // Dropped due to out-of-funds
 
+	pendingConditionalMeter = metrics.NewRegisteredMeter("txpool/pending/conditional", nil) // Dropped due to rejected preconditions
+
 	// Metrics for the queued pool
 	queuedDiscardMeter   = metrics.NewRegisteredMeter("txpool/queued/discard", nil)
 	queuedReplaceMeter   = metrics.NewRegisteredMeter("txpool/queued/replace", nil)
@@ -721,6 +723,15 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 	if err := txpool.ValidateTransactionWithState(tx, pool.signer, opts); err != nil {
 		return err
 	}
+	// Ensure the preconditions of a conditional transaction hold at the head
+	if cond := tx.Conditional(); cond != nil {
+		if err := pool.currentHead.Load().CheckTransactionConditional(cond); err != nil {
+			return fmt.Errorf("%w: %v", txpool.ErrConditionalRejected, err)
+		}
+		if err := pool.currentState.CheckTransactionConditional(cond); err != nil {
+			return fmt.Errorf("%w: %v", txpool.ErrConditionalRejected, err)
+		}
+	}
 	if tx.FeeCurrency() != nil {
 		from, err := pool.signer.Sender(tx) // already validated (and cached), but cleaner to check
 		if err != nil {
@@ -1768,6 +1779,15 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
 
+		// Drop all conditional transactions rejected by the block builder
+		rejected, rejectedInvalids := list.FilterRejected()
+		for _, tx := range rejected {
+			hash := tx.Hash()
+			log.Trace("Removed rejected conditional pending transaction", "hash", hash)
+			pool.all.Remove(hash)
+		}
+		pendingConditionalMeter.Mark(int64(len(rejected)))
+
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
 		drops, invalids := pool.filter(list, addr, gasLimit)
 		for _, tx := range drops {
@@ -1776,6 +1796,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			pool.all.Remove(hash)
 		}
 		pendingNofundsMeter.Mark(int64(len(drops)))
+		invalids = append(rejectedInvalids, invalids...)
 
 		for _, tx := range invalids {
 			hash := tx.Hash()
@@ -1784,9 +1805,9 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			// Internal shuffle shouldn't touch the lookup set.
 			pool.enqueueTx(hash, tx, false, false)
 		}
-		pendingGauge.Dec(int64(len(olds) + len(drops) + len(invalids)))
+		pendingGauge.Dec(int64(len(olds) + len(rejected) + len(drops) + len(invalids)))
 		if pool.locals.contains(addr) {
-			localGauge.Dec(int64(len(olds) + len(drops) + len(invalids)))
+			localGauge.Dec(int64(len(olds) + len(rejected) + len(drops) + len(invalids)))
 		}
 		// If there's a gap in front, alert (should never happen) and postpone all transactions
 		if list.Len() > 0 && list.txs.Get(nonce) == nil {
@@ -693,6 +693,71 @@ func TestDropping(t *testing.T) {
 	}
 }
 
+// Tests that the preconditions of conditional transactions are checked on
+// insertion, and that transactions rejected by the block builder are dropped.
+func TestConditionalTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	var (
+		account  = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0xc0ffee")
+		slot     = common.HexToHash("0x01")
+		value    = common.HexToHash("0x02")
+	)
+	testAddBalance(pool, account, big.NewInt(1000000))
+	pool.mu.Lock()
+	pool.currentState.SetState(contract, slot, value)
+	pool.mu.Unlock()
+
+	conditional := func(nonce uint64, cond *types.TransactionConditional) *types.Transaction {
+		tx := transaction(nonce, 100000, key)
+		tx.SetConditional(cond)
+		return tx
+	}
+	var (
+		tx0 = conditional(0, &types.TransactionConditional{KnownAccounts: types.KnownAccounts{
+			contract: {StorageSlots: map[common.Hash]common.Hash{slot: value}},
+		}})
+		tx1 = transaction(1, 100000, key)
+	)
+	if err := pool.addRemoteSync(tx0); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	// Transactions with preconditions not holding at the head are rejected
+	failing := []*types.TransactionConditional{
+		{KnownAccounts: types.KnownAccounts{contract: {StorageSlots: map[common.Hash]common.Hash{slot: {}}}}},
+		{KnownAccounts: types.KnownAccounts{contract: {StorageRoot: &types.EmptyRootHash}}},
+		{BlockNumberMin: big.NewInt(1)},
+	}
+	for i, cond := range failing {
+		if err := pool.addRemoteSync(conditional(1, cond)); !errors.Is(err, txpool.ErrConditionalRejected) {
+			t.Errorf("conditional %d: error mismatch: have %v, want %v", i, err, txpool.ErrConditionalRejected)
+		}
+	}
+	if err := pool.addRemoteSync(tx1); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Fatalf("pending transactions mismatch: have %d, want 2", pending)
+	}
+	// A rejected transaction is dropped on reset, and its successors are queued
+	tx0.SetRejected()
+	<-pool.requestReset(nil, nil)
+
+	if pool.all.Get(tx0.Hash()) != nil {
+		t.Errorf("rejected transaction present")
+	}
+	if pending, queued := pool.Stats(); pending != 0 || queued != 1 {
+		t.Errorf("transaction count mismatch: have %d pending and %d queued, want 0 and 1", pending, queued)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that if a transaction is dropped from the current pending pool (e.g. out
 // of fund), all consecutive (still valid, but not executable) transactions are
 // postponed back into the future queue to prevent broadcasting them.
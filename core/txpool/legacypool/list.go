@@ -153,6 +153,22 @@ func (m *sortedMap) filter(filter func(*types.Transaction) bool) types.Transacti
 	return removed
 }
 
+// FilterRejected removes all conditional transactions the block builder
+// rejected from the list, returning them alongside the strict-mode invalidated
+// transactions for post-removal maintenance.
+func (l *list) FilterRejected() (types.Transactions, types.Transactions) {
+	removed := l.txs.Filter(func(tx *types.Transaction) bool {
+		return tx.Rejected()
+	})
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	invalids := l.dropInvalidsAfterRemovalAndReheap(removed)
+	l.subTotalCost(removed)
+	l.subTotalCost(invalids)
+	return removed, invalids
+}
+
 // Cap places a hard limit on the number of items, returning all transactions
 // exceeding that limit.
 func (m *sortedMap) Cap(threshold int) types.Transactions {
@@ -64,6 +64,12 @@ type Transaction struct {
 
 	// cache of details to compute the data availability fee
 	rollupCostData atomic.Value
+
+	// optional preconditions for the inclusion of the transaction
+	conditional atomic.Pointer[TransactionConditional]
+
+	// whether the block builder rejected the transaction
+	rejected atomic.Bool
 }
 
 // NewTx creates a new transaction.
@@ -407,6 +413,28 @@ func (tx *Transaction) RollupCostData() RollupCostData {
 	return out
 }
 
+// Conditional returns the preconditions for the inclusion of the transaction,
+// if any.
+func (tx *Transaction) Conditional() *TransactionConditional {
+	return tx.conditional.Load()
+}
+
+// SetConditional sets the preconditions for the inclusion of the transaction.
+func (tx *Transaction) SetConditional(cond *TransactionConditional) {
+	tx.conditional.Store(cond)
+}
+
+// Rejected reports whether the block builder rejected the transaction, as its
+// preconditions no longer hold.
+func (tx *Transaction) Rejected() bool {
+	return tx.rejected.Load()
+}
+
+// SetRejected marks the transaction as rejected by the block builder.
+func (tx *Transaction) SetRejected() {
+	tx.rejected.Store(true)
+}
+
 // RawSignatureValues returns the V, R, S signature values of the transaction.
 // The return values should not be modified by the caller.
 // The return values may be nil or zero, if the transaction is unsigned.
@@ -0,0 +1,144 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// KnownAccounts represents the expected prestate of a set of accounts.
+type KnownAccounts map[common.Address]KnownAccount
+
+// KnownAccount is the expected prestate of an account. Either the storage root
+// or a set of storage slots is expected. In JSON, the former is encoded as a
+// hash, and the latter as a mapping from slots to values.
+type KnownAccount struct {
+	StorageRoot  *common.Hash
+	StorageSlots map[common.Hash]common.Hash
+}
+
+// UnmarshalJSON parses a known account from either a storage root or a
+// mapping of storage slots.
+func (ka *KnownAccount) UnmarshalJSON(input []byte) error {
+	var root common.Hash
+	if err := json.Unmarshal(input, &root); err == nil {
+		ka.StorageRoot, ka.StorageSlots = &root, nil
+		return nil
+	}
+	var slots map[common.Hash]common.Hash
+	if err := json.Unmarshal(input, &slots); err != nil {
+		return err
+	}
+	ka.StorageRoot, ka.StorageSlots = nil, slots
+	return nil
+}
+
+// MarshalJSON encodes a known account as either its storage root or its
+// mapping of storage slots.
+func (ka KnownAccount) MarshalJSON() ([]byte, error) {
+	if ka.StorageRoot != nil {
+		return json.Marshal(ka.StorageRoot)
+	}
+	return json.Marshal(ka.StorageSlots)
+}
+
+//go:generate go run github.com/fjl/gencodec -type TransactionConditional -field-override transactionConditionalMarshaling -out gen_transaction_conditional_json.go
+
+// TransactionConditional is a set of preconditions for the inclusion of a
+// transaction. The conditions are not part of the protocol: they are checked
+// by the transaction pool and the block builder of the sequencer only.
+type TransactionConditional struct {
+	KnownAccounts KnownAccounts `json:"knownAccounts"`
+
+	// Inclusive ranges of the block which includes the transaction
+	BlockNumberMin *big.Int `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *big.Int `json:"blockNumberMax,omitempty"`
+	TimestampMin   *uint64  `json:"timestampMin,omitempty"`
+	TimestampMax   *uint64  `json:"timestampMax,omitempty"`
+}
+
+// field type overrides for gencodec
+type transactionConditionalMarshaling struct {
+	BlockNumberMin *hexutil.Big
+	BlockNumberMax *hexutil.Big
+	TimestampMin   *hexutil.Uint64
+	TimestampMax   *hexutil.Uint64
+}
+
+// Validate performs sanity checks on the conditions which don't require any
+// chain context.
+func (cond *TransactionConditional) Validate() error {
+	if cond.BlockNumberMin != nil && cond.BlockNumberMax != nil && cond.BlockNumberMin.Cmp(cond.BlockNumberMax) > 0 {
+		return errors.New("block number minimum greater than maximum")
+	}
+	if cond.TimestampMin != nil && cond.TimestampMax != nil && *cond.TimestampMin > *cond.TimestampMax {
+		return errors.New("timestamp minimum greater than maximum")
+	}
+	for addr, account := range cond.KnownAccounts {
+		if account.StorageRoot != nil && len(account.StorageSlots) > 0 {
+			return fmt.Errorf("both storage root and slots set for account %v", addr)
+		}
+	}
+	return nil
+}
+
+// Cost returns the cost of checking the conditions, which is the number of
+// lookups required.
+func (cond *TransactionConditional) Cost() int {
+	cost := 0
+	for _, account := range cond.KnownAccounts {
+		// The account itself needs to be looked up, followed by either its
+		// storage root or its slots
+		cost += 1
+		if account.StorageRoot != nil {
+			cost += 1
+		} else {
+			cost += len(account.StorageSlots)
+		}
+	}
+	if cond.BlockNumberMin != nil || cond.BlockNumberMax != nil {
+		cost += 1
+	}
+	if cond.TimestampMin != nil || cond.TimestampMax != nil {
+		cost += 1
+	}
+	return cost
+}
+
+// CheckTransactionConditional checks the block number and timestamp conditions
+// against the header.
+func (h *Header) CheckTransactionConditional(cond *TransactionConditional) error {
+	if cond.BlockNumberMin != nil && cond.BlockNumberMin.Cmp(h.Number) > 0 {
+		return fmt.Errorf("block number %v below minimum %v", h.Number, cond.BlockNumberMin)
+	}
+	if cond.BlockNumberMax != nil && cond.BlockNumberMax.Cmp(h.Number) < 0 {
+		return fmt.Errorf("block number %v above maximum %v", h.Number, cond.BlockNumberMax)
+	}
+	if cond.TimestampMin != nil && *cond.TimestampMin > h.Time {
+		return fmt.Errorf("timestamp %d below minimum %d", h.Time, *cond.TimestampMin)
+	}
+	if cond.TimestampMax != nil && *cond.TimestampMax < h.Time {
+		return fmt.Errorf("timestamp %d above maximum %d", h.Time, *cond.TimestampMax)
+	}
+	return nil
+}
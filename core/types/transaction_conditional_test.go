@@ -0,0 +1,114 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTransactionConditionalJSON(t *testing.T) {
+	var (
+		root = common.HexToHash("0x01")
+		ts   = uint64(100)
+	)
+	tests := []struct {
+		input string
+		cond  TransactionConditional
+	}{
+		{
+			input: `{"knownAccounts":{"0x000000000000000000000000000000000000aaaa":"0x0000000000000000000000000000000000000000000000000000000000000001"}}`,
+			cond:  TransactionConditional{KnownAccounts: KnownAccounts{common.HexToAddress("0xaaaa"): {StorageRoot: &root}}},
+		},
+		{
+			input: `{"knownAccounts":{"0x000000000000000000000000000000000000aaaa":{"0x0000000000000000000000000000000000000000000000000000000000000001":"0x0000000000000000000000000000000000000000000000000000000000000002"}}}`,
+			cond: TransactionConditional{KnownAccounts: KnownAccounts{common.HexToAddress("0xaaaa"): {StorageSlots: map[common.Hash]common.Hash{
+				common.HexToHash("0x01"): common.HexToHash("0x02"),
+			}}}},
+		},
+		{
+			input: `{"knownAccounts":null,"blockNumberMin":"0x1","blockNumberMax":"0x2","timestampMin":"0x64","timestampMax":"0x64"}`,
+			cond:  TransactionConditional{BlockNumberMin: big.NewInt(1), BlockNumberMax: big.NewInt(2), TimestampMin: &ts, TimestampMax: &ts},
+		},
+	}
+	for i, test := range tests {
+		var cond TransactionConditional
+		if err := json.Unmarshal([]byte(test.input), &cond); err != nil {
+			t.Fatalf("test %d: failed to decode: %v", i, err)
+		}
+		if !reflect.DeepEqual(cond, test.cond) {
+			t.Errorf("test %d: decoded mismatch: have %+v, want %+v", i, cond, test.cond)
+		}
+		blob, err := json.Marshal(&cond)
+		if err != nil {
+			t.Fatalf("test %d: failed to encode: %v", i, err)
+		}
+		if string(blob) != test.input {
+			t.Errorf("test %d: encoded mismatch: have %s, want %s", i, blob, test.input)
+		}
+	}
+}
+
+func TestTransactionConditionalCost(t *testing.T) {
+	root := common.HexToHash("0x01")
+	cond := TransactionConditional{
+		KnownAccounts: KnownAccounts{
+			common.HexToAddress("0xaaaa"): {StorageRoot: &root},
+			common.HexToAddress("0xbbbb"): {StorageSlots: map[common.Hash]common.Hash{{1}: {}, {2}: {}}},
+		},
+		BlockNumberMin: big.NewInt(1),
+		BlockNumberMax: big.NewInt(2),
+	}
+	if cost := cond.Cost(); cost != 2+3+1 {
+		t.Errorf("cost mismatch: have %d, want %d", cost, 6)
+	}
+}
+
+func TestTransactionConditionalCheck(t *testing.T) {
+	var (
+		header = &Header{Number: big.NewInt(10), Time: 100}
+		low    = uint64(99)
+		high   = uint64(101)
+	)
+	tests := []struct {
+		cond  TransactionConditional
+		valid bool
+		holds bool
+	}{
+		{TransactionConditional{}, true, true},
+		{TransactionConditional{BlockNumberMin: big.NewInt(10), BlockNumberMax: big.NewInt(10)}, true, true},
+		{TransactionConditional{BlockNumberMin: big.NewInt(11)}, true, false},
+		{TransactionConditional{BlockNumberMax: big.NewInt(9)}, true, false},
+		{TransactionConditional{BlockNumberMin: big.NewInt(2), BlockNumberMax: big.NewInt(1)}, false, false},
+		{TransactionConditional{TimestampMin: &low, TimestampMax: &high}, true, true},
+		{TransactionConditional{TimestampMin: &high}, true, false},
+		{TransactionConditional{TimestampMax: &low}, true, false},
+		{TransactionConditional{TimestampMin: &high, TimestampMax: &low}, false, false},
+	}
+	for i, test := range tests {
+		if err := test.cond.Validate(); (err == nil) != test.valid {
+			t.Errorf("test %d: validation mismatch: have %v, want valid %v", i, err, test.valid)
+		}
+		if err := header.CheckTransactionConditional(&test.cond); (err == nil) != test.holds {
+			t.Errorf("test %d: check mismatch: have %v, want holds %v", i, err, test.holds)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package vm
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/exchange"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
-	"github.com/ethereum/go-ethereum/contracts/addresses"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
 )
@@ -47,11 +49,7 @@ func celoPrecompileAddress(index byte) common.Address {
 }
 
 func (ctx *celoPrecompileContext) IsCallerCeloToken() (bool, error) {
-	tokenAddress := addresses.CeloTokenAddress
-	if ctx.evm.ChainConfig().ChainID != nil && ctx.evm.ChainConfig().ChainID.Uint64() == addresses.AlfajoresChainID {
-		tokenAddress = addresses.CeloTokenAlfajoresAddress
-	}
-	return tokenAddress == ctx.caller, nil
+	return ctx.evm.ChainConfig().CeloTokenAddress() == ctx.caller, nil
 }
 
 // Native transfer contract to make CELO ERC20 compatible.
@@ -105,3 +103,70 @@ func (c *transfer) Run(input []byte, ctx *celoPrecompileContext) ([]byte, error)
 
 	return nil, nil
 }
+
+// feeCurrencyAddressFromInput parses the single ABI encoded address argument
+// of the fee currency context getters.
+func feeCurrencyAddressFromInput(input []byte) (common.Address, error) {
+	if len(input) != 32 {
+		return common.Address{}, ErrInputLength
+	}
+	// The address is left padded with zeros, anything else is malformed
+	if !bytes.Equal(input[:32-common.AddressLength], make([]byte, 32-common.AddressLength)) {
+		return common.Address{}, ErrInvalidAddress
+	}
+	return common.BytesToAddress(input), nil
+}
+
+// Returns the exchange rate of a fee currency as used for the current block,
+// so that contracts don't have to repeat the oracle lookups done by the node.
+type feeCurrencyExchangeRate struct{}
+
+func (c *feeCurrencyExchangeRate) RequiredGas(input []byte) uint64 {
+	return params.FeeCurrencyExchangeRateGas
+}
+
+func (c *feeCurrencyExchangeRate) Run(input []byte, ctx *celoPrecompileContext) ([]byte, error) {
+	// input is comprised of a single argument:
+	//   feeCurrency: 32 bytes representing the address of the fee currency
+	// output is comprised of 2 values:
+	//   numerator:   32 bytes, a 256 bit integer
+	//   denominator: 32 bytes, a 256 bit integer
+	// An amount of CELO is converted to the fee currency by multiplying it
+	// with numerator / denominator.
+	feeCurrency, err := feeCurrencyAddressFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+	rate, ok := ctx.FeeCurrencyContext.ExchangeRates[feeCurrency]
+	if !ok {
+		return nil, exchange.ErrUnregisteredFeeCurrency
+	}
+	output := make([]byte, 64)
+	rate.Num().FillBytes(output[0:32])
+	rate.Denom().FillBytes(output[32:64])
+	return output, nil
+}
+
+// Returns the intrinsic gas cost charged for using a fee currency, as used
+// for the current block.
+type feeCurrencyIntrinsicGasCost struct{}
+
+func (c *feeCurrencyIntrinsicGasCost) RequiredGas(input []byte) uint64 {
+	return params.FeeCurrencyIntrinsicGasCostGas
+}
+
+func (c *feeCurrencyIntrinsicGasCost) Run(input []byte, ctx *celoPrecompileContext) ([]byte, error) {
+	// input is comprised of a single argument:
+	//   feeCurrency: 32 bytes representing the address of the fee currency
+	// output is a single value:
+	//   intrinsicGas: 32 bytes, a 256 bit integer
+	feeCurrency, err := feeCurrencyAddressFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+	intrinsicGas, ok := common.CurrencyIntrinsicGasCost(ctx.FeeCurrencyContext.IntrinsicGasCosts, &feeCurrency)
+	if !ok {
+		return nil, exchange.ErrUnregisteredFeeCurrency
+	}
+	return common.LeftPadBytes(new(big.Int).SetUint64(intrinsicGas).Bytes(), 32), nil
+}
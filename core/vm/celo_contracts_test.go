@@ -1,10 +1,13 @@
 package vm
 
 import (
+	"bytes"
 	"math/big"
+	"slices"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/exchange"
 	"github.com/ethereum/go-ethereum/contracts/addresses"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
@@ -118,3 +121,94 @@ func TestPrecompileTransfer(t *testing.T) {
 		})
 	}
 }
+
+func TestIsCallerCeloTokenConfigured(t *testing.T) {
+	customToken := common.HexToAddress("0xce10")
+	config := *params.TestChainConfig
+	config.Celo = &params.CeloConfig{CeloTokenAddress: &customToken}
+	evm := &EVM{
+		chainConfig: &config,
+		Context:     vmBlockCtx,
+		TxContext:   vmTxCtx,
+	}
+
+	if isCeloToken, _ := NewContext(customToken, evm).IsCallerCeloToken(); !isCeloToken {
+		t.Errorf("expected configured token %v to be accepted", customToken)
+	}
+	if isCeloToken, _ := NewContext(addresses.CeloTokenAddress, evm).IsCallerCeloToken(); isCeloToken {
+		t.Errorf("expected default token %v to be rejected", addresses.CeloTokenAddress)
+	}
+}
+
+func TestPrecompileFeeCurrencyContext(t *testing.T) {
+	var (
+		registered   = common.HexToAddress("0xce16")
+		unregistered = common.HexToAddress("0xce17")
+	)
+	blockCtx := vmBlockCtx
+	blockCtx.FeeCurrencyContext = common.FeeCurrencyContext{
+		ExchangeRates:     common.ExchangeRates{registered: big.NewRat(2, 3)},
+		IntrinsicGasCosts: common.IntrinsicGasCosts{registered: 50000},
+	}
+	ctx := NewContext(common.HexToAddress("1337"), &EVM{
+		chainConfig: params.TestChainConfig,
+		Context:     blockCtx,
+		TxContext:   vmTxCtx,
+	})
+	input := func(addr common.Address) []byte {
+		return common.LeftPadBytes(addr.Bytes(), 32)
+	}
+
+	rate, err := (&feeCurrencyExchangeRate{}).Run(input(registered), ctx)
+	if err != nil {
+		t.Fatalf("exchange rate: unexpected error: %v", err)
+	}
+	want := append(common.LeftPadBytes([]byte{2}, 32), common.LeftPadBytes([]byte{3}, 32)...)
+	if !bytes.Equal(rate, want) {
+		t.Errorf("exchange rate mismatch: have %x, want %x", rate, want)
+	}
+	if _, err := (&feeCurrencyExchangeRate{}).Run(input(unregistered), ctx); err != exchange.ErrUnregisteredFeeCurrency {
+		t.Errorf("exchange rate: unexpected error for unregistered currency: %v", err)
+	}
+	if _, err := (&feeCurrencyExchangeRate{}).Run([]byte{1}, ctx); err != ErrInputLength {
+		t.Errorf("exchange rate: unexpected error for short input: %v", err)
+	}
+	dirty := input(registered)
+	dirty[0] = 1
+	if _, err := (&feeCurrencyExchangeRate{}).Run(dirty, ctx); err != ErrInvalidAddress {
+		t.Errorf("exchange rate: unexpected error for dirty address padding: %v", err)
+	}
+
+	intrinsicGas, err := (&feeCurrencyIntrinsicGasCost{}).Run(input(registered), ctx)
+	if err != nil {
+		t.Fatalf("intrinsic gas: unexpected error: %v", err)
+	}
+	if have := new(big.Int).SetBytes(intrinsicGas).Uint64(); len(intrinsicGas) != 32 || have != 50000 {
+		t.Errorf("intrinsic gas mismatch: have %x, want %d", intrinsicGas, 50000)
+	}
+	if _, err := (&feeCurrencyIntrinsicGasCost{}).Run(input(unregistered), ctx); err != exchange.ErrUnregisteredFeeCurrency {
+		t.Errorf("intrinsic gas: unexpected error for unregistered currency: %v", err)
+	}
+}
+
+func TestCeloPrecompilesForkGating(t *testing.T) {
+	hazelnutAddr := celoPrecompileAddress(48)
+	config := *params.TestChainConfig
+	hazelnutTime := uint64(1000)
+	config.HazelnutTime = &hazelnutTime
+
+	before := config.Rules(common.Big0, true, 999)
+	if _, ok := CeloPrecompiles(before)[hazelnutAddr]; ok {
+		t.Errorf("expected %v to be inactive before Hazelnut", hazelnutAddr)
+	}
+	if _, ok := CeloPrecompiles(before)[celoPrecompileAddress(2)]; !ok {
+		t.Errorf("expected transfer precompile to be active before Hazelnut")
+	}
+	after := config.Rules(common.Big0, true, 1000)
+	if _, ok := CeloPrecompiles(after)[hazelnutAddr]; !ok {
+		t.Errorf("expected %v to be active after Hazelnut", hazelnutAddr)
+	}
+	if !slices.Contains(ActivePrecompiles(after), hazelnutAddr) {
+		t.Errorf("expected %v to be in active precompiles after Hazelnut", hazelnutAddr)
+	}
+}
@@ -5,5 +5,6 @@ import (
 )
 
 var (
-	ErrInputLength = errors.New("invalid input length")
+	ErrInputLength    = errors.New("invalid input length")
+	ErrInvalidAddress = errors.New("invalid address encoding")
 )
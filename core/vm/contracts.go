@@ -184,6 +184,14 @@ var PrecompiledCeloContractsCel2 = map[common.Address]CeloPrecompiledContract{
 	celoPrecompileAddress(2): &transfer{},
 }
 
+// PrecompiledCeloContractsHazelnut contains the set of pre-compiled contracts
+// used in the Hazelnut release which require the extra celoPrecompileContext.
+var PrecompiledCeloContractsHazelnut = map[common.Address]CeloPrecompiledContract{
+	celoPrecompileAddress(2):  &transfer{},
+	celoPrecompileAddress(48): &feeCurrencyExchangeRate{},
+	celoPrecompileAddress(49): &feeCurrencyIntrinsicGasCost{},
+}
+
 var (
 	PrecompiledAddressesGranite   []common.Address
 	PrecompiledAddressesCel2      []common.Address
@@ -246,18 +254,32 @@ func OptimismPrecompiles(rules params.Rules) []common.Address {
 	}
 }
 
+// CeloPrecompiles returns the precompiles which require the extra
+// celoPrecompileContext, enabled with the current configuration.
+func CeloPrecompiles(rules params.Rules) map[common.Address]CeloPrecompiledContract {
+	switch {
+	case rules.IsHazelnut:
+		return PrecompiledCeloContractsHazelnut
+	case rules.IsCel2:
+		return PrecompiledCeloContractsCel2
+	default:
+		return nil
+	}
+}
+
 // ActivePrecompiles returns the precompiles enabled with the current configuration.
 func ActivePrecompiles(rules params.Rules) []common.Address {
 	addresses := OptimismPrecompiles(rules)
 
-	if !rules.IsCel2 {
+	celoPrecompiles := CeloPrecompiles(rules)
+	if len(celoPrecompiles) == 0 {
 		return addresses
 	}
 
 	PrecompiledAddressesCel2 = PrecompiledAddressesCel2[:0]
 	PrecompiledAddressesCel2 = append(PrecompiledAddressesCel2, addresses...)
 
-	for k := range PrecompiledCeloContractsCel2 {
+	for k := range celoPrecompiles {
 		PrecompiledAddressesCel2 = append(PrecompiledAddressesCel2, k)
 	}
 
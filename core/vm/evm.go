@@ -73,12 +73,7 @@ func (evm *EVM) precompile(addr common.Address) (CeloPrecompiledContract, bool)
 	if ok {
 		cp = &wrap{p}
 	} else {
-		var celoPrecompiles map[common.Address]CeloPrecompiledContract
-		switch {
-		case evm.chainRules.IsCel2:
-			celoPrecompiles = PrecompiledCeloContractsCel2
-		}
-		cp, ok = celoPrecompiles[addr]
+		cp, ok = CeloPrecompiles(evm.chainRules)[addr]
 	}
 
 	return cp, ok
@@ -30,4 +30,4 @@ sleep 0.5
 # although we sent a transaction wih faulty fee-currency twice,
 # the EVM call should have been executed only once
 grep "" debug-fee-currency/geth.partial.log
-if [ "$(grep -Ec "fee-currency EVM execution error, temporarily blocking fee-currency in local txpools .+ This DebugFeeCurrency always fails in \(old\) creditGasFees!" debug-fee-currency/geth.partial.log)" -ne 1 ]; then exit 1; fi
+if [ "$(grep -Ec "fee-currency EVM execution error .+ This DebugFeeCurrency always fails in \(old\) creditGasFees!" debug-fee-currency/geth.partial.log)" -ne 1 ]; then exit 1; fi
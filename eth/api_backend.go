@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -40,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -147,11 +149,26 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 		}
 		return b.eth.blockchain.GetBlock(header.Hash(), header.Number.Uint64()), nil
 	}
-	return b.eth.blockchain.GetBlockByNumber(uint64(number)), nil
+	block := b.eth.blockchain.GetBlockByNumber(uint64(number))
+	if block == nil && uint64(number) < b.HistoryPruningCutoff() {
+		return nil, &ethapi.PrunedHistoryError{}
+	}
+	return block, nil
 }
 
 func (b *EthAPIBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
-	return b.eth.blockchain.GetBlockByHash(hash), nil
+	block := b.eth.blockchain.GetBlockByHash(hash)
+	if block == nil && b.historyPruned(hash) {
+		return nil, &ethapi.PrunedHistoryError{}
+	}
+	return block, nil
+}
+
+// historyPruned returns whether the body and receipts of the block with the
+// given hash were pruned from the chain history.
+func (b *EthAPIBackend) historyPruned(hash common.Hash) bool {
+	header := b.eth.blockchain.GetHeaderByHash(hash)
+	return header != nil && header.Number.Uint64() < b.HistoryPruningCutoff()
 }
 
 // GetBody returns body of a block. It does not resolve special block numbers.
@@ -162,6 +179,9 @@ func (b *EthAPIBackend) GetBody(ctx context.Context, hash common.Hash, number rp
 	if body := b.eth.blockchain.GetBody(hash); body != nil {
 		return body, nil
 	}
+	if uint64(number) < b.HistoryPruningCutoff() {
+		return nil, &ethapi.PrunedHistoryError{}
+	}
 	return nil, errors.New("block body not found")
 }
 
@@ -179,6 +199,9 @@ func (b *EthAPIBackend) BlockByNumberOrHash(ctx context.Context, blockNrOrHash r
 		}
 		block := b.eth.blockchain.GetBlock(hash, header.Number.Uint64())
 		if block == nil {
+			if header.Number.Uint64() < b.HistoryPruningCutoff() {
+				return nil, &ethapi.PrunedHistoryError{}
+			}
 			return nil, errors.New("header found, but block body is missing")
 		}
 		return block, nil
@@ -240,11 +263,19 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 }
 
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
-	return b.eth.blockchain.GetReceiptsByHash(hash), nil
+	receipts := b.eth.blockchain.GetReceiptsByHash(hash)
+	if receipts == nil && b.historyPruned(hash) {
+		return nil, &ethapi.PrunedHistoryError{}
+	}
+	return receipts, nil
 }
 
 func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash, number uint64) ([][]*types.Log, error) {
-	return rawdb.ReadLogs(b.eth.chainDb, hash, number), nil
+	logs := rawdb.ReadLogs(b.eth.chainDb, hash, number)
+	if logs == nil && number < b.HistoryPruningCutoff() {
+		return nil, &ethapi.PrunedHistoryError{}
+	}
+	return logs, nil
 }
 
 func (b *EthAPIBackend) GetTd(ctx context.Context, hash common.Hash) *big.Int {
@@ -438,6 +469,14 @@ func (b *EthAPIBackend) BloomStatus() (uint64, uint64) {
 	return params.BloomBitsBlocks, sections
 }
 
+func (b *EthAPIBackend) LogIndex() *filtermaps.FilterMaps {
+	return b.eth.filterMaps
+}
+
+func (b *EthAPIBackend) HistoryPruningCutoff() uint64 {
+	return b.eth.blockchain.HistoryPruningCutoff()
+}
+
 func (b *EthAPIBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	for i := 0; i < bloomFilterThreads; i++ {
 		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.eth.bloomRequests)
@@ -464,6 +503,14 @@ func (b *EthAPIBackend) HistoricalRPCService() *rpc.Client {
 	return b.eth.historicalRPCService
 }
 
+func (b *EthAPIBackend) TraceCache() *tracers.ResultCache {
+	return b.eth.traceCache
+}
+
 func (b *EthAPIBackend) Genesis() *types.Block {
 	return b.eth.blockchain.Genesis()
 }
+
+func (b *EthAPIBackend) BlockedFeeCurrencies() []common.Address {
+	return b.eth.Miner().BlockedFeeCurrencies()
+}
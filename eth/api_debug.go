@@ -24,9 +24,12 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
@@ -446,3 +449,55 @@ func (api *DebugAPI) GetTrieFlushInterval() (string, error) {
 	}
 	return api.eth.blockchain.GetTrieFlushInterval().String(), nil
 }
+
+// ExecutionWitness re-executes the block with the given number and returns the
+// witness required to execute it statelessly: the state trie nodes and codes
+// accessed during execution, as well as the headers of the ancestors accessed
+// through BLOCKHASH. The block keeps its state and receipt roots, so the result
+// of the stateless execution can be checked against them.
+func (api *DebugAPI) ExecutionWitness(ctx context.Context, blockNr rpc.BlockNumber) (*stateless.Witness, error) {
+	if blockNr == rpc.PendingBlockNumber {
+		return nil, errors.New("witness of the pending block is not available")
+	}
+	block, err := api.eth.APIBackend.BlockByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNr)
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis block has no witness")
+	}
+	return generateWitness(api.eth.blockchain, block)
+}
+
+// generateWitness executes the block on top of its parent state, collecting the
+// accessed state into a witness. The block is validated against the resulting
+// state to ensure the witness is complete.
+//
+// The block is executed without the chain's tracer, which must only see the
+// blocks being imported, and would slow down the execution.
+func generateWitness(bc *core.BlockChain, block *types.Block) (*stateless.Witness, error) {
+	witness, err := stateless.NewWitness(bc, block)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := bc.StateAt(witness.Root())
+	if err != nil {
+		return nil, fmt.Errorf("state of block #%d parent not available: %w", block.NumberU64(), err)
+	}
+	statedb.StartPrefetcher("debug", witness)
+	defer statedb.StopPrefetcher()
+
+	receipts, _, usedGas, err := bc.Processor().Process(block, statedb, vm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to process block #%d: %w", block.NumberU64(), err)
+	}
+	if err := bc.Validator().ValidateState(block, statedb, receipts, usedGas, false); err != nil {
+		return nil, fmt.Errorf("failed to validate block #%d: %w", block.NumberU64(), err)
+	}
+	// Keep the roots the stateless execution has to arrive at
+	witness.Block = block
+	return witness, nil
+}
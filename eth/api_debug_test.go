@@ -18,7 +18,9 @@ package eth
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"slices"
 	"strings"
@@ -26,10 +28,15 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/holiman/uint256"
@@ -221,3 +228,70 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+func TestExecutionWitness(t *testing.T) {
+	t.Parallel()
+
+	var (
+		gspec  = core.DeveloperGenesisBlock(30_000_000, nil)
+		engine = beacon.New(ethash.NewFaker())
+		signer = types.LatestSigner(gspec.Config)
+		aa     = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+	)
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, 2, func(i int, b *core.BlockGen) {
+		b.SetPoS()
+		b.SetParentBeaconRoot(common.Hash{})
+		txs := []types.TxData{
+			&types.DynamicFeeTx{Nonce: uint64(2 * i), To: &aa, Value: big.NewInt(1), Gas: 21000, GasFeeCap: b.BaseFee(), GasTipCap: big.NewInt(2)},
+			// The fee currency debit and credit calls access state outside of the
+			// transaction's own execution.
+			&types.CeloDynamicFeeTxV2{Nonce: uint64(2*i + 1), To: &aa, Value: big.NewInt(1), Gas: 100000, GasFeeCap: new(big.Int).Mul(b.BaseFee(), big.NewInt(4)), GasTipCap: big.NewInt(2), FeeCurrency: &core.DevFeeCurrencyAddr},
+		}
+		for _, txdata := range txs {
+			b.AddTx(types.MustSignNewTx(core.DevPrivateKey, signer, txdata))
+		}
+	})
+	// The chain's tracer must not see the re-executed blocks
+	var traced int
+	tracer := &tracing.Hooks{OnTxStart: func(*tracing.VMContext, *types.Transaction, common.Address) { traced++ }}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{Tracer: tracer}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	traced = 0
+	for _, block := range blocks {
+		witness, err := generateWitness(chain, block)
+		if err != nil {
+			t.Fatalf("block %d: failed to generate witness: %v", block.NumberU64(), err)
+		}
+		// Verify the witness after a roundtrip through its JSON encoding
+		blob, err := json.Marshal(witness)
+		if err != nil {
+			t.Fatalf("block %d: failed to encode witness: %v", block.NumberU64(), err)
+		}
+		var decoded stateless.Witness
+		if err := json.Unmarshal(blob, &decoded); err != nil {
+			t.Fatalf("block %d: failed to decode witness: %v", block.NumberU64(), err)
+		}
+		if decoded.Block.Hash() != block.Hash() {
+			t.Errorf("block %d: witness block mismatch: have %x, want %x", block.NumberU64(), decoded.Block.Hash(), block.Hash())
+		}
+		receiptRoot, stateRoot, err := core.ExecuteStateless(gspec.Config, &decoded)
+		if err != nil {
+			t.Fatalf("block %d: failed to execute statelessly: %v", block.NumberU64(), err)
+		}
+		if receiptRoot != block.ReceiptHash() {
+			t.Errorf("block %d: receipt root mismatch: have %x, want %x", block.NumberU64(), receiptRoot, block.ReceiptHash())
+		}
+		if stateRoot != block.Root() {
+			t.Errorf("block %d: state root mismatch: have %x, want %x", block.NumberU64(), stateRoot, block.Root())
+		}
+	}
+	if traced != 0 {
+		t.Errorf("chain tracer invoked %d times while generating witnesses", traced)
+	}
+}
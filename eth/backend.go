@@ -32,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -49,6 +50,7 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/celoapi"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/internal/sequencerapi"
 	"github.com/ethereum/go-ethereum/internal/shutdowncheck"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/miner"
@@ -59,6 +61,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
 )
 
 // Config contains the configuration options of the ETH protocol.
@@ -90,9 +93,13 @@ type Ethereum struct {
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
 	closeBloomHandler chan struct{}
+	filterMaps        *filtermaps.FilterMaps // Log index, nil if disabled
+	traceCache        *tracers.ResultCache   // Trace result cache, nil if disabled
 
 	APIBackend *EthAPIBackend
 
+	tracerAPIs []rpc.API // APIs exposed by the live tracer
+
 	miner    *miner.Miner
 	gasPrice *big.Int
 
@@ -203,6 +210,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			SnapshotLimit:       config.SnapshotCache,
 			Preimages:           config.Preimages,
 			StateHistory:        config.StateHistory,
+			ChainHistory:        config.HistoryRetain,
 			StateScheme:         scheme,
 		}
 	)
@@ -211,11 +219,12 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		if config.VMTraceJsonConfig != "" {
 			traceConfig = json.RawMessage(config.VMTraceJsonConfig)
 		}
-		t, err := tracers.LiveDirectory.New(config.VMTrace, traceConfig)
+		t, apis, err := tracers.LiveDirectory.NewWithAPIs(config.VMTrace, traceConfig, chainDb)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create tracer %s: %v", config.VMTrace, err)
 		}
 		vmConfig.Tracer = t
+		eth.tracerAPIs = apis
 	}
 	// Override the chain config with provided settings.
 	var overrides core.ChainOverrides
@@ -263,6 +272,19 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	log.Info("Initialising Ethereum protocol", "network", config.NetworkId, "dbversion", dbVer)
 
 	eth.bloomIndexer.Start(eth.blockchain)
+	if config.LogIndex {
+		eth.filterMaps = filtermaps.NewFilterMaps(chainDb, eth.blockchain)
+	}
+	if config.TraceCache > 0 {
+		var traceDb ethdb.KeyValueStore = rawdb.NewMemoryDatabase()
+		if stack.Config().DataDir != "" {
+			traceDb, err = rawdb.NewPebbleDBDatabase(stack.ResolvePath("tracecache"), 16, 16, "eth/db/tracecache/", false, false)
+			if err != nil {
+				return nil, err
+			}
+		}
+		eth.traceCache = tracers.NewResultCache(traceDb, uint64(config.TraceCache)*1024*1024, eth.blockchain)
+	}
 
 	if config.BlobPool.Datadir != "" {
 		config.BlobPool.Datadir = stack.ResolvePath(config.BlobPool.Datadir)
@@ -379,6 +401,17 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Append any APIs exposed by the live tracer
+	apis = append(apis, s.tracerAPIs...)
+
+	// Append the conditional transaction submission if enabled
+	if s.config.RollupSequencerTxConditionalEnabled {
+		apis = append(apis, rpc.API{
+			Namespace: "eth",
+			Service:   sequencerapi.NewConditionalAPI(s.APIBackend, s.seqRPCService, rate.Limit(s.config.RollupSequencerTxConditionalCostRateLimit)),
+		})
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -443,6 +476,9 @@ func (s *Ethereum) Start() error {
 
 	// Start the bloom bits servicing goroutines
 	s.startBloomHandlers(params.BloomBitsBlocks)
+	if s.filterMaps != nil {
+		s.filterMaps.Start()
+	}
 
 	// Regularly update shutdown marker
 	s.shutdownTracker.Start()
@@ -471,6 +507,10 @@ func (s *Ethereum) Stop() error {
 	// Then stop everything else.
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
+	if s.filterMaps != nil {
+		s.filterMaps.Stop()
+	}
+	s.traceCache.Close()
 	s.txPool.Close()
 	s.blockchain.Stop()
 	s.engine.Close()
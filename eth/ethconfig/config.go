@@ -68,6 +68,8 @@ var Defaults = Config{
 	RPCEVMTimeout:      5 * time.Second,
 	GPO:                FullNodeGPO,
 	RPCTxFeeCap:        1, // 1 ether
+
+	RollupSequencerTxConditionalCostRateLimit: 5000,
 }
 
 //go:generate go run github.com/fjl/gencodec -type Config -formats toml -out gen_config.go
@@ -95,6 +97,8 @@ type Config struct {
 	TxLookupLimit      uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 	TransactionHistory uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 	StateHistory       uint64 `toml:",omitempty"` // The maximum number of blocks from head whose state histories are reserved.
+	LogIndex           bool   `toml:",omitempty"` // Whether to maintain a log index to speed up log searches.
+	HistoryRetain      uint64 `toml:",omitempty"` // The maximum number of blocks from head whose bodies and receipts are reserved, 0 for the entire chain.
 
 	// State scheme represents the scheme used to store ethereum states and trie
 	// nodes on top. It can be 'hash', 'path', or none which means use the scheme
@@ -162,6 +166,10 @@ type Config struct {
 	// send-transaction variants. The unit is ether.
 	RPCTxFeeCap float64
 
+	// TraceCache is the size in megabytes of the persistent cache of block
+	// trace results, 0 disables the cache.
+	TraceCache int `toml:",omitempty"`
+
 	// OverrideCancun (TODO: remove after the fork)
 	OverrideCancun *uint64 `toml:",omitempty"`
 
@@ -190,6 +198,9 @@ type Config struct {
 	RollupDisableTxPoolGossip               bool
 	RollupDisableTxPoolAdmission            bool
 	RollupHaltOnIncompatibleProtocolVersion string
+
+	RollupSequencerTxConditionalEnabled       bool
+	RollupSequencerTxConditionalCostRateLimit int
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
@@ -17,61 +17,66 @@ import (
 // MarshalTOML marshals as TOML.
 func (c Config) MarshalTOML() (interface{}, error) {
 	type Config struct {
-		Genesis                                 *core.Genesis `toml:",omitempty"`
-		NetworkId                               uint64
-		SyncMode                                downloader.SyncMode
-		EthDiscoveryURLs                        []string
-		SnapDiscoveryURLs                       []string
-		NoPruning                               bool
-		NoPrefetch                              bool
-		TxLookupLimit                           uint64                 `toml:",omitempty"`
-		TransactionHistory                      uint64                 `toml:",omitempty"`
-		StateHistory                            uint64                 `toml:",omitempty"`
-		StateScheme                             string                 `toml:",omitempty"`
-		RequiredBlocks                          map[uint64]common.Hash `toml:"-"`
-		LightServ                               int                    `toml:",omitempty"`
-		LightIngress                            int                    `toml:",omitempty"`
-		LightEgress                             int                    `toml:",omitempty"`
-		LightPeers                              int                    `toml:",omitempty"`
-		LightNoPrune                            bool                   `toml:",omitempty"`
-		LightNoSyncServe                        bool                   `toml:",omitempty"`
-		SkipBcVersionCheck                      bool                   `toml:"-"`
-		DatabaseHandles                         int                    `toml:"-"`
-		DatabaseCache                           int
-		DatabaseFreezer                         string
-		TrieCleanCache                          int
-		TrieDirtyCache                          int
-		TrieTimeout                             time.Duration
-		SnapshotCache                           int
-		Preimages                               bool
-		FilterLogCacheSize                      int
-		Miner                                   miner.Config
-		TxPool                                  legacypool.Config
-		BlobPool                                blobpool.Config
-		GPO                                     gasprice.Config
-		EnablePreimageRecording                 bool
-		EnableWitnessCollection                 bool `toml:"-"`
-		VMTrace                                 string
-		VMTraceJsonConfig                       string
-		DocRoot                                 string `toml:"-"`
-		RPCGasCap                               uint64
-		RPCEVMTimeout                           time.Duration
-		RPCTxFeeCap                             float64
-		OverrideCancun                          *uint64 `toml:",omitempty"`
-		OverrideVerkle                          *uint64 `toml:",omitempty"`
-		OverrideOptimismCanyon                  *uint64 `toml:",omitempty"`
-		OverrideOptimismEcotone                 *uint64 `toml:",omitempty"`
-		OverrideOptimismFjord                   *uint64 `toml:",omitempty"`
-		OverrideOptimismGranite                 *uint64 `toml:",omitempty"`
-		OverrideOptimismHolocene                *uint64 `toml:",omitempty"`
-		OverrideOptimismInterop                 *uint64 `toml:",omitempty"`
-		ApplySuperchainUpgrades                 bool    `toml:",omitempty"`
-		RollupSequencerHTTP                     string
-		RollupHistoricalRPC                     string
-		RollupHistoricalRPCTimeout              time.Duration
-		RollupDisableTxPoolGossip               bool
-		RollupDisableTxPoolAdmission            bool
-		RollupHaltOnIncompatibleProtocolVersion string
+		Genesis                                   *core.Genesis `toml:",omitempty"`
+		NetworkId                                 uint64
+		SyncMode                                  downloader.SyncMode
+		EthDiscoveryURLs                          []string
+		SnapDiscoveryURLs                         []string
+		NoPruning                                 bool
+		NoPrefetch                                bool
+		TxLookupLimit                             uint64                 `toml:",omitempty"`
+		TransactionHistory                        uint64                 `toml:",omitempty"`
+		StateHistory                              uint64                 `toml:",omitempty"`
+		LogIndex                                  bool                   `toml:",omitempty"`
+		HistoryRetain                             uint64                 `toml:",omitempty"`
+		StateScheme                               string                 `toml:",omitempty"`
+		RequiredBlocks                            map[uint64]common.Hash `toml:"-"`
+		LightServ                                 int                    `toml:",omitempty"`
+		LightIngress                              int                    `toml:",omitempty"`
+		LightEgress                               int                    `toml:",omitempty"`
+		LightPeers                                int                    `toml:",omitempty"`
+		LightNoPrune                              bool                   `toml:",omitempty"`
+		LightNoSyncServe                          bool                   `toml:",omitempty"`
+		SkipBcVersionCheck                        bool                   `toml:"-"`
+		DatabaseHandles                           int                    `toml:"-"`
+		DatabaseCache                             int
+		DatabaseFreezer                           string
+		TrieCleanCache                            int
+		TrieDirtyCache                            int
+		TrieTimeout                               time.Duration
+		SnapshotCache                             int
+		Preimages                                 bool
+		FilterLogCacheSize                        int
+		Miner                                     miner.Config
+		TxPool                                    legacypool.Config
+		BlobPool                                  blobpool.Config
+		GPO                                       gasprice.Config
+		EnablePreimageRecording                   bool
+		EnableWitnessCollection                   bool `toml:"-"`
+		VMTrace                                   string
+		VMTraceJsonConfig                         string
+		DocRoot                                   string `toml:"-"`
+		RPCGasCap                                 uint64
+		RPCEVMTimeout                             time.Duration
+		RPCTxFeeCap                               float64
+		TraceCache                                int     `toml:",omitempty"`
+		OverrideCancun                            *uint64 `toml:",omitempty"`
+		OverrideVerkle                            *uint64 `toml:",omitempty"`
+		OverrideOptimismCanyon                    *uint64 `toml:",omitempty"`
+		OverrideOptimismEcotone                   *uint64 `toml:",omitempty"`
+		OverrideOptimismFjord                     *uint64 `toml:",omitempty"`
+		OverrideOptimismGranite                   *uint64 `toml:",omitempty"`
+		OverrideOptimismHolocene                  *uint64 `toml:",omitempty"`
+		OverrideOptimismInterop                   *uint64 `toml:",omitempty"`
+		ApplySuperchainUpgrades                   bool    `toml:",omitempty"`
+		RollupSequencerHTTP                       string
+		RollupHistoricalRPC                       string
+		RollupHistoricalRPCTimeout                time.Duration
+		RollupDisableTxPoolGossip                 bool
+		RollupDisableTxPoolAdmission              bool
+		RollupHaltOnIncompatibleProtocolVersion   string
+		RollupSequencerTxConditionalEnabled       bool
+		RollupSequencerTxConditionalCostRateLimit int
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -84,6 +89,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TxLookupLimit = c.TxLookupLimit
 	enc.TransactionHistory = c.TransactionHistory
 	enc.StateHistory = c.StateHistory
+	enc.LogIndex = c.LogIndex
+	enc.HistoryRetain = c.HistoryRetain
 	enc.StateScheme = c.StateScheme
 	enc.RequiredBlocks = c.RequiredBlocks
 	enc.LightServ = c.LightServ
@@ -114,6 +121,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RPCGasCap = c.RPCGasCap
 	enc.RPCEVMTimeout = c.RPCEVMTimeout
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
+	enc.TraceCache = c.TraceCache
 	enc.OverrideCancun = c.OverrideCancun
 	enc.OverrideVerkle = c.OverrideVerkle
 	enc.OverrideOptimismCanyon = c.OverrideOptimismCanyon
@@ -129,67 +137,74 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RollupDisableTxPoolGossip = c.RollupDisableTxPoolGossip
 	enc.RollupDisableTxPoolAdmission = c.RollupDisableTxPoolAdmission
 	enc.RollupHaltOnIncompatibleProtocolVersion = c.RollupHaltOnIncompatibleProtocolVersion
+	enc.RollupSequencerTxConditionalEnabled = c.RollupSequencerTxConditionalEnabled
+	enc.RollupSequencerTxConditionalCostRateLimit = c.RollupSequencerTxConditionalCostRateLimit
 	return &enc, nil
 }
 
 // UnmarshalTOML unmarshals from TOML.
 func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	type Config struct {
-		Genesis                                 *core.Genesis `toml:",omitempty"`
-		NetworkId                               *uint64
-		SyncMode                                *downloader.SyncMode
-		EthDiscoveryURLs                        []string
-		SnapDiscoveryURLs                       []string
-		NoPruning                               *bool
-		NoPrefetch                              *bool
-		TxLookupLimit                           *uint64                `toml:",omitempty"`
-		TransactionHistory                      *uint64                `toml:",omitempty"`
-		StateHistory                            *uint64                `toml:",omitempty"`
-		StateScheme                             *string                `toml:",omitempty"`
-		RequiredBlocks                          map[uint64]common.Hash `toml:"-"`
-		LightServ                               *int                   `toml:",omitempty"`
-		LightIngress                            *int                   `toml:",omitempty"`
-		LightEgress                             *int                   `toml:",omitempty"`
-		LightPeers                              *int                   `toml:",omitempty"`
-		LightNoPrune                            *bool                  `toml:",omitempty"`
-		LightNoSyncServe                        *bool                  `toml:",omitempty"`
-		SkipBcVersionCheck                      *bool                  `toml:"-"`
-		DatabaseHandles                         *int                   `toml:"-"`
-		DatabaseCache                           *int
-		DatabaseFreezer                         *string
-		TrieCleanCache                          *int
-		TrieDirtyCache                          *int
-		TrieTimeout                             *time.Duration
-		SnapshotCache                           *int
-		Preimages                               *bool
-		FilterLogCacheSize                      *int
-		Miner                                   *miner.Config
-		TxPool                                  *legacypool.Config
-		BlobPool                                *blobpool.Config
-		GPO                                     *gasprice.Config
-		EnablePreimageRecording                 *bool
-		EnableWitnessCollection                 *bool `toml:"-"`
-		VMTrace                                 *string
-		VMTraceJsonConfig                       *string
-		DocRoot                                 *string `toml:"-"`
-		RPCGasCap                               *uint64
-		RPCEVMTimeout                           *time.Duration
-		RPCTxFeeCap                             *float64
-		OverrideCancun                          *uint64 `toml:",omitempty"`
-		OverrideVerkle                          *uint64 `toml:",omitempty"`
-		OverrideOptimismCanyon                  *uint64 `toml:",omitempty"`
-		OverrideOptimismEcotone                 *uint64 `toml:",omitempty"`
-		OverrideOptimismFjord                   *uint64 `toml:",omitempty"`
-		OverrideOptimismGranite                 *uint64 `toml:",omitempty"`
-		OverrideOptimismHolocene                *uint64 `toml:",omitempty"`
-		OverrideOptimismInterop                 *uint64 `toml:",omitempty"`
-		ApplySuperchainUpgrades                 *bool   `toml:",omitempty"`
-		RollupSequencerHTTP                     *string
-		RollupHistoricalRPC                     *string
-		RollupHistoricalRPCTimeout              *time.Duration
-		RollupDisableTxPoolGossip               *bool
-		RollupDisableTxPoolAdmission            *bool
-		RollupHaltOnIncompatibleProtocolVersion *string
+		Genesis                                   *core.Genesis `toml:",omitempty"`
+		NetworkId                                 *uint64
+		SyncMode                                  *downloader.SyncMode
+		EthDiscoveryURLs                          []string
+		SnapDiscoveryURLs                         []string
+		NoPruning                                 *bool
+		NoPrefetch                                *bool
+		TxLookupLimit                             *uint64                `toml:",omitempty"`
+		TransactionHistory                        *uint64                `toml:",omitempty"`
+		StateHistory                              *uint64                `toml:",omitempty"`
+		LogIndex                                  *bool                  `toml:",omitempty"`
+		HistoryRetain                             *uint64                `toml:",omitempty"`
+		StateScheme                               *string                `toml:",omitempty"`
+		RequiredBlocks                            map[uint64]common.Hash `toml:"-"`
+		LightServ                                 *int                   `toml:",omitempty"`
+		LightIngress                              *int                   `toml:",omitempty"`
+		LightEgress                               *int                   `toml:",omitempty"`
+		LightPeers                                *int                   `toml:",omitempty"`
+		LightNoPrune                              *bool                  `toml:",omitempty"`
+		LightNoSyncServe                          *bool                  `toml:",omitempty"`
+		SkipBcVersionCheck                        *bool                  `toml:"-"`
+		DatabaseHandles                           *int                   `toml:"-"`
+		DatabaseCache                             *int
+		DatabaseFreezer                           *string
+		TrieCleanCache                            *int
+		TrieDirtyCache                            *int
+		TrieTimeout                               *time.Duration
+		SnapshotCache                             *int
+		Preimages                                 *bool
+		FilterLogCacheSize                        *int
+		Miner                                     *miner.Config
+		TxPool                                    *legacypool.Config
+		BlobPool                                  *blobpool.Config
+		GPO                                       *gasprice.Config
+		EnablePreimageRecording                   *bool
+		EnableWitnessCollection                   *bool `toml:"-"`
+		VMTrace                                   *string
+		VMTraceJsonConfig                         *string
+		DocRoot                                   *string `toml:"-"`
+		RPCGasCap                                 *uint64
+		RPCEVMTimeout                             *time.Duration
+		RPCTxFeeCap                               *float64
+		TraceCache                                *int    `toml:",omitempty"`
+		OverrideCancun                            *uint64 `toml:",omitempty"`
+		OverrideVerkle                            *uint64 `toml:",omitempty"`
+		OverrideOptimismCanyon                    *uint64 `toml:",omitempty"`
+		OverrideOptimismEcotone                   *uint64 `toml:",omitempty"`
+		OverrideOptimismFjord                     *uint64 `toml:",omitempty"`
+		OverrideOptimismGranite                   *uint64 `toml:",omitempty"`
+		OverrideOptimismHolocene                  *uint64 `toml:",omitempty"`
+		OverrideOptimismInterop                   *uint64 `toml:",omitempty"`
+		ApplySuperchainUpgrades                   *bool   `toml:",omitempty"`
+		RollupSequencerHTTP                       *string
+		RollupHistoricalRPC                       *string
+		RollupHistoricalRPCTimeout                *time.Duration
+		RollupDisableTxPoolGossip                 *bool
+		RollupDisableTxPoolAdmission              *bool
+		RollupHaltOnIncompatibleProtocolVersion   *string
+		RollupSequencerTxConditionalEnabled       *bool
+		RollupSequencerTxConditionalCostRateLimit *int
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -225,6 +240,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.StateHistory != nil {
 		c.StateHistory = *dec.StateHistory
 	}
+	if dec.LogIndex != nil {
+		c.LogIndex = *dec.LogIndex
+	}
+	if dec.HistoryRetain != nil {
+		c.HistoryRetain = *dec.HistoryRetain
+	}
 	if dec.StateScheme != nil {
 		c.StateScheme = *dec.StateScheme
 	}
@@ -315,6 +336,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RPCTxFeeCap != nil {
 		c.RPCTxFeeCap = *dec.RPCTxFeeCap
 	}
+	if dec.TraceCache != nil {
+		c.TraceCache = *dec.TraceCache
+	}
 	if dec.OverrideCancun != nil {
 		c.OverrideCancun = dec.OverrideCancun
 	}
@@ -360,5 +384,11 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RollupHaltOnIncompatibleProtocolVersion != nil {
 		c.RollupHaltOnIncompatibleProtocolVersion = *dec.RollupHaltOnIncompatibleProtocolVersion
 	}
+	if dec.RollupSequencerTxConditionalEnabled != nil {
+		c.RollupSequencerTxConditionalEnabled = *dec.RollupSequencerTxConditionalEnabled
+	}
+	if dec.RollupSequencerTxConditionalCostRateLimit != nil {
+		c.RollupSequencerTxConditionalCostRateLimit = *dec.RollupSequencerTxConditionalCostRateLimit
+	}
 	return nil
 }
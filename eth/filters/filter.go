@@ -24,7 +24,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -147,6 +149,9 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if f.end, err = resolveSpecial(f.end); err != nil {
 		return nil, err
 	}
+	if f.begin >= 0 && uint64(f.begin) < f.sys.backend.HistoryPruningCutoff() {
+		return nil, &ethapi.PrunedHistoryError{}
+	}
 
 	logChan, errChan := f.rangeLogsAsync(ctx)
 	var logs []*types.Log
@@ -180,7 +185,16 @@ func (f *Filter) rangeLogsAsync(ctx context.Context) (chan *types.Log, chan erro
 			size, sections = f.sys.backend.BloomStatus()
 			err            error
 		)
-		if indexed := sections * size; indexed > uint64(f.begin) {
+		// Prefer the log index over the bloom bits where it covers the range
+		if index := f.sys.backend.LogIndex(); index != nil && f.indexable() {
+			if first, last, ok := index.IndexedRange(); ok && first <= uint64(f.begin) && uint64(f.begin) <= last {
+				if err = f.logIndexLogs(ctx, index, min(end, last), logChan); err != nil {
+					errChan <- err
+					return
+				}
+			}
+		}
+		if indexed := sections * size; indexed > uint64(f.begin) && uint64(f.begin) <= end {
 			if indexed > end {
 				indexed = end + 1
 			}
@@ -247,6 +261,45 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64, logChan chan *type
 	}
 }
 
+// indexable returns whether the filter criteria can be served by the log index,
+// which requires at least one address or topic to be specified.
+func (f *Filter) indexable() bool {
+	return len(f.addresses) > 0 || slices.ContainsFunc(f.topics, func(sub []common.Hash) bool { return len(sub) > 0 })
+}
+
+// logIndexLogs returns the logs matching the filter criteria based on the log
+// index, which must cover the range up to end.
+func (f *Filter) logIndexLogs(ctx context.Context, index *filtermaps.FilterMaps, end uint64, logChan chan *types.Log) error {
+	err := index.Matches(ctx, uint64(f.begin), end, f.addresses, f.topics, func(number uint64) error {
+		f.begin = int64(number) + 1
+
+		// Retrieve the suggested block and pull any truly matching logs
+		header, err := f.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return err
+		}
+		if header == nil {
+			return errors.New("unknown block")
+		}
+		found, err := f.checkMatches(ctx, header)
+		if err != nil {
+			return err
+		}
+		for _, log := range found {
+			select {
+			case logChan <- log:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		f.begin = int64(end) + 1
+	}
+	return err
+}
+
 // unindexedLogs returns the logs matching the filter criteria based on raw block
 // iteration and bloom matching.
 func (f *Filter) unindexedLogs(ctx context.Context, end uint64, logChan chan *types.Log) error {
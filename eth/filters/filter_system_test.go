@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -48,6 +49,8 @@ type testBackend struct {
 	chainFeed       event.Feed
 	pendingBlock    *types.Block
 	pendingReceipts types.Receipts
+	logIndex        *filtermaps.FilterMaps
+	historyCutoff   uint64
 }
 
 func (b *testBackend) ChainConfig() *params.ChainConfig {
@@ -141,6 +144,14 @@ func (b *testBackend) BloomStatus() (uint64, uint64) {
 	return params.BloomBitsBlocks, b.sections
 }
 
+func (b *testBackend) LogIndex() *filtermaps.FilterMaps {
+	return b.logIndex
+}
+
+func (b *testBackend) HistoryPruningCutoff() uint64 {
+	return b.historyCutoff
+}
+
 func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	requests := make(chan chan *bloombits.Retrieval)
 
@@ -464,6 +475,24 @@ func TestInvalidGetRangeLogsRequest(t *testing.T) {
 	}
 }
 
+// TestPrunedGetRangeLogsRequest tests getLogs with a block range reaching into
+// the pruned chain history.
+func TestPrunedGetRangeLogsRequest(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		api          = NewFilterAPI(sys)
+	)
+	backend.historyCutoff = 10
+
+	_, err := api.GetLogs(context.Background(), FilterCriteria{FromBlock: big.NewInt(5), ToBlock: big.NewInt(20)})
+	if _, ok := err.(*ethapi.PrunedHistoryError); !ok {
+		t.Errorf("Expected pruned history error, but got: %v", err)
+	}
+}
+
 // TestLogFilter tests whether log filters match the correct logs that are posted to the event feed.
 func TestLogFilter(t *testing.T) {
 	t.Parallel()
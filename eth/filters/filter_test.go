@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -108,6 +109,14 @@ func BenchmarkFilters(b *testing.B) {
 }
 
 func TestFilters(t *testing.T) {
+	testFilters(t, false)
+}
+
+func TestFiltersLogIndex(t *testing.T) {
+	testFilters(t, true)
+}
+
+func testFilters(t *testing.T, logIndex bool) {
 	var (
 		db           = rawdb.NewMemoryDatabase()
 		backend, sys = newTestFilterSystem(t, db, Config{})
@@ -258,6 +267,20 @@ func TestFilters(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	if logIndex {
+		backend.logIndex = filtermaps.NewFilterMaps(db, bc)
+		backend.logIndex.Start()
+		defer backend.logIndex.Stop()
+
+		for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+			if _, last, ok := backend.logIndex.IndexedRange(); ok && last == uint64(len(chain)) {
+				break
+			}
+			if time.Since(start) > 10*time.Second {
+				t.Fatal("log index not synced")
+			}
+		}
+	}
 
 	// Set block 998 as Finalized (-3)
 	bc.SetFinalized(chain[998].Header())
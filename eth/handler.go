@@ -494,6 +494,11 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		hash   = make([]byte, 32)
 	)
 	for _, tx := range txs {
+		// Conditional transactions are only submitted to the sequencer, don't
+		// leak them to the network.
+		if tx.Conditional() != nil {
+			continue
+		}
 		var maybeDirect bool
 		switch {
 		case tx.Type() == types.BlobTxType:
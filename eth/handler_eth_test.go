@@ -438,3 +438,97 @@ func testTransactionPropagation(t *testing.T, protocol uint) {
 		}
 	}
 }
+
+// Tests that conditional transactions are not propagated to peers, neither on
+// the initial sync nor when they are added to the pool.
+func TestConditionalTransactionPropagation68(t *testing.T) {
+	testConditionalTransactionPropagation(t, eth.ETH68)
+}
+
+func testConditionalTransactionPropagation(t *testing.T, protocol uint) {
+	t.Parallel()
+
+	source := newTestHandler()
+	source.handler.snapSync.Store(false) // Avoid requiring snap, otherwise some will be dropped below
+	defer source.close()
+
+	// Create transactions, every other one conditional
+	var (
+		txs  = make([]*types.Transaction, 64)
+		want = make(map[common.Hash]struct{})
+	)
+	for nonce := range txs {
+		tx := types.NewTransaction(uint64(nonce), common.Address{}, big.NewInt(0), 100000, big.NewInt(0), nil)
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, testKey)
+		if nonce%2 == 0 {
+			tx.SetConditional(&types.TransactionConditional{})
+		} else {
+			want[tx.Hash()] = struct{}{}
+		}
+		txs[nonce] = tx
+	}
+	// Add the first half before any peer connects to exercise the initial sync
+	source.txpool.Add(txs[:len(txs)/2], false, false)
+
+	sinks := make([]*testHandler, 4)
+	for i := 0; i < len(sinks); i++ {
+		sinks[i] = newTestHandler()
+		defer sinks[i].close()
+
+		sinks[i].handler.synced.Store(true) // mark synced to accept transactions
+	}
+	txChs := make([]chan core.NewTxsEvent, len(sinks))
+	for i := 0; i < len(sinks); i++ {
+		txChs[i] = make(chan core.NewTxsEvent, 1024)
+
+		sub := sinks[i].txpool.SubscribeTransactions(txChs[i], false)
+		defer sub.Unsubscribe()
+	}
+	for i, sink := range sinks {
+		sink := sink // Closure for goroutine below
+
+		sourcePipe, sinkPipe := p2p.MsgPipe()
+		defer sourcePipe.Close()
+		defer sinkPipe.Close()
+
+		sourcePeer := eth.NewPeer(protocol, p2p.NewPeerPipe(enode.ID{byte(i + 1)}, "", nil, sourcePipe), sourcePipe, source.txpool)
+		sinkPeer := eth.NewPeer(protocol, p2p.NewPeerPipe(enode.ID{0}, "", nil, sinkPipe), sinkPipe, sink.txpool)
+		defer sourcePeer.Close()
+		defer sinkPeer.Close()
+
+		go source.handler.runEthPeer(sourcePeer, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(source.handler), peer)
+		})
+		go sink.handler.runEthPeer(sinkPeer, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(sink.handler), peer)
+		})
+	}
+	time.Sleep(250 * time.Millisecond) // Wait for the peers to connect, the broadcaster races with peer join
+	source.txpool.Add(txs[len(txs)/2:], false, false)
+
+	// Ensure all sinks got the plain transactions and none of the conditional
+	// ones, waiting a bit after the last expected one for any stragglers
+	for i := range sinks {
+		seen := make(map[common.Hash]struct{})
+		for timeout := false; !timeout; {
+			wait := 2 * time.Second
+			if len(seen) == len(want) {
+				wait = 250 * time.Millisecond
+			}
+			select {
+			case event := <-txChs[i]:
+				for _, tx := range event.Txs {
+					if _, ok := want[tx.Hash()]; !ok {
+						t.Errorf("sink %d: conditional transaction propagated: %x", i, tx.Hash())
+					}
+					seen[tx.Hash()] = struct{}{}
+				}
+			case <-time.After(wait):
+				timeout = true
+			}
+		}
+		if len(seen) != len(want) {
+			t.Errorf("sink %d: transaction propagation mismatch: have %d, want %d", i, len(seen), len(want))
+		}
+	}
+}
@@ -393,9 +393,10 @@ func answerGetPooledTransactions(backend Backend, query GetPooledTransactionsReq
 		if bytes >= softResponseLimit {
 			break
 		}
-		// Retrieve the requested transaction, skipping if unknown to us
+		// Retrieve the requested transaction, skipping if unknown to us or
+		// conditional, as those are not propagated
 		tx := backend.TxPool().Get(hash)
-		if tx == nil {
+		if tx == nil || tx.Conditional() != nil {
 			continue
 		}
 		// If known, encode and queue for response packet
@@ -27,6 +27,9 @@ func (h *handler) syncTransactions(p *eth.Peer) {
 	var hashes []common.Hash
 	for _, batch := range h.txpool.Pending(txpool.PendingFilter{OnlyPlainTxs: true}) {
 		for _, tx := range batch {
+			if tx.Tx != nil && tx.Tx.Conditional() != nil {
+				continue // Conditional transactions are not propagated
+			}
 			hashes = append(hashes, tx.Hash)
 		}
 	}
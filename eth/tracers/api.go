@@ -87,16 +87,18 @@ type Backend interface {
 	StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, readOnly bool, preferDisk bool) (*state.StateDB, StateReleaseFunc, error)
 	StateAtTransaction(ctx context.Context, block *types.Block, txIndex int, reexec uint64) (*types.Transaction, vm.BlockContext, *state.StateDB, StateReleaseFunc, error)
 	HistoricalRPCService() *rpc.Client
+	TraceCache() *ResultCache
 }
 
 // API is the collection of tracing APIs exposed over the private debugging endpoint.
 type API struct {
 	backend Backend
+	streams chan struct{} // Semaphore limiting the concurrent trace streams
 }
 
 // NewAPI creates a new API definition for the tracing methods of the Ethereum service.
 func NewAPI(backend Backend) *API {
-	return &API{backend: backend}
+	return &API{backend: backend, streams: make(chan struct{}, maxTraceStreams)}
 }
 
 // chainContext constructs the context reader which is used by the evm for reading
@@ -456,7 +458,7 @@ func (api *API) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber,
 		}
 	}
 
-	return api.traceBlock(ctx, block, config)
+	return api.traceCanonicalBlock(ctx, block, config)
 }
 
 // TraceBlockByHash returns the structured logs created during the execution of
@@ -480,7 +482,7 @@ func (api *API) TraceBlockByHash(ctx context.Context, hash common.Hash, config *
 		}
 	}
 
-	return api.traceBlock(ctx, block, config)
+	return api.traceCanonicalBlock(ctx, block, config)
 }
 
 // TraceBlock returns the structured logs created during the execution of EVM
@@ -602,6 +604,30 @@ func (api *API) StandardTraceBadBlockToFile(ctx context.Context, hash common.Has
 	return api.standardTraceBlockToFile(ctx, block, config)
 }
 
+// traceCanonicalBlock traces the given block like traceBlock, serving the results
+// from the trace result cache if available. Only the results of canonical blocks
+// are cached, as these are dropped from the cache on reorgs. Failed traces may
+// be caused by timeouts and are not cached.
+func (api *API) traceCanonicalBlock(ctx context.Context, block *types.Block, config *TraceConfig) ([]*txTraceResult, error) {
+	cache := api.backend.TraceCache()
+	if results, ok := cache.get(block.Hash(), config); ok {
+		return results, nil
+	}
+	results, err := api.traceBlock(ctx, block, config)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		if res.Error != "" {
+			return results, nil
+		}
+	}
+	if rawdb.ReadCanonicalHash(api.backend.ChainDb(), block.NumberU64()) == block.Hash() {
+		cache.put(block.Hash(), config, results)
+	}
+	return results, nil
+}
+
 // traceBlock configures a new tracer according to the provided configuration, and
 // executes all the transactions contained within. The return value will be one item
 // per transaction, dependent on the requested tracer.
@@ -935,32 +961,10 @@ func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *
 // the trace will be conducted on the state after executing the specified transaction
 // within the specified block.
 func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
-	// Try to retrieve the specified block
-	var (
-		err     error
-		block   *types.Block
-		statedb *state.StateDB
-		release StateReleaseFunc
-	)
-	if hash, ok := blockNrOrHash.Hash(); ok {
-		block, err = api.blockByHash(ctx, hash)
-	} else if number, ok := blockNrOrHash.Number(); ok {
-		if number == rpc.PendingBlockNumber {
-			// We don't have access to the miner here. For tracing 'future' transactions,
-			// it can be done with block- and state-overrides instead, which offers
-			// more flexibility and stability than trying to trace on 'pending', since
-			// the contents of 'pending' is unstable and probably not a true representation
-			// of what the next actual block is likely to contain.
-			return nil, errors.New("tracing on top of pending is not supported")
-		}
-		block, err = api.blockByNumber(ctx, number)
-	} else {
-		return nil, errors.New("invalid arguments; neither block nor hash specified")
-	}
+	block, err := api.callBlock(ctx, blockNrOrHash)
 	if err != nil {
 		return nil, err
 	}
-
 	if api.backend.ChainConfig().IsOptimismPreBedrock(block.Number()) {
 		if api.backend.HistoricalRPCService() != nil {
 			var histResult json.RawMessage
@@ -972,18 +976,7 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 		}
 		return nil, rpc.ErrNoHistoricalFallback
 	}
-
-	// try to recompute the state
-	reexec := defaultTraceReexec
-	if config != nil && config.Reexec != nil {
-		reexec = *config.Reexec
-	}
-
-	if config != nil && config.TxIndex != nil {
-		_, _, statedb, release, err = api.backend.StateAtTransaction(ctx, block, int(*config.TxIndex), reexec)
-	} else {
-		statedb, release, err = api.backend.StateAtBlock(ctx, block, reexec, nil, true, false)
-	}
+	statedb, release, err := api.callState(ctx, block, config)
 	if err != nil {
 		return nil, err
 	}
@@ -1012,50 +1005,229 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 	return api.traceTx(ctx, tx, msg, new(Context), vmctx, statedb, traceConfig)
 }
 
+// Bundle is a batch of calls traced by TraceCallMany, sharing the same block
+// overrides.
+type Bundle struct {
+	Transactions  []ethapi.TransactionArgs `json:"transactions"`
+	BlockOverride *ethapi.BlockOverrides   `json:"blockOverride"`
+}
+
+// TraceCallMany lets you trace a list of bundles of calls, executed one after
+// the other on top of the state of the given block. The state changes of each
+// call are visible to the following ones. The state overrides of the config
+// are applied once before the first call, the block overrides of the config
+// apply to all bundles and are extended by the block overrides of each bundle.
+// The calls share the RPC gas cap, i.e. the gas used by the calls adds up to at
+// most the gas cap. It returns the traces of the calls, grouped by bundle.
+func (api *API) TraceCallMany(ctx context.Context, bundles []Bundle, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) ([][]interface{}, error) {
+	if len(bundles) == 0 {
+		return nil, errors.New("empty bundles")
+	}
+	block, err := api.callBlock(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if api.backend.ChainConfig().IsOptimismPreBedrock(block.Number()) {
+		if api.backend.HistoricalRPCService() != nil {
+			var histResult [][]interface{}
+			err := api.backend.HistoricalRPCService().CallContext(ctx, &histResult, "debug_traceCallMany", bundles, blockNrOrHash, config)
+			if err != nil {
+				return nil, fmt.Errorf("historical backend error: %w", err)
+			}
+			return histResult, nil
+		}
+		return nil, rpc.ErrNoHistoricalFallback
+	}
+	statedb, release, err := api.callState(ctx, block, config)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var (
+		traceConfig  *TraceConfig
+		traceTimeout *string
+	)
+	if config != nil {
+		if err := config.StateOverrides.Apply(statedb); err != nil {
+			return nil, err
+		}
+		traceConfig, traceTimeout = &config.TraceConfig, config.Timeout
+	}
+	timeout, err := parseTraceTimeout(traceTimeout, defaultTraceTimeout)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		results = make([][]interface{}, len(bundles))
+		txIndex int
+		gasCap  = api.backend.RPCGasCap()
+	)
+	// The calls are traced as if they followed the transaction they're traced on
+	if config != nil && config.TxIndex != nil {
+		txIndex = int(*config.TxIndex)
+	}
+	for i, bundle := range bundles {
+		// The fee currency context is derived from the state, which may have
+		// been changed by the previous bundles.
+		vmctx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil, api.backend.ChainConfig(), statedb)
+		if config != nil {
+			config.BlockOverrides.Apply(&vmctx)
+		}
+		bundle.BlockOverride.Apply(&vmctx)
+
+		results[i] = make([]interface{}, len(bundle.Transactions))
+		for j, args := range bundle.Transactions {
+			// A gas cap of zero means no cap, so it can't be passed on once used up
+			if api.backend.RPCGasCap() != 0 && gasCap == 0 {
+				return nil, fmt.Errorf("bundle %d, call %d: gas cap of %d exhausted", i, j, api.backend.RPCGasCap())
+			}
+			if err := args.CallDefaults(gasCap, vmctx.BaseFee, api.backend.ChainConfig().ChainID); err != nil {
+				return nil, fmt.Errorf("bundle %d, call %d: %w", i, j, err)
+			}
+			var (
+				msg   = args.ToMessage(vmctx.BaseFee, vmctx.FeeCurrencyContext.ExchangeRates)
+				tx    = args.ToTransaction()
+				txctx = &Context{TxIndex: txIndex, TxHash: tx.Hash()}
+			)
+			tracer, err := newTracer(txctx, traceConfig)
+			if err != nil {
+				return nil, fmt.Errorf("bundle %d, call %d: %w", i, j, err)
+			}
+			// Track the gas used by the call, to charge it to the gas cap
+			var (
+				hooks   = *tracer.Hooks
+				usedGas uint64
+			)
+			hooks.OnTxEnd = func(receipt *types.Receipt, err error) {
+				if receipt != nil {
+					usedGas = receipt.GasUsed
+				}
+				if tracer.Hooks.OnTxEnd != nil {
+					tracer.Hooks.OnTxEnd(receipt, err)
+				}
+			}
+			res, err := api.traceTxWithTracer(ctx, tx, msg, txctx, vmctx, statedb, &Tracer{Hooks: &hooks, GetResult: tracer.GetResult, Stop: tracer.Stop}, timeout)
+			if err != nil {
+				return nil, fmt.Errorf("bundle %d, call %d: %w", i, j, err)
+			}
+			results[i][j] = res
+			txIndex++
+			if gasCap != 0 {
+				gasCap -= min(gasCap, usedGas)
+			}
+		}
+	}
+	return results, nil
+}
+
+// callBlock retrieves the block to trace calls on top of.
+func (api *API) callBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Block, error) {
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		return api.blockByHash(ctx, hash)
+	} else if number, ok := blockNrOrHash.Number(); ok {
+		if number == rpc.PendingBlockNumber {
+			// We don't have access to the miner here. For tracing 'future' transactions,
+			// it can be done with block- and state-overrides instead, which offers
+			// more flexibility and stability than trying to trace on 'pending', since
+			// the contents of 'pending' is unstable and probably not a true representation
+			// of what the next actual block is likely to contain.
+			return nil, errors.New("tracing on top of pending is not supported")
+		}
+		return api.blockByNumber(ctx, number)
+	}
+	return nil, errors.New("invalid arguments; neither block nor hash specified")
+}
+
+// callState recomputes the state to trace calls on, which is the state after
+// the block, or before the transaction at config.TxIndex if set.
+func (api *API) callState(ctx context.Context, block *types.Block, config *TraceCallConfig) (*state.StateDB, StateReleaseFunc, error) {
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	if config != nil && config.TxIndex != nil {
+		_, _, statedb, release, err := api.backend.StateAtTransaction(ctx, block, int(*config.TxIndex), reexec)
+		return statedb, release, err
+	}
+	return api.backend.StateAtBlock(ctx, block, reexec, nil, true, false)
+}
+
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
 func (api *API) traceTx(ctx context.Context, tx *types.Transaction, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
-	var (
-		tracer  *Tracer
-		err     error
-		timeout = defaultTraceTimeout
-		usedGas uint64
-	)
 	if config == nil {
 		config = &TraceConfig{}
 	}
-	// Default tracer is the struct logger
+	tracer, err := newTracer(txctx, config)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := parseTraceTimeout(config.Timeout, defaultTraceTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return api.traceTxWithTracer(ctx, tx, message, txctx, vmctx, statedb, tracer, timeout)
+}
+
+// parseTraceTimeout returns the configured timeout of a single transaction
+// trace, or the given default if not configured.
+func parseTraceTimeout(timeout *string, fallback time.Duration) (time.Duration, error) {
+	if timeout == nil {
+		return fallback, nil
+	}
+	return time.ParseDuration(*timeout)
+}
+
+// newTracer creates the tracer requested by the configuration, which is the
+// struct logger by default.
+func newTracer(txctx *Context, config *TraceConfig) (*Tracer, error) {
+	if config == nil {
+		config = &TraceConfig{}
+	}
 	if config.Tracer == nil {
 		logger := logger.NewStructLogger(config.Config)
-		tracer = &Tracer{
+		return &Tracer{
 			Hooks:     logger.Hooks(),
 			GetResult: logger.GetResult,
 			Stop:      logger.Stop,
-		}
-	} else {
-		tracer, err = DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig)
-		if err != nil {
-			return nil, err
-		}
+		}, nil
 	}
+	return DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig)
+}
+
+// traceTxWithTracer executes the given message in the provided environment with
+// the given tracer, and returns the result of the tracer. Execution is aborted
+// when the timeout expires, if non-zero, or the context is cancelled.
+func (api *API) traceTxWithTracer(ctx context.Context, tx *types.Transaction, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, tracer *Tracer, timeout time.Duration) (json.RawMessage, error) {
+	var (
+		err     error
+		usedGas uint64
+	)
 	// The actual TxContext will be created as part of ApplyTransactionWithEVM.
 	vmenv := vm.NewEVM(vmctx, vm.TxContext{GasPrice: message.GasPrice, BlobFeeCap: message.BlobGasFeeCap}, statedb, api.backend.ChainConfig(), vm.Config{Tracer: tracer.Hooks, NoBaseFee: true})
 	statedb.SetLogger(tracer.Hooks)
 
 	// Define a meaningful timeout of a single transaction trace
-	if config.Timeout != nil {
-		if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
-			return nil, err
-		}
+	var (
+		deadlineCtx context.Context
+		cancel      context.CancelFunc
+	)
+	if timeout != 0 {
+		deadlineCtx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		deadlineCtx, cancel = context.WithCancel(ctx)
 	}
-	deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 	go func() {
 		<-deadlineCtx.Done()
 		if errors.Is(deadlineCtx.Err(), context.DeadlineExceeded) {
 			tracer.Stop(errors.New("execution timeout"))
 			// Stop evm execution. Note cancellation is not necessarily immediate.
 			vmenv.Cancel()
+		} else if ctx.Err() != nil {
+			tracer.Stop(errors.New("execution aborted"))
+			vmenv.Cancel()
 		}
 	}()
 	defer cancel()
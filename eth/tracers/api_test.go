@@ -27,6 +27,7 @@ import (
 	"net/http"
 	"reflect"
 	"slices"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -40,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -127,6 +129,7 @@ type testBackend struct {
 	relHook func() // Hook is invoked when the requested state is released
 
 	historical     *rpc.Client
+	traceCache     *ResultCache
 	mockHistorical *mockHistoricalBackend
 }
 
@@ -268,6 +271,10 @@ func (b *testBackend) HistoricalRPCService() *rpc.Client {
 	return b.historical
 }
 
+func (b *testBackend) TraceCache() *ResultCache {
+	return b.traceCache
+}
+
 func TestTraceCall(t *testing.T) {
 	t.Parallel()
 
@@ -1065,6 +1072,218 @@ func TestTracingWithOverrides(t *testing.T) {
 	}
 }
 
+func TestTraceCallMany(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(1)
+	// A contract returning the value of slot 0 and storing the block number
+	// in it, which makes the state carried over between calls visible.
+	contract := common.HexToAddress("0xc0de")
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			contract: {
+				Balance: new(big.Int),
+				// PUSH1 0 SLOAD NUMBER PUSH1 0 SSTORE PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+				Code: common.FromHex("0x6000544360005560005260206000f3"),
+			},
+		},
+	}
+	genBlocks := 10
+	backend := newTestBackend(t, genBlocks, genesis, func(i int, b *core.BlockGen) {})
+	defer backend.teardown()
+	api := NewAPI(backend)
+
+	call := ethapi.TransactionArgs{From: &accounts[0].addr, To: &contract}
+	bundles := []Bundle{
+		{Transactions: []ethapi.TransactionArgs{call, call}},
+		{Transactions: []ethapi.TransactionArgs{call}, BlockOverride: &ethapi.BlockOverrides{Number: (*hexutil.Big)(big.NewInt(100))}},
+		{Transactions: []ethapi.TransactionArgs{call}},
+	}
+	results, err := api.TraceCallMany(context.Background(), bundles, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil)
+	if err != nil {
+		t.Fatalf("failed to trace calls: %v", err)
+	}
+	want := [][]uint64{{0, uint64(genBlocks)}, {uint64(genBlocks)}, {100}}
+	if len(results) != len(want) {
+		t.Fatalf("bundle count mismatch: have %d, want %d", len(results), len(want))
+	}
+	for i := range want {
+		if len(results[i]) != len(want[i]) {
+			t.Fatalf("bundle %d: call count mismatch: have %d, want %d", i, len(results[i]), len(want[i]))
+		}
+		for j := range want[i] {
+			var have struct {
+				Failed      bool
+				ReturnValue string
+			}
+			resBytes, _ := json.Marshal(results[i][j])
+			json.Unmarshal(resBytes, &have)
+			if have.Failed {
+				t.Errorf("bundle %d, call %d: call failed", i, j)
+			}
+			if value := new(big.Int).SetBytes(common.FromHex(have.ReturnValue)); value.Uint64() != want[i][j] {
+				t.Errorf("bundle %d, call %d: return value mismatch: have %d, want %d", i, j, value, want[i][j])
+			}
+		}
+	}
+
+	// Errors report the failing call
+	_, err = api.TraceCallMany(context.Background(), []Bundle{{Transactions: []ethapi.TransactionArgs{call, {From: &accounts[0].addr, To: &contract, GasPrice: newRPCBalance(big.NewInt(1)), MaxFeePerGas: newRPCBalance(big.NewInt(1))}}}}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "bundle 0, call 1:") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTraceCallManyGasCap(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(1)
+	// A contract looping until it runs out of gas
+	contract := common.HexToAddress("0xc0de")
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			contract:         {Code: common.FromHex("0x5b600056")}, // JUMPDEST PUSH1 0 JUMP
+		},
+	}
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {})
+	defer backend.teardown()
+	api := NewAPI(backend)
+
+	// The calls share the gas cap, the last one gets what's left of it
+	var (
+		gas   = hexutil.Uint64(backend.RPCGasCap() * 2 / 5)
+		call  = ethapi.TransactionArgs{From: &accounts[0].addr, To: &contract, Gas: &gas}
+		calls = []ethapi.TransactionArgs{call, call, call}
+		// Don't collect the logs of the loop
+		config = &TraceCallConfig{TraceConfig: TraceConfig{Config: &logger.Config{Limit: 1}}}
+	)
+	results, err := api.TraceCallMany(context.Background(), []Bundle{{Transactions: calls}}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), config)
+	if err != nil {
+		t.Fatalf("failed to trace calls: %v", err)
+	}
+	var last struct{ Gas uint64 }
+	resBytes, _ := json.Marshal(results[0][2])
+	json.Unmarshal(resBytes, &last)
+	if want := backend.RPCGasCap() - 2*uint64(gas); last.Gas != want {
+		t.Errorf("last call gas mismatch: have %d, want %d", last.Gas, want)
+	}
+	// Once it is used up, no more calls are traced
+	_, err = api.TraceCallMany(context.Background(), []Bundle{{Transactions: calls}, {Transactions: []ethapi.TransactionArgs{call}}}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), config)
+	if err == nil || !strings.HasPrefix(err.Error(), "bundle 1, call 0: gas cap") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTraceCallManyTxIndex(t *testing.T) {
+	// A tracer returning the transaction index it's run with
+	DefaultDirectory.Register("txIndexTracer", func(ctx *Context, cfg json.RawMessage) (*Tracer, error) {
+		return &Tracer{
+			Hooks:     &tracing.Hooks{},
+			GetResult: func() (json.RawMessage, error) { return json.Marshal(ctx.TxIndex) },
+			Stop:      func(error) {},
+		}, nil
+	}, false)
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		signer   = types.HomesteadSigner{}
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{accounts[0].addr: {Balance: big.NewInt(params.Ether)}},
+		}
+	)
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		for n := 0; n < 2; n++ {
+			tx, _ := types.SignTx(types.NewTransaction(uint64(n), accounts[1].addr, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, accounts[0].key)
+			b.AddTx(tx)
+		}
+	})
+	defer backend.teardown()
+	api := NewAPI(backend)
+
+	// The calls are traced as if they followed the transactions before TxIndex
+	var (
+		tracer  = "txIndexTracer"
+		txIndex = hexutil.Uint(1)
+		call    = ethapi.TransactionArgs{From: &accounts[1].addr, To: &accounts[0].addr}
+		config  = &TraceCallConfig{TxIndex: &txIndex, TraceConfig: TraceConfig{Tracer: &tracer}}
+	)
+	results, err := api.TraceCallMany(context.Background(), []Bundle{{Transactions: []ethapi.TransactionArgs{call, call}}, {Transactions: []ethapi.TransactionArgs{call}}}, rpc.BlockNumberOrHashWithNumber(1), config)
+	if err != nil {
+		t.Fatalf("failed to trace calls: %v", err)
+	}
+	have, _ := json.Marshal(results)
+	if want := `[[1,2],[3]]`; string(have) != want {
+		t.Errorf("transaction index mismatch: have %s, want %s", have, want)
+	}
+}
+
+func TestTraceCallManyFeeCurrency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		genesis     = core.DeveloperGenesisBlock(30_000_000, nil)
+		feeCurrency = core.DevFeeCurrencyAddr
+		to          = common.HexToAddress("0xdeadbeef")
+		// balanceOf(DevAddr)
+		balanceOf = hexutil.Bytes(append(common.FromHex("0x70a08231"), common.LeftPadBytes(core.DevAddr.Bytes(), 32)...))
+	)
+	backend := newTestBackend(t, 0, genesis, nil)
+	defer backend.teardown()
+	api := NewAPI(backend)
+
+	bundles := []Bundle{
+		{Transactions: []ethapi.TransactionArgs{
+			{To: &feeCurrency, Input: &balanceOf},
+			{
+				From:                 &core.DevAddr,
+				To:                   &to,
+				Value:                (*hexutil.Big)(big.NewInt(1)),
+				MaxFeePerGas:         (*hexutil.Big)(big.NewInt(100 * params.GWei)),
+				MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(params.GWei)),
+				FeeCurrency:          &feeCurrency,
+			},
+		}},
+		{Transactions: []ethapi.TransactionArgs{{To: &feeCurrency, Input: &balanceOf}}},
+	}
+	results, err := api.TraceCallMany(context.Background(), bundles, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil)
+	if err != nil {
+		t.Fatalf("failed to trace calls: %v", err)
+	}
+	type res struct {
+		Gas         uint64
+		Failed      bool
+		ReturnValue string
+	}
+	var traces []res
+	for _, bundle := range results {
+		for _, result := range bundle {
+			var have res
+			resBytes, _ := json.Marshal(result)
+			json.Unmarshal(resBytes, &have)
+			if have.Failed {
+				t.Fatalf("call failed: %s", resBytes)
+			}
+			traces = append(traces, have)
+		}
+	}
+	// The fees of the fee currency call are debited from the sender's fee
+	// currency balance, which is visible in the following bundle.
+	before := new(big.Int).SetBytes(common.FromHex(traces[0].ReturnValue))
+	after := new(big.Int).SetBytes(common.FromHex(traces[2].ReturnValue))
+	if after.Cmp(before) >= 0 {
+		t.Errorf("fee currency balance not debited: before %d, after %d", before, after)
+	}
+	if traces[1].Gas <= params.TxGas {
+		t.Errorf("fee currency intrinsic gas not charged: gas used %d", traces[1].Gas)
+	}
+}
+
 type Account struct {
 	key  *ecdsa.PrivateKey
 	addr common.Address
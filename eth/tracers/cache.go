@@ -0,0 +1,248 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"math"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// resultCacheKey identifies the trace results of a block, being the block hash
+// followed by the hash of the tracer name and configuration.
+type resultCacheKey [2 * common.HashLength]byte
+
+// chainSideSubscriber is the chain event source used to drop the results of
+// blocks which are no longer canonical.
+type chainSideSubscriber interface {
+	SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription
+}
+
+// ResultCache is a persistent cache of block trace results. The total size of
+// the stored results is capped, evicting the least recently used ones. The
+// results of blocks leaving the canonical chain are dropped.
+//
+// A nil ResultCache is valid and caches nothing.
+type ResultCache struct {
+	db    ethdb.KeyValueStore
+	limit uint64 // Maximum total size of the stored results
+
+	lock    sync.Mutex
+	entries lru.BasicLRU[resultCacheKey, uint64] // Sizes of the stored results in access order
+	size    uint64                               // Total size of the stored results
+
+	sub     event.Subscription
+	closeCh chan struct{}
+	closeWg sync.WaitGroup
+}
+
+// NewResultCache creates a trace result cache on top of the given database,
+// holding at most limit bytes of results. The database is closed along with
+// the cache.
+func NewResultCache(db ethdb.KeyValueStore, limit uint64, chain chainSideSubscriber) *ResultCache {
+	c := &ResultCache{
+		db:      db,
+		limit:   limit,
+		entries: lru.NewBasicLRU[resultCacheKey, uint64](math.MaxInt),
+		closeCh: make(chan struct{}),
+	}
+	// Load the results stored by previous runs, their access order is lost
+	it := db.NewIterator(nil, nil)
+	for it.Next() {
+		if len(it.Key()) != len(resultCacheKey{}) {
+			continue
+		}
+		c.entries.Add(resultCacheKey(it.Key()), uint64(len(it.Key())+len(it.Value())))
+		c.size += uint64(len(it.Key()) + len(it.Value()))
+	}
+	it.Release()
+	c.evict()
+	log.Info("Loaded trace result cache", "entries", c.entries.Len(), "size", common.StorageSize(c.size), "limit", common.StorageSize(limit))
+
+	sideCh := make(chan core.ChainSideEvent, 10)
+	c.sub = chain.SubscribeChainSideEvent(sideCh)
+	c.closeWg.Add(1)
+	go c.loop(sideCh)
+	return c
+}
+
+// Close stops dropping the results of reorged blocks and closes the database.
+func (c *ResultCache) Close() error {
+	if c == nil {
+		return nil
+	}
+	close(c.closeCh)
+	c.closeWg.Wait()
+	return c.db.Close()
+}
+
+// loop drops the results of the blocks leaving the canonical chain.
+func (c *ResultCache) loop(sideCh chan core.ChainSideEvent) {
+	defer c.closeWg.Done()
+	defer c.sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-sideCh:
+			c.drop(ev.Block.Hash())
+		case <-c.sub.Err():
+			return
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+// resultCacheKeyOf returns the cache key of the results of tracing the given
+// block with the given configuration. Settings not affecting the results are
+// disregarded.
+func resultCacheKeyOf(blockHash common.Hash, config *TraceConfig) resultCacheKey {
+	var (
+		key    resultCacheKey
+		tracer string
+		enc    []byte
+	)
+	if config != nil {
+		if config.Tracer != nil {
+			tracer = *config.Tracer
+		}
+		stripped := *config
+		stripped.Tracer, stripped.Timeout, stripped.Reexec = nil, nil, nil
+		enc, _ = json.Marshal(&stripped)
+	}
+	copy(key[:], blockHash[:])
+	copy(key[common.HashLength:], crypto.Keccak256([]byte(tracer), []byte{0}, enc))
+	return key
+}
+
+// get returns the cached trace results of the given block and configuration.
+func (c *ResultCache) get(blockHash common.Hash, config *TraceConfig) ([]*txTraceResult, bool) {
+	if c == nil {
+		return nil, false
+	}
+	key := resultCacheKeyOf(blockHash, config)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.entries.Get(key); !ok {
+		return nil, false
+	}
+	blob, err := c.db.Get(key[:])
+	if err != nil {
+		return nil, false
+	}
+	var stored []struct {
+		TxHash common.Hash     `json:"txHash"`
+		Result json.RawMessage `json:"result,omitempty"`
+		Error  string          `json:"error,omitempty"`
+	}
+	if err := json.Unmarshal(blob, &stored); err != nil {
+		log.Warn("Invalid cached trace results", "block", blockHash, "err", err)
+		return nil, false
+	}
+	results := make([]*txTraceResult, len(stored))
+	for i, res := range stored {
+		results[i] = &txTraceResult{TxHash: res.TxHash, Error: res.Error}
+		if res.Result != nil {
+			results[i].Result = res.Result
+		}
+	}
+	return results, true
+}
+
+// put stores the trace results of the given block and configuration, evicting
+// the least recently used results if the size limit is exceeded.
+func (c *ResultCache) put(blockHash common.Hash, config *TraceConfig, results []*txTraceResult) {
+	if c == nil {
+		return
+	}
+	blob, err := json.Marshal(results)
+	if err != nil {
+		return
+	}
+	key := resultCacheKeyOf(blockHash, config)
+	size := uint64(len(key) + len(blob))
+	if size > c.limit {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.db.Put(key[:], blob); err != nil {
+		log.Warn("Failed to cache trace results", "block", blockHash, "err", err)
+		return
+	}
+	if old, ok := c.entries.Peek(key); ok {
+		c.size -= old
+	}
+	c.entries.Add(key, size)
+	c.size += size
+	c.evict()
+}
+
+// drop removes all cached trace results of the given block.
+func (c *ResultCache) drop(blockHash common.Hash) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	batch := c.db.NewBatch()
+	it := c.db.NewIterator(blockHash[:], nil)
+	for it.Next() {
+		if len(it.Key()) != len(resultCacheKey{}) {
+			continue
+		}
+		key := resultCacheKey(it.Key())
+		if size, ok := c.entries.Peek(key); ok {
+			c.entries.Remove(key)
+			c.size -= size
+		}
+		batch.Delete(key[:])
+	}
+	it.Release()
+	if err := batch.Write(); err != nil {
+		log.Warn("Failed to drop cached trace results", "block", blockHash, "err", err)
+	}
+}
+
+// evict removes the least recently used results until the total size is within
+// the limit. The lock must be held by the caller.
+func (c *ResultCache) evict() {
+	if c.size <= c.limit {
+		return
+	}
+	batch := c.db.NewBatch()
+	for c.size > c.limit {
+		key, size, ok := c.entries.RemoveOldest()
+		if !ok {
+			break
+		}
+		batch.Delete(key[:])
+		c.size -= size
+	}
+	if err := batch.Write(); err != nil {
+		log.Warn("Failed to evict cached trace results", "err", err)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type testChainSide struct{ feed event.Feed }
+
+// nopCloseDB keeps the database open when the cache is closed, so that it can
+// be reopened.
+type nopCloseDB struct{ ethdb.KeyValueStore }
+
+func (db nopCloseDB) Close() error { return nil }
+
+func (c *testChainSide) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+func testTraceResults(n int) []*txTraceResult {
+	results := make([]*txTraceResult, n)
+	for i := range results {
+		results[i] = &txTraceResult{TxHash: common.Hash{byte(i)}, Result: map[string]int{"gas": i}}
+	}
+	return results
+}
+
+func TestResultCache(t *testing.T) {
+	var (
+		db     = nopCloseDB{memorydb.New()}
+		chain  = new(testChainSide)
+		cache  = NewResultCache(db, 1000, chain)
+		tracer = "callTracer"
+		config = &TraceConfig{Tracer: &tracer}
+		hashes = []common.Hash{{1}, {2}, {3}}
+	)
+	cache.put(hashes[0], config, testTraceResults(2))
+	cache.put(hashes[0], nil, testTraceResults(1))
+
+	results, ok := cache.get(hashes[0], config)
+	if !ok {
+		t.Fatal("cached results not found")
+	}
+	have, _ := json.Marshal(results)
+	want, _ := json.Marshal(testTraceResults(2))
+	if string(have) != string(want) {
+		t.Fatalf("cached results mismatch: have %s, want %s", have, want)
+	}
+	// Settings not affecting the results share the entry
+	timeout := "1s"
+	if _, ok := cache.get(hashes[0], &TraceConfig{Tracer: &tracer, Timeout: &timeout}); !ok {
+		t.Error("cached results not found with different timeout")
+	}
+	if _, ok := cache.get(hashes[0], &TraceConfig{Tracer: &tracer, TracerConfig: json.RawMessage(`{"onlyTopCall":true}`)}); ok {
+		t.Error("cached results found with different tracer config")
+	}
+	// Exceeding the size limit evicts the least recently used results
+	cache.get(hashes[0], nil)
+	cache.put(hashes[1], config, testTraceResults(4))
+	cache.put(hashes[2], config, testTraceResults(4))
+	if _, ok := cache.get(hashes[0], config); ok {
+		t.Error("least recently used results not evicted")
+	}
+	if _, ok := cache.get(hashes[2], config); !ok {
+		t.Error("most recent results evicted")
+	}
+	if cache.size > cache.limit {
+		t.Errorf("cache size %d exceeds limit %d", cache.size, cache.limit)
+	}
+	// Blocks leaving the canonical chain are dropped
+	hash := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2)}).Hash()
+	cache.put(hash, config, testTraceResults(1))
+	chain.feed.Send(core.ChainSideEvent{Block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2)})})
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if _, ok := cache.get(hash, config); !ok {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("results of reorged block not dropped")
+		}
+	}
+	// The results are persisted across restarts
+	cache.Close()
+	cache = NewResultCache(db, 1000, chain)
+	defer cache.Close()
+	if _, ok := cache.get(hashes[2], config); !ok {
+		t.Error("cached results not reloaded")
+	}
+}
+
+func TestTraceBlockCached(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	backend := newTestBackend(t, 2, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &accounts[1].addr,
+			Value:    big.NewInt(1000),
+			Gas:      params.TxGas,
+			GasPrice: b.BaseFee(),
+		}), types.HomesteadSigner{}, accounts[0].key)
+		b.AddTx(tx)
+	})
+	defer backend.chain.Stop()
+	backend.traceCache = NewResultCache(rawdb.NewMemoryDatabase(), 1024*1024, backend.chain)
+	defer backend.traceCache.Close()
+	api := NewAPI(backend)
+
+	config := &TraceConfig{Config: &logger.Config{DisableStorage: true}}
+	traced, err := api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(1), config)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	block := backend.chain.GetBlockByNumber(1)
+	if _, ok := backend.traceCache.get(block.Hash(), config); !ok {
+		t.Fatal("trace results of canonical block not cached")
+	}
+	cached, err := api.TraceBlockByHash(context.Background(), block.Hash(), config)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	have, _ := json.Marshal(cached)
+	want, _ := json.Marshal(traced)
+	if string(have) != string(want) {
+		t.Fatalf("cached results mismatch: have %s, want %s", have, want)
+	}
+	// Cached results are served without re-executing the block
+	backend.traceCache.put(block.Hash(), config, testTraceResults(1))
+	cached, _ = api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(1), config)
+	have, _ = json.Marshal(cached)
+	want, _ = json.Marshal(testTraceResults(1))
+	if string(have) != string(want) {
+		t.Fatalf("results not served from cache: have %s, want %s", have, want)
+	}
+}
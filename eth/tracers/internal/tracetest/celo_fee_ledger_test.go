@@ -0,0 +1,190 @@
+package tracetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type feeLedgerEntry struct {
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	TxHash          common.Hash    `json:"transactionHash"`
+	From            common.Address `json:"from"`
+	FeeCurrency     common.Address `json:"feeCurrency"`
+	RateNumerator   *hexutil.Big   `json:"exchangeRateNumerator"`
+	RateDenominator *hexutil.Big   `json:"exchangeRateDenominator"`
+	Debited         *hexutil.Big   `json:"debited"`
+	Refund          *hexutil.Big   `json:"refund"`
+	Tip             *hexutil.Big   `json:"tip"`
+	BaseFee         *hexutil.Big   `json:"baseFee"`
+}
+
+type feeLedgerBlock struct {
+	Number  hexutil.Uint64    `json:"number"`
+	Hash    common.Hash       `json:"hash"`
+	Entries []*feeLedgerEntry `json:"entries"`
+}
+
+func TestFeeLedger(t *testing.T) {
+	var (
+		gspec  = core.DeveloperGenesisBlock(30_000_000, nil)
+		engine = beacon.New(ethash.NewFaker())
+		signer = types.LatestSigner(gspec.Config)
+		aa     = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+	)
+	// Each block contains a transaction paying in the given fee currency,
+	// or a CELO transaction if nil.
+	generate := func(currencies []*common.Address) func(int, *core.BlockGen) {
+		return func(i int, b *core.BlockGen) {
+			b.SetPoS()
+			b.SetParentBeaconRoot(common.Hash{})
+			txdata := &types.CeloDynamicFeeTxV2{
+				Nonce:       uint64(i),
+				To:          &aa,
+				Gas:         100000,
+				GasFeeCap:   new(big.Int).Mul(b.BaseFee(), big.NewInt(4)),
+				GasTipCap:   big.NewInt(2),
+				FeeCurrency: currencies[i],
+			}
+			b.AddTx(types.MustSignNewTx(core.DevPrivateKey, signer, txdata))
+		}
+	}
+	_, chainA, _ := core.GenerateChainWithGenesis(gspec, engine, 2, generate([]*common.Address{&core.DevFeeCurrencyAddr2, &core.DevFeeCurrencyAddr}))
+	_, chainB, _ := core.GenerateChainWithGenesis(gspec, engine, 3, generate([]*common.Address{nil, &core.DevFeeCurrencyAddr2, &core.DevFeeCurrencyAddr2}))
+
+	db := rawdb.NewMemoryDatabase()
+	tracer, apis, err := tracers.LiveDirectory.NewWithAPIs("feeLedger", json.RawMessage(fmt.Sprintf(`{"path":"%s"}`, t.TempDir())), db)
+	if err != nil {
+		t.Fatalf("failed to create fee ledger tracer: %v", err)
+	}
+	chain, err := core.NewBlockChain(db, core.DefaultCacheConfigWithScheme(rawdb.PathScheme), gspec, nil, engine, vm.Config{Tracer: tracer}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	for _, api := range apis {
+		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+			t.Fatalf("failed to register API: %v", err)
+		}
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	getEntries := func(from, to uint64, feeCurrency *common.Address) []*feeLedgerEntry {
+		var entries []*feeLedgerEntry
+		if err := client.Call(&entries, "feeLedger_getEntries", hexutil.Uint64(from), hexutil.Uint64(to), feeCurrency); err != nil {
+			t.Fatalf("failed to get entries: %v", err)
+		}
+		return entries
+	}
+
+	if n, err := chain.InsertChain(chainA); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	entries := getEntries(0, 10, nil)
+	if len(entries) != 2 {
+		t.Fatalf("entry count mismatch: have %d, want 2", len(entries))
+	}
+	for i, entry := range entries {
+		block := chain.GetBlockByNumber(uint64(i + 1))
+		receipt := chain.GetReceiptsByHash(block.Hash())[0]
+		tx := block.Transactions()[0]
+		if entry.BlockHash != block.Hash() || entry.TxHash != tx.Hash() || entry.From != core.DevAddr || entry.FeeCurrency != *tx.FeeCurrency() {
+			t.Errorf("entry %d: unexpected transaction info: %+v", i, entry)
+		}
+		// The base fee is charged at the exchange rate
+		rate := new(big.Rat).SetFrac(entry.RateNumerator.ToInt(), entry.RateDenominator.ToInt())
+		baseFee := new(big.Rat).Mul(rate, new(big.Rat).SetInt(new(big.Int).Mul(block.BaseFee(), new(big.Int).SetUint64(receipt.GasUsed))))
+		if baseFee.Cmp(new(big.Rat).SetInt(entry.BaseFee.ToInt())) != 0 {
+			t.Errorf("entry %d: base fee mismatch: have %v, want %v", i, entry.BaseFee, baseFee)
+		}
+		if tip := new(big.Int).SetUint64(2 * receipt.GasUsed); entry.Tip.ToInt().Cmp(tip) != 0 {
+			t.Errorf("entry %d: tip mismatch: have %v, want %v", i, entry.Tip, tip)
+		}
+		credited := new(big.Int).Add(entry.Refund.ToInt(), entry.Tip.ToInt())
+		credited.Add(credited, entry.BaseFee.ToInt())
+		if credited.Cmp(entry.Debited.ToInt()) != 0 {
+			t.Errorf("entry %d: credited %v, debited %v", i, credited, entry.Debited)
+		}
+	}
+	if entries := getEntries(0, 10, &core.DevFeeCurrencyAddr); len(entries) != 1 || entries[0].BlockNumber != 2 {
+		t.Errorf("unexpected entries for fee currency: %+v", entries)
+	}
+
+	// Only the entries of the canonical chain are returned
+	checkEntries := func(want ...common.Hash) {
+		t.Helper()
+		entries := getEntries(0, 10, nil)
+		if len(entries) != len(want) {
+			t.Fatalf("entry count mismatch: have %d, want %d", len(entries), len(want))
+		}
+		for i, entry := range entries {
+			if entry.BlockHash != want[i] {
+				t.Errorf("entry %d: block hash mismatch: have %v, want %v", i, entry.BlockHash, want[i])
+			}
+		}
+	}
+	if n, err := chain.InsertChain(chainB); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	if head := chain.CurrentBlock().Hash(); head != chainB[2].Hash() {
+		t.Fatalf("fork not canonical")
+	}
+	checkEntries(chainB[1].Hash(), chainB[2].Hash())
+
+	// Reorg back to the first chain, whose blocks were already executed
+	if _, err := chain.SetCanonical(chainA[1]); err != nil {
+		t.Fatalf("failed to reorg to first chain: %v", err)
+	}
+	checkEntries(chainA[0].Hash(), chainA[1].Hash())
+
+	// Rewinding the chain drops the entries above the head
+	if err := chain.SetHead(1); err != nil {
+		t.Fatalf("failed to rewind chain: %v", err)
+	}
+	checkEntries(chainA[0].Hash())
+
+	// And back to the fork, to check the block queries
+	if _, err := chain.InsertChain(chainB); err != nil {
+		t.Fatalf("failed to reinsert fork: %v", err)
+	}
+	if head := chain.CurrentBlock().Hash(); head != chainB[2].Hash() {
+		t.Fatalf("fork not canonical")
+	}
+	checkEntries(chainB[1].Hash(), chainB[2].Hash())
+	var block *feeLedgerBlock
+	if err := client.Call(&block, "feeLedger_getBlock", hexutil.Uint64(1)); err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+	if block != nil {
+		t.Errorf("block without fee currency transactions in ledger: %+v", block)
+	}
+	if err := client.Call(&block, "feeLedger_getBlock", hexutil.Uint64(3)); err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+	if block == nil || block.Hash != chainB[2].Hash() || len(block.Entries) != 1 {
+		t.Errorf("unexpected ledger block: %+v", block)
+	}
+
+	// Range queries are limited
+	var res []*feeLedgerEntry
+	if err := client.Call(&res, "feeLedger_getEntries", hexutil.Uint64(0), hexutil.Uint64(100000), nil); err == nil {
+		t.Errorf("expected error for too large range")
+	}
+}
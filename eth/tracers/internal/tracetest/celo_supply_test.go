@@ -0,0 +1,218 @@
+package tracetest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/contracts"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+type celoSupplyInfoBurn struct {
+	BurnAddress *hexutil.Big `json:"burnAddress,omitempty"`
+	Misc        *hexutil.Big `json:"misc,omitempty"`
+}
+
+type celoSupplyInfoFees struct {
+	BaseFee       *hexutil.Big                    `json:"baseFee,omitempty"`
+	FeeCurrencies map[common.Address]*hexutil.Big `json:"feeCurrencies,omitempty"`
+}
+
+type celoSupplyInfo struct {
+	Issuance *supplyInfoIssuance `json:"issuance,omitempty"`
+	Burn     *celoSupplyInfoBurn `json:"burn,omitempty"`
+	Fees     *celoSupplyInfoFees `json:"fees,omitempty"`
+
+	// Block info
+	Number     uint64      `json:"blockNumber"`
+	Hash       common.Hash `json:"hash"`
+	ParentHash common.Hash `json:"parentHash"`
+}
+
+func TestCeloSupply(t *testing.T) {
+	var (
+		gspec       = core.DeveloperGenesisBlock(30_000_000, nil)
+		signer      = types.LatestSigner(gspec.Config)
+		celoToken   = gspec.Config.CeloTokenAddress()
+		burnAddress = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+		aa          = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+		feeCurrency = core.DevFeeCurrencyAddr2
+		cip66       = core.DevFeeCurrencyAddr
+	)
+	// transfer(address,uint256) of the CELO token
+	transfer := func(to common.Address, value *big.Int) []byte {
+		data := hexutil.MustDecode("0xa9059cbb")
+		data = append(data, common.LeftPadBytes(to.Bytes(), 32)...)
+		return append(data, common.LeftPadBytes(value.Bytes(), 32)...)
+	}
+	out, chain, err := testCeloSupplyTracer(t, gspec, func(b *core.BlockGen) {
+		b.SetPoS()
+		// The dev genesis deploys the beacon roots contract, which the block
+		// processor calls.
+		b.SetParentBeaconRoot(common.Hash{})
+		txs := []types.TxData{
+			// Base fee goes to the fee handler
+			&types.DynamicFeeTx{Nonce: 0, To: &aa, Value: big.NewInt(1), Gas: 21000, GasFeeCap: b.BaseFee(), GasTipCap: big.NewInt(2)},
+			// Fees paid in a fee currency
+			&types.CeloDynamicFeeTxV2{Nonce: 1, To: &aa, Value: big.NewInt(1), Gas: 100000, GasFeeCap: b.BaseFee(), GasTipCap: big.NewInt(2), FeeCurrency: &feeCurrency},
+			// Native transfer to the burn address
+			&types.DynamicFeeTx{Nonce: 2, To: &burnAddress, Value: big.NewInt(100), Gas: 21000, GasFeeCap: b.BaseFee(), GasTipCap: big.NewInt(2)},
+			// Token duality transfer to the burn address
+			&types.DynamicFeeTx{Nonce: 3, To: &celoToken, Data: transfer(burnAddress, big.NewInt(200)), Gas: 100000, GasFeeCap: b.BaseFee(), GasTipCap: big.NewInt(2)},
+			// Failing token duality transfer to the burn address
+			&types.DynamicFeeTx{Nonce: 4, To: &celoToken, Data: transfer(burnAddress, new(big.Int).Mul(core.DevBalance, big.NewInt(2))), Gas: 100000, GasFeeCap: b.BaseFee(), GasTipCap: big.NewInt(2)},
+		}
+		for _, txdata := range txs {
+			b.AddTx(types.MustSignNewTx(core.DevPrivateKey, signer, txdata))
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to test celo supply tracer: %v", err)
+	}
+	head := chain.CurrentBlock()
+	receipts := chain.GetReceiptsByHash(head.Hash())
+	if len(receipts) != 5 {
+		t.Fatalf("unexpected number of receipts: %d", len(receipts))
+	}
+	if receipts[4].Status != types.ReceiptStatusFailed {
+		t.Fatalf("transfer of more than the balance succeeded")
+	}
+	// Only the CELO transactions pay the base fee in CELO
+	var baseFee = new(big.Int)
+	for _, i := range []int{0, 2, 3, 4} {
+		baseFee.Add(baseFee, new(big.Int).Mul(new(big.Int).SetUint64(receipts[i].GasUsed), head.BaseFee))
+	}
+	feeCurrencyFees := new(big.Int).Mul(new(big.Int).SetUint64(receipts[1].GasUsed), receipts[1].EffectiveGasPrice)
+	expected := celoSupplyInfo{
+		Burn: &celoSupplyInfoBurn{
+			BurnAddress: (*hexutil.Big)(big.NewInt(300)),
+		},
+		Fees: &celoSupplyInfoFees{
+			BaseFee: (*hexutil.Big)(baseFee),
+			FeeCurrencies: map[common.Address]*hexutil.Big{
+				feeCurrency: (*hexutil.Big)(feeCurrencyFees),
+			},
+		},
+		Number:     1,
+		Hash:       head.Hash(),
+		ParentHash: head.ParentHash,
+	}
+	compareAsJSON(t, expected, out[expected.Number])
+
+	// The fee currency fees must match what the state processor actually
+	// debited from the sender
+	balance := func(root common.Hash) *big.Int {
+		statedb, err := chain.StateAt(root)
+		if err != nil {
+			t.Fatalf("failed to get state: %v", err)
+		}
+		balance, err := contracts.GetBalanceERC20(&contracts.CeloBackend{ChainConfig: gspec.Config, State: statedb}, core.DevAddr, feeCurrency)
+		if err != nil {
+			t.Fatalf("failed to get fee currency balance: %v", err)
+		}
+		return balance
+	}
+	if debited := new(big.Int).Sub(balance(chain.Genesis().Root()), balance(head.Root)); debited.Cmp(feeCurrencyFees) != 0 {
+		t.Errorf("fee currency fees mismatch: debited %d, traced %d", debited, feeCurrencyFees)
+	}
+
+	// CIP-66 transactions can't be executed yet, so their fees are checked by
+	// driving the tracer directly. The CELO denominated fee is converted with
+	// the exchange rate of the block.
+	dir := filepath.ToSlash(t.TempDir())
+	hooks, err := tracers.LiveDirectory.New("celoSupply", json.RawMessage(fmt.Sprintf(`{"path":"%s"}`, dir)))
+	if err != nil {
+		t.Fatalf("failed to create celo supply tracer: %v", err)
+	}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), BaseFee: big.NewInt(100)})
+	tx := types.NewTx(&types.CeloDenominatedTx{Gas: 100000, GasFeeCap: big.NewInt(1000), GasTipCap: big.NewInt(10), To: &aa, FeeCurrency: &cip66, MaxFeeInFeeCurrency: big.NewInt(1e18)})
+	hooks.OnBlockStart(tracing.BlockEvent{Block: block})
+	hooks.OnTxStart(&tracing.VMContext{ChainConfig: gspec.Config}, tx, core.DevAddr)
+	hooks.OnFeeCurrencyFees(&tracing.FeeCurrencyFees{FeeCurrency: cip66, ExchangeRate: big.NewRat(2, 1)})
+	hooks.OnTxEnd(&types.Receipt{GasUsed: 50000}, nil)
+	hooks.OnBlockEnd(nil)
+	hooks.OnClose()
+
+	out, err = readCeloSupplyOutput(path.Join(dir, "celo_supply.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to read tracer output: %v", err)
+	}
+	expected = celoSupplyInfo{
+		Fees: &celoSupplyInfoFees{
+			FeeCurrencies: map[common.Address]*hexutil.Big{
+				cip66: (*hexutil.Big)(big.NewInt(50000 * 110 * 2)),
+			},
+		},
+		Number: 2,
+		Hash:   block.Hash(),
+	}
+	compareAsJSON(t, expected, out[0])
+}
+
+func testCeloSupplyTracer(t *testing.T, genesis *core.Genesis, gen func(*core.BlockGen)) ([]celoSupplyInfo, *core.BlockChain, error) {
+	var (
+		engine = beacon.New(ethash.NewFaker())
+	)
+
+	traceOutputPath := filepath.ToSlash(t.TempDir())
+	traceOutputFilename := path.Join(traceOutputPath, "celo_supply.jsonl")
+
+	// Load celo supply tracer
+	tracer, err := tracers.LiveDirectory.New("celoSupply", json.RawMessage(fmt.Sprintf(`{"path":"%s"}`, traceOutputPath)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create celo supply tracer: %v", err)
+	}
+
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), core.DefaultCacheConfigWithScheme(rawdb.PathScheme), genesis, nil, engine, vm.Config{Tracer: tracer}, nil, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, engine, 1, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{1})
+		gen(b)
+	})
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		return nil, chain, fmt.Errorf("block %d: failed to insert into chain: %v", n, err)
+	}
+
+	// Check and compare the results
+	output, err := readCeloSupplyOutput(traceOutputFilename)
+	return output, chain, err
+}
+
+func readCeloSupplyOutput(filename string) ([]celoSupplyInfo, error) {
+	file, err := os.OpenFile(filename, os.O_RDONLY, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output file: %v", err)
+	}
+	defer file.Close()
+
+	var output []celoSupplyInfo
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var info celoSupplyInfo
+		if err := json.Unmarshal(scanner.Bytes(), &info); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal result: %v", err)
+		}
+		output = append(output, info)
+	}
+	return output, nil
+}
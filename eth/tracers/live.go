@@ -5,27 +5,50 @@ import (
 	"errors"
 
 	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type ctorFunc func(config json.RawMessage) (*tracing.Hooks, error)
 
+// ctorWithAPIsFunc is the constructor of a live tracer which exposes RPC APIs,
+// e.g. to query the data it collected. The chain database gives the APIs access
+// to the chain, e.g. to tell which of the traced blocks are canonical.
+type ctorWithAPIsFunc func(config json.RawMessage, chainDb ethdb.Reader) (*tracing.Hooks, []rpc.API, error)
+
 // LiveDirectory is the collection of tracers which can be used
 // during normal block import operations.
-var LiveDirectory = liveDirectory{elems: make(map[string]ctorFunc)}
+var LiveDirectory = liveDirectory{elems: make(map[string]ctorWithAPIsFunc)}
 
 type liveDirectory struct {
-	elems map[string]ctorFunc
+	elems map[string]ctorWithAPIsFunc
 }
 
 // Register registers a tracer constructor by name.
 func (d *liveDirectory) Register(name string, f ctorFunc) {
+	d.elems[name] = func(config json.RawMessage, chainDb ethdb.Reader) (*tracing.Hooks, []rpc.API, error) {
+		hooks, err := f(config)
+		return hooks, nil, err
+	}
+}
+
+// RegisterWithAPIs registers the constructor of a tracer exposing RPC APIs
+// by name.
+func (d *liveDirectory) RegisterWithAPIs(name string, f ctorWithAPIsFunc) {
 	d.elems[name] = f
 }
 
 // New instantiates a tracer by name.
 func (d *liveDirectory) New(name string, config json.RawMessage) (*tracing.Hooks, error) {
+	hooks, _, err := d.NewWithAPIs(name, config, nil)
+	return hooks, err
+}
+
+// NewWithAPIs instantiates a tracer by name, and returns the RPC APIs it
+// exposes alongside. The APIs read the chain from the given database.
+func (d *liveDirectory) NewWithAPIs(name string, config json.RawMessage, chainDb ethdb.Reader) (*tracing.Hooks, []rpc.API, error) {
 	if f, ok := d.elems[name]; ok {
-		return f(config)
+		return f(config, chainDb)
 	}
-	return nil, errors.New("not found")
+	return nil, nil, errors.New("not found")
 }
@@ -0,0 +1,309 @@
+package live
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/pebble"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func init() {
+	tracers.LiveDirectory.RegisterWithAPIs("feeLedger", newFeeLedger)
+}
+
+const (
+	// feeLedgerMaxRange is the maximum number of blocks which can be queried at once.
+	feeLedgerMaxRange = 10000
+
+	feeLedgerDefaultCache   = 16 // Default cache size of the ledger database in megabytes
+	feeLedgerDefaultHandles = 16 // Default number of open files of the ledger database
+)
+
+var (
+	feeLedgerChainIDKey  = []byte("LedgerChainID") // Chain ID of the chain the ledger belongs to
+	feeLedgerBlockPrefix = []byte("b")             // feeLedgerBlockPrefix + num (uint64 big endian) + hash -> ledger block
+)
+
+// encodeFeeLedgerNumber encodes a block number as big endian uint64.
+func encodeFeeLedgerNumber(number uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, number)
+}
+
+// feeLedgerBlockKey = feeLedgerBlockPrefix + num (uint64 big endian) + hash
+func feeLedgerBlockKey(number uint64, hash common.Hash) []byte {
+	key := append(bytes.Clone(feeLedgerBlockPrefix), encodeFeeLedgerNumber(number)...)
+	return append(key, hash.Bytes()...)
+}
+
+// feeLedgerEntry records the fees of a transaction paid in a fee currency.
+// All amounts are denominated in the fee currency.
+type feeLedgerEntry struct {
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	TxHash          common.Hash    `json:"transactionHash"`
+	TxIndex         hexutil.Uint   `json:"transactionIndex"`
+	From            common.Address `json:"from"`
+	FeeCurrency     common.Address `json:"feeCurrency"`
+	RateNumerator   *hexutil.Big   `json:"exchangeRateNumerator"`
+	RateDenominator *hexutil.Big   `json:"exchangeRateDenominator"`
+	Debited         *hexutil.Big   `json:"debited"`
+	Refund          *hexutil.Big   `json:"refund"`
+	Tip             *hexutil.Big   `json:"tip"`
+	BaseFee         *hexutil.Big   `json:"baseFee"`
+	L1Fee           *hexutil.Big   `json:"l1Fee,omitempty"`
+}
+
+// feeLedgerBlock holds the ledger entries of a block.
+type feeLedgerBlock struct {
+	Number     hexutil.Uint64    `json:"number"`
+	Hash       common.Hash       `json:"hash"`
+	ParentHash common.Hash       `json:"parentHash"`
+	Entries    []*feeLedgerEntry `json:"entries"`
+}
+
+type feeLedgerTracerConfig struct {
+	Path    string `json:"path"`    // Path to the directory of the ledger database
+	Cache   int    `json:"cache"`   // Cache size of the ledger database in megabytes
+	Handles int    `json:"handles"` // Number of files the ledger database may keep open
+}
+
+// feeLedger is a live tracer which writes the fees of the transactions paid in
+// fee currencies to its own database, one ledger block per block with such
+// transactions.
+//
+// Every executed block is written by hash, whether it ends up canonical or not.
+// The blocks are only filtered when queried, by checking them against the
+// canonical chain, so reorgs and rewinds of the chain don't need to be tracked.
+type feeLedger struct {
+	db     ethdb.KeyValueStore
+	block  *feeLedgerBlock
+	tx     *types.Transaction
+	from   common.Address
+	fees   *tracing.FeeCurrencyFees // Fees of the current transaction
+	failed bool                     // Whether the ledger database is unusable
+}
+
+func newFeeLedger(cfg json.RawMessage, chainDb ethdb.Reader) (*tracing.Hooks, []rpc.API, error) {
+	var config feeLedgerTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse config: %v", err)
+		}
+	}
+	if config.Path == "" {
+		return nil, nil, errors.New("fee ledger tracer output path is required")
+	}
+	if config.Cache <= 0 {
+		config.Cache = feeLedgerDefaultCache
+	}
+	if config.Handles <= 0 {
+		config.Handles = feeLedgerDefaultHandles
+	}
+	db, err := pebble.New(config.Path, config.Cache, config.Handles, "eth/tracers/feeledger/", false, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open fee ledger database: %v", err)
+	}
+	t := &feeLedger{db: db}
+	hooks := &tracing.Hooks{
+		OnBlockchainInit:  t.OnBlockchainInit,
+		OnBlockStart:      t.OnBlockStart,
+		OnBlockEnd:        t.OnBlockEnd,
+		OnTxStart:         t.OnTxStart,
+		OnTxEnd:           t.OnTxEnd,
+		OnFeeCurrencyFees: t.OnFeeCurrencyFees,
+		OnClose:           t.OnClose,
+	}
+	apis := []rpc.API{{
+		Namespace: "feeLedger",
+		Service:   &FeeLedgerAPI{db: db, chainDb: chainDb},
+	}}
+	return hooks, apis, nil
+}
+
+// OnBlockchainInit checks that the ledger belongs to the chain. A ledger of
+// another chain is discarded.
+func (l *feeLedger) OnBlockchainInit(config *params.ChainConfig) {
+	chainID := config.ChainID.Bytes()
+	if stored, _ := l.db.Get(feeLedgerChainIDKey); stored != nil && !bytes.Equal(stored, chainID) {
+		log.Warn("Fee ledger belongs to another chain, resetting", "chainid", config.ChainID, "ledger", new(big.Int).SetBytes(stored))
+		if err := l.reset(); err != nil {
+			log.Error("Failed to reset fee ledger", "err", err)
+			l.failed = true
+			return
+		}
+	}
+	if err := l.db.Put(feeLedgerChainIDKey, chainID); err != nil {
+		log.Error("Failed to write fee ledger chain ID", "err", err)
+		l.failed = true
+	}
+}
+
+// reset deletes all ledger blocks.
+func (l *feeLedger) reset() error {
+	batch := l.db.NewBatch()
+	it := l.db.NewIterator(feeLedgerBlockPrefix, nil)
+	defer it.Release()
+	for it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func (l *feeLedger) OnBlockStart(ev tracing.BlockEvent) {
+	l.block = &feeLedgerBlock{
+		Number:     hexutil.Uint64(ev.Block.NumberU64()),
+		Hash:       ev.Block.Hash(),
+		ParentHash: ev.Block.ParentHash(),
+	}
+}
+
+// OnBlockEnd writes the ledger block of a successfully processed block, if it
+// has any fee currency transactions.
+func (l *feeLedger) OnBlockEnd(err error) {
+	block := l.block
+	l.block = nil
+	if err != nil || block == nil || len(block.Entries) == 0 || l.failed {
+		return
+	}
+	data, err := json.Marshal(block)
+	if err != nil {
+		log.Error("Failed to encode fee ledger block", "number", block.Number, "hash", block.Hash, "err", err)
+		return
+	}
+	if err := l.db.Put(feeLedgerBlockKey(uint64(block.Number), block.Hash), data); err != nil {
+		log.Error("Failed to write fee ledger block", "number", block.Number, "hash", block.Hash, "err", err)
+	}
+}
+
+func (l *feeLedger) OnTxStart(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	l.tx = tx
+	l.from = from
+	l.fees = nil
+}
+
+func (l *feeLedger) OnFeeCurrencyFees(fees *tracing.FeeCurrencyFees) {
+	l.fees = fees
+}
+
+func (l *feeLedger) OnTxEnd(receipt *types.Receipt, err error) {
+	fees := l.fees
+	l.fees = nil
+	if err != nil || receipt == nil || fees == nil || l.block == nil {
+		return
+	}
+	entry := &feeLedgerEntry{
+		BlockNumber: l.block.Number,
+		BlockHash:   l.block.Hash,
+		TxHash:      l.tx.Hash(),
+		TxIndex:     hexutil.Uint(receipt.TransactionIndex),
+		From:        l.from,
+		FeeCurrency: fees.FeeCurrency,
+		Debited:     (*hexutil.Big)(fees.Debited),
+		Refund:      (*hexutil.Big)(fees.Refund),
+		Tip:         (*hexutil.Big)(fees.Tip),
+		BaseFee:     (*hexutil.Big)(fees.BaseFee),
+		L1Fee:       (*hexutil.Big)(fees.L1Fee),
+	}
+	if fees.ExchangeRate != nil {
+		entry.RateNumerator = (*hexutil.Big)(fees.ExchangeRate.Num())
+		entry.RateDenominator = (*hexutil.Big)(fees.ExchangeRate.Denom())
+	}
+	l.block.Entries = append(l.block.Entries, entry)
+}
+
+func (l *feeLedger) OnClose() {
+	if err := l.db.Close(); err != nil {
+		log.Warn("failed to close fee ledger database", "error", err)
+	}
+}
+
+// FeeLedgerAPI provides access to the fee ledger written by the fee ledger
+// live tracer. Only the ledger blocks of the canonical chain are returned.
+type FeeLedgerAPI struct {
+	db      ethdb.KeyValueStore
+	chainDb ethdb.Reader // Chain database to look up the canonical blocks in
+}
+
+// GetBlock returns the ledger entries of the canonical block with the given
+// number, or nil if the block has no fees paid in fee currencies.
+func (api *FeeLedgerAPI) GetBlock(ctx context.Context, number hexutil.Uint64) (*feeLedgerBlock, error) {
+	hash := rawdb.ReadCanonicalHash(api.chainDb, uint64(number))
+	if hash == (common.Hash{}) {
+		return nil, nil
+	}
+	key := feeLedgerBlockKey(uint64(number), hash)
+	if ok, _ := api.db.Has(key); !ok {
+		return nil, nil
+	}
+	data, err := api.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	block := new(feeLedgerBlock)
+	if err := json.Unmarshal(data, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// GetEntries returns the ledger entries of the canonical blocks in the
+// inclusive range, optionally limited to a fee currency.
+func (api *FeeLedgerAPI) GetEntries(ctx context.Context, from, to hexutil.Uint64, feeCurrency *common.Address) ([]*feeLedgerEntry, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid block range: %d > %d", from, to)
+	}
+	if to-from >= feeLedgerMaxRange {
+		return nil, fmt.Errorf("block range too large: %d > %d", to-from+1, feeLedgerMaxRange)
+	}
+	it := api.db.NewIterator(feeLedgerBlockPrefix, encodeFeeLedgerNumber(uint64(from)))
+	defer it.Release()
+
+	entries := []*feeLedgerEntry{}
+	for it.Next() {
+		key := it.Key()[len(feeLedgerBlockPrefix):]
+		if len(key) != 8+common.HashLength {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key)
+		if number > uint64(to) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// Skip the blocks which were executed, but aren't canonical
+		if common.BytesToHash(key[8:]) != rawdb.ReadCanonicalHash(api.chainDb, number) {
+			continue
+		}
+		var block feeLedgerBlock
+		if err := json.Unmarshal(it.Value(), &block); err != nil {
+			return nil, err
+		}
+		for _, entry := range block.Entries {
+			if feeCurrency == nil || entry.FeeCurrency == *feeCurrency {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, it.Error()
+}
@@ -0,0 +1,394 @@
+package live
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/exchange"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+func init() {
+	tracers.LiveDirectory.Register("celoSupply", newCeloSupply)
+}
+
+var (
+	// celoBurnAddress is the address CELO is sent to in order to burn it.
+	celoBurnAddress = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	// celoTransferPrecompile is the precompile through which the CELO token
+	// moves native balances (token duality).
+	celoTransferPrecompile = common.BytesToAddress([]byte{0xfd})
+)
+
+type celoSupplyInfoBurn struct {
+	BurnAddress *big.Int `json:"burnAddress,omitempty"`
+	Misc        *big.Int `json:"misc,omitempty"`
+}
+
+//go:generate go run github.com/fjl/gencodec -type celoSupplyInfoBurn -field-override celoSupplyInfoBurnMarshaling -out gen_celosupplyinfoburn.go
+type celoSupplyInfoBurnMarshaling struct {
+	BurnAddress *hexutil.Big
+	Misc        *hexutil.Big
+}
+
+// celoSupplyInfoFees contains the transaction fees which change hands without
+// affecting the CELO supply.
+type celoSupplyInfoFees struct {
+	// BaseFee is the CELO base fee, which is transferred to the fee handler
+	// instead of being burned.
+	BaseFee *big.Int `json:"baseFee,omitempty"`
+	// FeeCurrencies is the fee volume of the transactions paying for gas in
+	// a fee currency, denominated in that fee currency.
+	FeeCurrencies map[common.Address]*big.Int `json:"feeCurrencies,omitempty"`
+}
+
+//go:generate go run github.com/fjl/gencodec -type celoSupplyInfoFees -field-override celoSupplyInfoFeesMarshaling -out gen_celosupplyinfofees.go
+type celoSupplyInfoFeesMarshaling struct {
+	BaseFee       *hexutil.Big
+	FeeCurrencies map[common.Address]*hexutil.Big
+}
+
+type celoSupplyInfo struct {
+	Issuance *supplyInfoIssuance `json:"issuance,omitempty"`
+	Burn     *celoSupplyInfoBurn `json:"burn,omitempty"`
+	Fees     *celoSupplyInfoFees `json:"fees,omitempty"`
+
+	// Block info
+	Number     uint64      `json:"blockNumber"`
+	Hash       common.Hash `json:"hash"`
+	ParentHash common.Hash `json:"parentHash"`
+}
+
+type celoSupplyTxCallstack struct {
+	calls       []celoSupplyTxCallstack
+	burn        *big.Int // Burned by self destructing
+	burnAddress *big.Int // Sent to the burn address
+}
+
+// celoSupply is the Celo variant of the supply tracer. Unlike on Ethereum, the
+// base fee is not burned but transferred to the fee handler, and transactions
+// can pay for gas in fee currencies, which doesn't touch the CELO supply at
+// all. CELO is burned by sending it to the burn address, either directly or
+// through the CELO token.
+type celoSupply struct {
+	delta       celoSupplyInfo
+	txCallstack []celoSupplyTxCallstack // Callstack for current transaction
+	logger      *lumberjack.Logger
+
+	config  *params.ChainConfig
+	baseFee *big.Int             // Base fee of the current block
+	rates   common.ExchangeRates // Exchange rates of the fee currencies used in the current block
+	tx      *types.Transaction   // Current transaction
+}
+
+func newCeloSupply(cfg json.RawMessage) (*tracing.Hooks, error) {
+	var config supplyTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse config: %v", err)
+		}
+	}
+	if config.Path == "" {
+		return nil, errors.New("celo supply tracer output path is required")
+	}
+
+	// Store traces in a rotating file
+	logger := &lumberjack.Logger{
+		Filename: filepath.Join(config.Path, "celo_supply.jsonl"),
+	}
+	if config.MaxSize > 0 {
+		logger.MaxSize = config.MaxSize
+	}
+
+	t := &celoSupply{
+		delta:  newCeloSupplyInfo(),
+		logger: logger,
+	}
+	return &tracing.Hooks{
+		OnBlockchainInit:  t.OnBlockchainInit,
+		OnBlockStart:      t.OnBlockStart,
+		OnBlockEnd:        t.OnBlockEnd,
+		OnGenesisBlock:    t.OnGenesisBlock,
+		OnTxStart:         t.OnTxStart,
+		OnTxEnd:           t.OnTxEnd,
+		OnBalanceChange:   t.OnBalanceChange,
+		OnFeeCurrencyFees: t.OnFeeCurrencyFees,
+		OnEnter:           t.OnEnter,
+		OnExit:            t.OnExit,
+		OnClose:           t.OnClose,
+	}, nil
+}
+
+func newCeloSupplyInfo() celoSupplyInfo {
+	return celoSupplyInfo{
+		Issuance: &supplyInfoIssuance{
+			GenesisAlloc: big.NewInt(0),
+			Reward:       big.NewInt(0),
+			Withdrawals:  big.NewInt(0),
+		},
+		Burn: &celoSupplyInfoBurn{
+			BurnAddress: big.NewInt(0),
+			Misc:        big.NewInt(0),
+		},
+		Fees: &celoSupplyInfoFees{
+			BaseFee:       big.NewInt(0),
+			FeeCurrencies: make(map[common.Address]*big.Int),
+		},
+	}
+}
+
+func (s *celoSupply) resetDelta() {
+	s.delta = newCeloSupplyInfo()
+}
+
+func (s *celoSupply) OnBlockchainInit(config *params.ChainConfig) {
+	s.config = config
+}
+
+func (s *celoSupply) OnBlockStart(ev tracing.BlockEvent) {
+	s.resetDelta()
+
+	s.delta.Number = ev.Block.NumberU64()
+	s.delta.Hash = ev.Block.Hash()
+	s.delta.ParentHash = ev.Block.ParentHash()
+	s.baseFee = ev.Block.BaseFee()
+	s.rates = nil
+}
+
+func (s *celoSupply) OnBlockEnd(err error) {
+	s.write(s.delta)
+}
+
+func (s *celoSupply) OnGenesisBlock(b *types.Block, alloc types.GenesisAlloc) {
+	s.resetDelta()
+
+	s.delta.Number = b.NumberU64()
+	s.delta.Hash = b.Hash()
+	s.delta.ParentHash = b.ParentHash()
+
+	// Initialize supply with total allocation in genesis block
+	for _, account := range alloc {
+		s.delta.Issuance.GenesisAlloc.Add(s.delta.Issuance.GenesisAlloc, account.Balance)
+	}
+
+	s.write(s.delta)
+}
+
+func (s *celoSupply) OnBalanceChange(a common.Address, prevBalance, newBalance *big.Int, reason tracing.BalanceChangeReason) {
+	diff := new(big.Int).Sub(newBalance, prevBalance)
+
+	// NOTE: don't handle "BalanceIncreaseGenesisBalance" because it is handled in OnGenesisBlock.
+	// The base fee credited to the fee handler is accounted for in OnTxEnd.
+	switch reason {
+	case tracing.BalanceIncreaseRewardMineBlock:
+		s.delta.Issuance.Reward.Add(s.delta.Issuance.Reward, diff)
+	case tracing.BalanceIncreaseWithdrawal:
+		s.delta.Issuance.Withdrawals.Add(s.delta.Issuance.Withdrawals, diff)
+	case tracing.BalanceDecreaseSelfdestructBurn:
+		// BalanceDecreaseSelfdestructBurn is non-reversible as it happens
+		// at the end of the transaction.
+		s.delta.Burn.Misc.Sub(s.delta.Burn.Misc, diff)
+	// Technically an OP-Stack deposit mint is a "withdrawal" from L1, taking funds into L2.
+	case tracing.BalanceMint:
+		s.delta.Issuance.Withdrawals.Add(s.delta.Issuance.Withdrawals, diff)
+	}
+}
+
+func (s *celoSupply) OnTxStart(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	s.txCallstack = make([]celoSupplyTxCallstack, 0, 1)
+	s.tx = tx
+	if s.config == nil {
+		s.config = vm.ChainConfig
+	}
+}
+
+// OnFeeCurrencyFees records the exchange rate of the fee currency, which is
+// the same for all transactions of the block.
+func (s *celoSupply) OnFeeCurrencyFees(fees *tracing.FeeCurrencyFees) {
+	if fees.ExchangeRate == nil {
+		return
+	}
+	if s.rates == nil {
+		s.rates = make(common.ExchangeRates)
+	}
+	s.rates[fees.FeeCurrency] = fees.ExchangeRate
+}
+
+// OnTxEnd accounts for the fees paid by the transaction. The fees of
+// transactions paying in a fee currency are denominated in the fee currency.
+func (s *celoSupply) OnTxEnd(receipt *types.Receipt, err error) {
+	if err != nil || receipt == nil || s.tx.IsDepositTx() {
+		return
+	}
+	gasUsed := new(big.Int).SetUint64(receipt.GasUsed)
+	if currency := s.tx.FeeCurrency(); currency != nil {
+		var fee *big.Int
+		if s.tx.Type() == types.CeloDenominatedTxType {
+			// The gas price of CIP-66 transactions is denominated in CELO
+			if s.baseFee == nil {
+				return
+			}
+			tip, _ := s.tx.EffectiveGasTip(s.baseFee)
+			celoFee := gasUsed.Mul(gasUsed, tip.Add(tip, s.baseFee))
+			if fee, err = exchange.ConvertCeloToCurrency(s.rates, currency, celoFee); err != nil {
+				log.Warn("Failed to convert CIP-66 transaction fee", "tx", s.tx.Hash(), "err", err)
+				return
+			}
+		} else {
+			// The receipt holds the base fee converted to the fee currency
+			if receipt.BaseFee == nil {
+				return
+			}
+			gasPrice := math.BigMin(new(big.Int).Add(s.tx.GasTipCap(), receipt.BaseFee), s.tx.GasFeeCap())
+			fee = gasUsed.Mul(gasUsed, gasPrice)
+		}
+		fees, ok := s.delta.Fees.FeeCurrencies[*currency]
+		if !ok {
+			fees = new(big.Int)
+			s.delta.Fees.FeeCurrencies[*currency] = fees
+		}
+		fees.Add(fees, fee)
+		return
+	}
+	if s.baseFee != nil {
+		s.delta.Fees.BaseFee.Add(s.delta.Fees.BaseFee, gasUsed.Mul(gasUsed, s.baseFee))
+	}
+}
+
+// internalTxsHandler handles internal transactions burned amount
+func (s *celoSupply) internalTxsHandler(call *celoSupplyTxCallstack) {
+	// Handle Burned amount
+	if call.burn != nil {
+		s.delta.Burn.Misc.Add(s.delta.Burn.Misc, call.burn)
+	}
+	if call.burnAddress != nil {
+		s.delta.Burn.BurnAddress.Add(s.delta.Burn.BurnAddress, call.burnAddress)
+	}
+
+	// Recursively handle internal calls
+	for _, call := range call.calls {
+		callCopy := call
+		s.internalTxsHandler(&callCopy)
+	}
+}
+
+func (s *celoSupply) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	call := celoSupplyTxCallstack{
+		calls: make([]celoSupplyTxCallstack, 0),
+	}
+
+	op := vm.OpCode(typ)
+	switch {
+	// This is a special case of burned amount which has to be handled here
+	// which happens when type == selfdestruct and from == to.
+	case op == vm.SELFDESTRUCT && from == to && value.Cmp(common.Big0) == 1:
+		call.burn = value
+	// Native CELO sent to the burn address, either by value transfer or by
+	// self destructing.
+	case to == celoBurnAddress && op != vm.DELEGATECALL && op != vm.CALLCODE && value != nil && value.Sign() > 0:
+		call.burnAddress = value
+	// CELO token transfers to the burn address go through the transfer
+	// precompile, with the (sender, recipient, value) words as input.
+	case to == celoTransferPrecompile && s.isCeloToken(from) && len(input) == 96:
+		if common.BytesToAddress(input[32:64]) == celoBurnAddress {
+			call.burnAddress = new(big.Int).SetBytes(input[64:96])
+		}
+	}
+
+	// Append call to the callstack, so we can fill the details in CaptureExit
+	s.txCallstack = append(s.txCallstack, call)
+}
+
+func (s *celoSupply) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if depth == 0 {
+		// No need to handle Burned amount if transaction is reverted
+		if !reverted {
+			s.internalTxsHandler(&s.txCallstack[0])
+		}
+		return
+	}
+
+	size := len(s.txCallstack)
+	if size <= 1 {
+		return
+	}
+	// Pop call
+	call := s.txCallstack[size-1]
+	s.txCallstack = s.txCallstack[:size-1]
+	size -= 1
+
+	// In case of a revert, we can drop the call and all its subcalls.
+	// Caution, that this has to happen after popping the call from the stack.
+	if reverted {
+		return
+	}
+	s.txCallstack[size-1].calls = append(s.txCallstack[size-1].calls, call)
+}
+
+// isCeloToken returns whether the address is the CELO token, the only
+// contract allowed to use the transfer precompile.
+func (s *celoSupply) isCeloToken(addr common.Address) bool {
+	return s.config != nil && s.config.CeloTokenAddress() == addr
+}
+
+func (s *celoSupply) OnClose() {
+	if err := s.logger.Close(); err != nil {
+		log.Warn("failed to close celo supply tracer log file", "error", err)
+	}
+}
+
+func (s *celoSupply) write(supply celoSupplyInfo) {
+	// Remove empty fields
+	if supply.Issuance.GenesisAlloc.Sign() == 0 {
+		supply.Issuance.GenesisAlloc = nil
+	}
+	if supply.Issuance.Reward.Sign() == 0 {
+		supply.Issuance.Reward = nil
+	}
+	if supply.Issuance.Withdrawals.Sign() == 0 {
+		supply.Issuance.Withdrawals = nil
+	}
+	if supply.Issuance.GenesisAlloc == nil && supply.Issuance.Reward == nil && supply.Issuance.Withdrawals == nil {
+		supply.Issuance = nil
+	}
+
+	if supply.Burn.BurnAddress.Sign() == 0 {
+		supply.Burn.BurnAddress = nil
+	}
+	if supply.Burn.Misc.Sign() == 0 {
+		supply.Burn.Misc = nil
+	}
+	if supply.Burn.BurnAddress == nil && supply.Burn.Misc == nil {
+		supply.Burn = nil
+	}
+
+	if supply.Fees.BaseFee.Sign() == 0 {
+		supply.Fees.BaseFee = nil
+	}
+	if len(supply.Fees.FeeCurrencies) == 0 {
+		supply.Fees.FeeCurrencies = nil
+	}
+	if supply.Fees.BaseFee == nil && supply.Fees.FeeCurrencies == nil {
+		supply.Fees = nil
+	}
+
+	out, _ := json.Marshal(supply)
+	if _, err := s.logger.Write(out); err != nil {
+		log.Warn("failed to write to celo supply tracer log file", "error", err)
+	}
+	if _, err := s.logger.Write([]byte{'\n'}); err != nil {
+		log.Warn("failed to write to celo supply tracer log file", "error", err)
+	}
+}
@@ -117,6 +117,9 @@ type StructLogger struct {
 	err     error
 	usedGas uint64
 
+	emit    func(StructLogRes) error // Receives the logs instead of collecting them if set
+	emitted int                      // Number of logs passed to emit
+
 	interrupt atomic.Bool // Atomic flag to signal execution interruption
 	reason    error       // Textual reason for the interruption
 }
@@ -132,6 +135,16 @@ func NewStructLogger(cfg *Config) *StructLogger {
 	return logger
 }
 
+// NewStreamingStructLogger returns a logger passing every log to emit as soon
+// as it is captured instead of collecting them, keeping memory usage bounded
+// regardless of the length of the trace. The result of the logger contains no
+// logs. Tracing is stopped if emit fails.
+func NewStreamingStructLogger(cfg *Config, emit func(StructLogRes) error) *StructLogger {
+	logger := NewStructLogger(cfg)
+	logger.emit = emit
+	return logger
+}
+
 func (l *StructLogger) Hooks() *tracing.Hooks {
 	return &tracing.Hooks{
 		OnTxStart: l.OnTxStart,
@@ -146,6 +159,7 @@ func (l *StructLogger) Reset() {
 	l.storage = make(map[common.Address]Storage)
 	l.output = make([]byte, 0)
 	l.logs = l.logs[:0]
+	l.emitted = 0
 	l.err = nil
 }
 
@@ -158,7 +172,7 @@ func (l *StructLogger) OnOpcode(pc uint64, opcode byte, gas, cost uint64, scope
 		return
 	}
 	// check if already accumulated the specified number of logs
-	if l.cfg.Limit != 0 && l.cfg.Limit <= len(l.logs) {
+	if l.cfg.Limit != 0 && l.cfg.Limit <= len(l.logs)+l.emitted {
 		return
 	}
 
@@ -212,6 +226,13 @@ func (l *StructLogger) OnOpcode(pc uint64, opcode byte, gas, cost uint64, scope
 	}
 	// create a new snapshot of the EVM.
 	log := StructLog{pc, op, gas, cost, mem, len(memory), stck, rdata, storage, depth, l.env.StateDB.GetRefund(), err}
+	if l.emit != nil {
+		l.emitted++
+		if err := l.emit(formatLog(log)); err != nil {
+			l.Stop(err)
+		}
+		return
+	}
 	l.logs = append(l.logs, log)
 }
 
@@ -440,39 +461,45 @@ type StructLogRes struct {
 func formatLogs(logs []StructLog) []StructLogRes {
 	formatted := make([]StructLogRes, len(logs))
 	for index, trace := range logs {
-		formatted[index] = StructLogRes{
-			Pc:            trace.Pc,
-			Op:            trace.Op.String(),
-			Gas:           trace.Gas,
-			GasCost:       trace.GasCost,
-			Depth:         trace.Depth,
-			Error:         trace.ErrorString(),
-			RefundCounter: trace.RefundCounter,
-		}
-		if trace.Stack != nil {
-			stack := make([]string, len(trace.Stack))
-			for i, stackValue := range trace.Stack {
-				stack[i] = stackValue.Hex()
-			}
-			formatted[index].Stack = &stack
-		}
-		if trace.ReturnData != nil && len(trace.ReturnData) > 0 {
-			formatted[index].ReturnData = hexutil.Bytes(trace.ReturnData).String()
+		formatted[index] = formatLog(trace)
+	}
+	return formatted
+}
+
+// formatLog formats a single EVM returned structured log for json output
+func formatLog(trace StructLog) StructLogRes {
+	formatted := StructLogRes{
+		Pc:            trace.Pc,
+		Op:            trace.Op.String(),
+		Gas:           trace.Gas,
+		GasCost:       trace.GasCost,
+		Depth:         trace.Depth,
+		Error:         trace.ErrorString(),
+		RefundCounter: trace.RefundCounter,
+	}
+	if trace.Stack != nil {
+		stack := make([]string, len(trace.Stack))
+		for i, stackValue := range trace.Stack {
+			stack[i] = stackValue.Hex()
 		}
-		if trace.Memory != nil {
-			memory := make([]string, 0, (len(trace.Memory)+31)/32)
-			for i := 0; i+32 <= len(trace.Memory); i += 32 {
-				memory = append(memory, fmt.Sprintf("%x", trace.Memory[i:i+32]))
-			}
-			formatted[index].Memory = &memory
+		formatted.Stack = &stack
+	}
+	if trace.ReturnData != nil && len(trace.ReturnData) > 0 {
+		formatted.ReturnData = hexutil.Bytes(trace.ReturnData).String()
+	}
+	if trace.Memory != nil {
+		memory := make([]string, 0, (len(trace.Memory)+31)/32)
+		for i := 0; i+32 <= len(trace.Memory); i += 32 {
+			memory = append(memory, fmt.Sprintf("%x", trace.Memory[i:i+32]))
 		}
-		if trace.Storage != nil {
-			storage := make(map[string]string)
-			for i, storageValue := range trace.Storage {
-				storage[fmt.Sprintf("%x", i)] = fmt.Sprintf("%x", storageValue)
-			}
-			formatted[index].Storage = &storage
+		formatted.Memory = &memory
+	}
+	if trace.Storage != nil {
+		storage := make(map[string]string)
+		for i, storageValue := range trace.Storage {
+			storage[fmt.Sprintf("%x", i)] = fmt.Sprintf("%x", storageValue)
 		}
+		formatted.Storage = &storage
 	}
 	return formatted
 }
@@ -0,0 +1,402 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxTraceStreams is the maximum number of traces streamed concurrently. As
+// streams are not subject to a timeout by default, they could otherwise tie up
+// the node indefinitely.
+const maxTraceStreams = 8
+
+var (
+	errStreamTracer       = errors.New("only the struct logger and callTracer can be streamed")
+	errStreamTracerConfig = errors.New("tracer config is not supported for streamed traces")
+	errStreamPreBedrock   = errors.New("streamed traces of pre-bedrock blocks are not supported")
+	errTooManyStreams     = fmt.Errorf("too many concurrent trace streams, the limit is %d", maxTraceStreams)
+)
+
+// streamedTrace is a notification of a streamed transaction trace, carrying
+// either a struct log, a call frame or the final result of the trace.
+type streamedTrace struct {
+	TxIndex   int                  `json:"txIndex"`
+	TxHash    common.Hash          `json:"txHash"`
+	StructLog *logger.StructLogRes `json:"structLog,omitempty"` // Struct log produced by the struct logger
+	Call      *streamedCall        `json:"call,omitempty"`      // Call frame produced by the call tracer
+	Result    json.RawMessage      `json:"result,omitempty"`    // Result of the trace, sent last
+	Error     string               `json:"error,omitempty"`     // Trace failure, sent last
+}
+
+// streamedCall is a call frame, streamed when the call exits. As the frames of
+// the subcalls are streamed before their parent, the call tree is rebuilt by
+// attaching the preceding Calls frames one level deeper to each frame.
+type streamedCall struct {
+	Type    string         `json:"type"`
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	Value   *hexutil.Big   `json:"value,omitempty"`
+	Gas     hexutil.Uint64 `json:"gas"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Input   hexutil.Bytes  `json:"input"`
+	Output  hexutil.Bytes  `json:"output,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Depth   int            `json:"depth"`
+	Calls   int            `json:"calls"` // Number of direct subcalls
+}
+
+// callStreamer is a tracer passing the call frames of a transaction to emit as
+// they exit. Only the frames being executed are kept in memory.
+type callStreamer struct {
+	emit    func(*streamedCall) error
+	stack   []*streamedCall
+	gasUsed uint64
+	failed  bool
+
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+// newCallStreamer returns a tracer streaming the call frames to emit. Tracing
+// is stopped if emit fails.
+func newCallStreamer(emit func(*streamedCall) error) *Tracer {
+	t := &callStreamer{emit: emit}
+	return &Tracer{
+		Hooks: &tracing.Hooks{
+			OnEnter: t.onEnter,
+			OnExit:  t.onExit,
+			OnTxEnd: t.onTxEnd,
+		},
+		GetResult: t.getResult,
+		Stop:      t.stop,
+	}
+}
+
+func (t *callStreamer) onEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.interrupt.Load() {
+		return
+	}
+	call := &streamedCall{
+		Type:  vm.OpCode(typ).String(),
+		From:  from,
+		To:    to,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+		Depth: depth,
+	}
+	if value != nil {
+		call.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	if len(t.stack) > 0 {
+		t.stack[len(t.stack)-1].Calls++
+	}
+	t.stack = append(t.stack, call)
+}
+
+func (t *callStreamer) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.interrupt.Load() || len(t.stack) == 0 {
+		return
+	}
+	call := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	call.GasUsed = hexutil.Uint64(gasUsed)
+	call.Output = common.CopyBytes(output)
+	if err != nil {
+		call.Error = err.Error()
+	}
+	if depth == 0 {
+		t.failed = err != nil
+	}
+	if err := t.emit(call); err != nil {
+		t.stop(err)
+	}
+}
+
+func (t *callStreamer) onTxEnd(receipt *types.Receipt, err error) {
+	if err == nil && receipt != nil {
+		t.gasUsed = receipt.GasUsed
+	}
+}
+
+func (t *callStreamer) getResult() (json.RawMessage, error) {
+	if t.reason != nil {
+		return nil, t.reason
+	}
+	return json.Marshal(&struct {
+		Gas    uint64 `json:"gas"`
+		Failed bool   `json:"failed"`
+	}{t.gasUsed, t.failed})
+}
+
+func (t *callStreamer) stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
+
+// checkStreamConfig returns an error if the trace configuration can't be used
+// for streamed traces.
+func checkStreamConfig(config *TraceConfig) error {
+	if config.Tracer != nil && *config.Tracer != "callTracer" {
+		return errStreamTracer
+	}
+	if len(config.TracerConfig) > 0 && string(config.TracerConfig) != "null" {
+		return errStreamTracerConfig
+	}
+	if _, err := parseTraceTimeout(config.Timeout, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+// acquireStream reserves one of the concurrent trace streams, returning the
+// function to release it.
+func (api *API) acquireStream() (func(), error) {
+	select {
+	case api.streams <- struct{}{}:
+		return func() { <-api.streams }, nil
+	default:
+		return nil, errTooManyStreams
+	}
+}
+
+// streamContext returns the context of a streamed trace, cancelled when the
+// client unsubscribes, and the function to send a notification. Sending blocks
+// until the notification is written to the connection, holding back tracing
+// until the client keeps up.
+func streamContext(notifier *rpc.Notifier, sub *rpc.Subscription) (context.Context, context.CancelFunc, func(*streamedTrace) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-sub.Err():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	notify := func(trace *streamedTrace) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := notifier.Notify(sub.ID, trace); err != nil {
+			cancel()
+			return err
+		}
+		return nil
+	}
+	return ctx, cancel, notify
+}
+
+// TraceBlockStream is the streaming variant of TraceBlockByNumber and
+// TraceBlockByHash. Instead of collecting the traces of the transactions, the
+// struct logs or call frames are sent as notifications as soon as produced,
+// followed by the result of each transaction trace. Memory usage is bounded
+// regardless of the size of the traces.
+func (api *API) TraceBlockStream(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, config *TraceConfig) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if config == nil {
+		config = &TraceConfig{}
+	}
+	if err := checkStreamConfig(config); err != nil {
+		return nil, err
+	}
+	block, err := api.callBlock(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if api.backend.ChainConfig().IsOptimismPreBedrock(block.Number()) {
+		return nil, errStreamPreBedrock
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	parent, err := api.blockByNumberAndHash(ctx, rpc.BlockNumber(block.NumberU64()-1), block.ParentHash())
+	if err != nil {
+		return nil, err
+	}
+	reexec := defaultTraceReexec
+	if config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	done, err := api.acquireStream()
+	if err != nil {
+		return nil, err
+	}
+	statedb, release, err := api.backend.StateAtBlock(ctx, parent, reexec, nil, true, false)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer done()
+		defer release()
+
+		ctx, cancel, notify := streamContext(notifier, sub)
+		defer cancel()
+		api.streamBlock(ctx, block, statedb, config, notify)
+	}()
+	return sub, nil
+}
+
+// streamBlock streams the traces of the transactions of the block, stopping at
+// the first failure.
+func (api *API) streamBlock(ctx context.Context, block *types.Block, statedb *state.StateDB, config *TraceConfig, notify func(*streamedTrace) error) {
+	var (
+		blockHash = block.Hash()
+		blockCtx  = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil, api.backend.ChainConfig(), statedb)
+		signer    = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
+	)
+	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
+		vmenv := vm.NewEVM(blockCtx, vm.TxContext{}, statedb, api.backend.ChainConfig(), vm.Config{})
+		core.ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
+	}
+	for i, tx := range block.Transactions() {
+		msg, _ := core.TransactionToMessage(tx, signer, block.BaseFee(), blockCtx.FeeCurrencyContext.ExchangeRates)
+		txctx := &Context{
+			BlockHash:   blockHash,
+			BlockNumber: block.Number(),
+			TxIndex:     i,
+			TxHash:      tx.Hash(),
+		}
+		if err := api.streamTx(ctx, tx, msg, txctx, blockCtx, statedb, config, notify); err != nil {
+			return
+		}
+	}
+}
+
+// TraceTransactionStream is the streaming variant of TraceTransaction, sending
+// the struct logs or call frames as notifications as soon as produced, followed
+// by the result of the trace.
+func (api *API) TraceTransactionStream(ctx context.Context, hash common.Hash, config *TraceConfig) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if config == nil {
+		config = &TraceConfig{}
+	}
+	if err := checkStreamConfig(config); err != nil {
+		return nil, err
+	}
+	_, _, blockHash, blockNumber, index, err := api.backend.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, ethapi.NewTxIndexingError()
+	}
+	if api.backend.ChainConfig().IsOptimismPreBedrock(new(big.Int).SetUint64(blockNumber)) {
+		return nil, errStreamPreBedrock
+	}
+	// It shouldn't happen in practice.
+	if blockNumber == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	reexec := defaultTraceReexec
+	if config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	block, err := api.blockByNumberAndHash(ctx, rpc.BlockNumber(blockNumber), blockHash)
+	if err != nil {
+		return nil, err
+	}
+	done, err := api.acquireStream()
+	if err != nil {
+		return nil, err
+	}
+	tx, vmctx, statedb, release, err := api.backend.StateAtTransaction(ctx, block, int(index), reexec)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	exchangeRates := core.GetExchangeRates(block.Header(), api.backend.ChainConfig(), statedb)
+	msg, err := core.TransactionToMessage(tx, types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time()), block.BaseFee(), exchangeRates)
+	if err != nil {
+		release()
+		done()
+		return nil, err
+	}
+	txctx := &Context{
+		BlockHash:   blockHash,
+		BlockNumber: block.Number(),
+		TxIndex:     int(index),
+		TxHash:      hash,
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer done()
+		defer release()
+
+		ctx, cancel, notify := streamContext(notifier, sub)
+		defer cancel()
+		api.streamTx(ctx, tx, msg, txctx, vmctx, statedb, config, notify)
+	}()
+	return sub, nil
+}
+
+// streamTx executes the given message in the provided environment, streaming
+// the trace produced by the configured tracer. The final notification carries
+// the result of the trace, or the failure. Unlike regular traces, streams are
+// only subject to an explicitly configured timeout, as tracing is held back by
+// a slow client.
+func (api *API) streamTx(ctx context.Context, tx *types.Transaction, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig, notify func(*streamedTrace) error) error {
+	var tracer *Tracer
+	if config.Tracer == nil {
+		logger := logger.NewStreamingStructLogger(config.Config, func(log logger.StructLogRes) error {
+			return notify(&streamedTrace{TxIndex: txctx.TxIndex, TxHash: txctx.TxHash, StructLog: &log})
+		})
+		tracer = &Tracer{
+			Hooks:     logger.Hooks(),
+			GetResult: logger.GetResult,
+			Stop:      logger.Stop,
+		}
+	} else {
+		tracer = newCallStreamer(func(call *streamedCall) error {
+			return notify(&streamedTrace{TxIndex: txctx.TxIndex, TxHash: txctx.TxHash, Call: call})
+		})
+	}
+	timeout, _ := parseTraceTimeout(config.Timeout, 0) // Validated by checkStreamConfig
+	res, err := api.traceTxWithTracer(ctx, tx, message, txctx, vmctx, statedb, tracer, timeout)
+	if ctx.Err() != nil {
+		return ctx.Err() // Unsubscribed or the connection failed
+	}
+	result := &streamedTrace{TxIndex: txctx.TxIndex, TxHash: txctx.TxHash, Result: res}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if nerr := notify(result); nerr != nil {
+		return nerr
+	}
+	return err
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// receiveStream collects the notifications of a streamed trace until the
+// results of n transactions are received.
+func receiveStream(t *testing.T, ch chan *streamedTrace, sub *rpc.ClientSubscription, n int) []*streamedTrace {
+	t.Helper()
+
+	var traces []*streamedTrace
+	for results := 0; results < n; {
+		select {
+		case trace := <-ch:
+			traces = append(traces, trace)
+			if trace.Result != nil || trace.Error != "" {
+				results++
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout receiving streamed trace")
+		}
+	}
+	return traces
+}
+
+func TestTraceStream(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		contract = common.HexToAddress("0xc0de")
+		callee   = common.HexToAddress("0xca11ee")
+		target   common.Hash
+	)
+	// The contract calls the callee with no arguments and stops
+	code := append([]byte{0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x73}, callee.Bytes()...)
+	code = append(code, 0x5a, 0xf1, 0x00)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			contract:         {Code: code},
+		},
+	}
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		for n := 0; n < 2; n++ {
+			tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+				Nonce:    uint64(n),
+				To:       &contract,
+				Gas:      100000,
+				GasPrice: b.BaseFee(),
+			}), types.HomesteadSigner{}, accounts[0].key)
+			b.AddTx(tx)
+			target = tx.Hash()
+		}
+	})
+	defer backend.chain.Stop()
+	api := NewAPI(backend)
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("debug", api); err != nil {
+		t.Fatalf("failed to register api: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	// Streamed struct logs match the collected ones
+	config := &TraceConfig{Config: &logger.Config{DisableStorage: true}}
+	traced, err := api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(1), config)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	ch := make(chan *streamedTrace, 16)
+	sub, err := client.Subscribe(context.Background(), "debug", ch, "traceBlockStream", rpc.BlockNumber(1), config)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	traces := receiveStream(t, ch, sub, len(traced))
+	sub.Unsubscribe()
+
+	logs := make([]int, len(traced))
+	for _, trace := range traces {
+		if trace.TxHash != traced[trace.TxIndex].TxHash {
+			t.Fatalf("tx %d hash mismatch: have %x, want %x", trace.TxIndex, trace.TxHash, traced[trace.TxIndex].TxHash)
+		}
+		if trace.StructLog != nil {
+			logs[trace.TxIndex]++
+			continue
+		}
+		var have, want logger.ExecutionResult
+		if err := json.Unmarshal(trace.Result, &have); err != nil {
+			t.Fatalf("failed to unmarshal result: %v", err)
+		}
+		json.Unmarshal(traced[trace.TxIndex].Result.(json.RawMessage), &want)
+		if len(have.StructLogs) != 0 {
+			t.Errorf("tx %d: struct logs included in the final result", trace.TxIndex)
+		}
+		if have.Gas != want.Gas || have.Failed != want.Failed || logs[trace.TxIndex] != len(want.StructLogs) {
+			t.Errorf("tx %d: result mismatch: have gas %d, %d logs, want gas %d, %d logs", trace.TxIndex, have.Gas, logs[trace.TxIndex], want.Gas, len(want.StructLogs))
+		}
+	}
+	// Call frames are streamed in post-order
+	tracer := "callTracer"
+	ch = make(chan *streamedTrace, 16)
+	sub, err = client.Subscribe(context.Background(), "debug", ch, "traceTransactionStream", target, &TraceConfig{Tracer: &tracer})
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	traces = receiveStream(t, ch, sub, 1)
+	sub.Unsubscribe()
+
+	if len(traces) != 3 {
+		t.Fatalf("wrong number of notifications: have %d, want 3", len(traces))
+	}
+	if call := traces[0].Call; call == nil || call.To != callee || call.Depth != 1 {
+		t.Errorf("wrong subcall frame: %+v", call)
+	}
+	if call := traces[1].Call; call == nil || call.To != contract || call.Depth != 0 || call.Calls != 1 {
+		t.Errorf("wrong top call frame: %+v", call)
+	}
+	if traces[2].TxIndex != 1 || traces[2].TxHash != target || traces[2].Result == nil {
+		t.Errorf("wrong final notification: %+v", traces[2])
+	}
+	// Other tracers can't be streamed
+	tracer = "prestateTracer"
+	if _, err := client.Subscribe(context.Background(), "debug", ch, "traceBlockStream", rpc.BlockNumber(1), &TraceConfig{Tracer: &tracer}); err == nil {
+		t.Error("expected error streaming unsupported tracer")
+	}
+	timeout := "forever"
+	if _, err := client.Subscribe(context.Background(), "debug", ch, "traceBlockStream", rpc.BlockNumber(1), &TraceConfig{Timeout: &timeout}); err == nil {
+		t.Error("expected error streaming with invalid timeout")
+	}
+	// The number of concurrent streams is limited
+	for i := 0; i < maxTraceStreams; i++ {
+		api.streams <- struct{}{}
+	}
+	if _, err := client.Subscribe(context.Background(), "debug", ch, "traceTransactionStream", target, nil); err == nil || err.Error() != errTooManyStreams.Error() {
+		t.Errorf("stream limit error mismatch: have %v, want %v", err, errTooManyStreams)
+	}
+	<-api.streams
+	sub, err = client.Subscribe(context.Background(), "debug", ch, "traceTransactionStream", target, nil)
+	if err != nil {
+		t.Fatalf("failed to subscribe below the stream limit: %v", err)
+	}
+	receiveStream(t, ch, sub, 1)
+	sub.Unsubscribe()
+}
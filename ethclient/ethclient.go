@@ -699,6 +699,12 @@ func toCallArg(msg ethereum.CallMsg) interface{} {
 	if msg.BlobHashes != nil {
 		arg["blobVersionedHashes"] = msg.BlobHashes
 	}
+	if msg.FeeCurrency != nil {
+		arg["feeCurrency"] = msg.FeeCurrency
+	}
+	if msg.MaxFeeInFeeCurrency != nil {
+		arg["maxFeeInFeeCurrency"] = (*hexutil.Big)(msg.MaxFeeInFeeCurrency)
+	}
 	return arg
 }
 
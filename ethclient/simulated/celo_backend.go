@@ -0,0 +1,54 @@
+package simulated
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/addresses"
+	"github.com/ethereum/go-ethereum/contracts/celo/abigen"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// setExchangeRateGas is the gas limit used to update a mock oracle.
+const setExchangeRateGas = 100_000
+
+// SetExchangeRate sends a transaction signed by key, which updates the
+// exchange rate of feeCurrency in its mock oracle to numerator / denominator
+// units of the fee currency per CELO. The transaction is included by the next
+// call to Commit, and the new rate applies to all blocks built after that.
+//
+// The fee currency must have been registered with a mock oracle, e.g. via
+// WithCeloGenesis.
+func (n *Backend) SetExchangeRate(key *ecdsa.PrivateKey, feeCurrency common.Address, numerator, denominator *big.Int) (*types.Transaction, error) {
+	directory, err := abigen.NewFeeCurrencyDirectoryCaller(addresses.FeeCurrencyDirectoryAddress, n.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access FeeCurrencyDirectory: %w", err)
+	}
+	currencyConfig, err := directory.GetCurrencyConfig(&bind.CallOpts{}, feeCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config for fee currency %s: %w", feeCurrency, err)
+	}
+	if currencyConfig.Oracle == (common.Address{}) {
+		return nil, fmt.Errorf("fee currency %s is not registered", feeCurrency)
+	}
+	oracle, err := abigen.NewMockOracleTransactor(currencyConfig.Oracle, n.client)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := n.client.ChainID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(key, chainID)
+	if err != nil {
+		return nil, err
+	}
+	// The oracle stores the block timestamp, which makes gas estimation on top
+	// of a genesis block with timestamp 0 underestimate the storage costs.
+	opts.GasLimit = setExchangeRateGas
+	return oracle.SetExchangeRate(opts, feeCurrency, numerator, denominator)
+}
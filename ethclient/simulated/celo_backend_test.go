@@ -0,0 +1,120 @@
+package simulated
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/contracts/addresses"
+	"github.com/ethereum/go-ethereum/contracts/celo/abigen"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var testFeeCurrency = common.HexToAddress("0x000000000000000000000000000000000000ce16")
+
+func simCeloTestBackend(testAddr common.Address) *Backend {
+	return NewBackend(
+		types.GenesisAlloc{
+			testAddr: {Balance: big.NewInt(10000000000000000)},
+		},
+		WithCeloGenesis(&core.CeloGenesisConfig{
+			FeeCurrencies: []core.CeloGenesisFeeCurrency{{
+				Address:         testFeeCurrency,
+				RateNumerator:   math.NewHexOrDecimal256(2),
+				RateDenominator: math.NewHexOrDecimal256(1),
+				Balances: map[common.Address]*math.HexOrDecimal256{
+					testAddr: (*math.HexOrDecimal256)(big.NewInt(params.Ether)),
+				},
+			}},
+		}),
+	)
+}
+
+// Tests that transactions paying gas in a fee currency can be sent to a
+// simulated backend started with the Celo genesis contracts.
+func TestSendFeeCurrencyTransaction(t *testing.T) {
+	sim := simCeloTestBackend(testAddr)
+	defer sim.Close()
+
+	client := sim.Client()
+	ctx := context.Background()
+
+	head, _ := client.HeaderByNumber(ctx, nil)
+	chainid, _ := client.ChainID(ctx)
+	// The base fee is converted with a rate of 2 fee currency units per CELO
+	gasFeeCap := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(4)), big.NewInt(params.GWei))
+	tx := types.NewTx(&types.CeloDynamicFeeTxV2{
+		ChainID:     chainid,
+		Nonce:       0,
+		GasTipCap:   big.NewInt(params.GWei),
+		GasFeeCap:   gasFeeCap,
+		Gas:         100000,
+		To:          &testAddr,
+		FeeCurrency: &testFeeCurrency,
+	})
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainid), testKey)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		t.Fatalf("could not add tx to pending block: %v", err)
+	}
+	sim.Commit()
+
+	receipt, err := client.TransactionReceipt(ctx, signedTx.Hash())
+	if err != nil {
+		t.Fatalf("could not get receipt: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("transaction failed")
+	}
+	token, err := abigen.NewFeeCurrencyCaller(testFeeCurrency, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance, err := token.BalanceOf(&bind.CallOpts{}, testAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Cmp(big.NewInt(params.Ether)) >= 0 {
+		t.Errorf("fees were not paid in fee currency, balance is %v", balance)
+	}
+}
+
+// Tests that exchange rates can be changed between blocks.
+func TestSetExchangeRate(t *testing.T) {
+	sim := simCeloTestBackend(testAddr)
+	defer sim.Close()
+
+	client := sim.Client()
+	tx, err := sim.SetExchangeRate(testKey, testFeeCurrency, big.NewInt(3), big.NewInt(2))
+	if err != nil {
+		t.Fatalf("could not set exchange rate: %v", err)
+	}
+	sim.Commit()
+	receipt, err := client.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("exchange rate update failed: %v %v", err, receipt)
+	}
+
+	directory, err := abigen.NewFeeCurrencyDirectoryCaller(addresses.FeeCurrencyDirectoryAddress, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rate, err := directory.GetExchangeRate(&bind.CallOpts{}, testFeeCurrency)
+	if err != nil {
+		t.Fatalf("could not get exchange rate: %v", err)
+	}
+	if rate.Numerator.Cmp(big.NewInt(3)) != 0 || rate.Denominator.Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("exchange rate mismatch: have %v/%v, want 3/2", rate.Numerator, rate.Denominator)
+	}
+
+	if _, err := sim.SetExchangeRate(testKey, testAddr, big.NewInt(1), big.NewInt(1)); err == nil {
+		t.Errorf("expected error for unregistered fee currency")
+	}
+}
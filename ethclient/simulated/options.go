@@ -19,6 +19,8 @@ package simulated
 import (
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/node"
 )
@@ -53,3 +55,34 @@ func WithMinerMinTip(tip *big.Int) func(nodeConf *node.Config, ethConf *ethconfi
 		ethConf.Miner.GasPrice = tip
 	}
 }
+
+// WithCeloGenesis configures the simulated backend to start with the Celo core
+// contracts deployed: the CELO token, the FeeCurrencyDirectory and the fee
+// currencies listed in the config, each backed by a mock oracle with the given
+// exchange rate. If the config doesn't contain a chain config, the one of the
+// simulated backend is used.
+//
+// Exchange rates can be changed between blocks with Backend.SetExchangeRate.
+func WithCeloGenesis(celoGenesis *core.CeloGenesisConfig) func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+	return func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		celoGenesis := *celoGenesis
+		if celoGenesis.Config == nil {
+			celoGenesis.Config = ethConf.Genesis.Config
+		}
+		config := celoGenesis.ChainConfig()
+		celoAlloc, err := celoGenesis.Alloc(config)
+		if err != nil {
+			panic(err)
+		}
+		// Don't modify the alloc passed in by the caller
+		alloc := make(types.GenesisAlloc, len(ethConf.Genesis.Alloc)+len(celoAlloc))
+		for addr, account := range ethConf.Genesis.Alloc {
+			alloc[addr] = account
+		}
+		for addr, account := range celoAlloc {
+			alloc[addr] = account
+		}
+		ethConf.Genesis.Config = config
+		ethConf.Genesis.Alloc = alloc
+	}
+}
@@ -0,0 +1,114 @@
+package ethstats
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// exchangeRatePrecision is the number of decimals reported for exchange rates.
+const exchangeRatePrecision = 18
+
+// celoBackend encompasses the functionality necessary for reporting Celo fee
+// currency data to ethstats. The Celo data is only reported if the backend
+// implements it, to keep stock ethstats servers working.
+type celoBackend interface {
+	fullNodeBackend
+	GetExchangeRates(ctx context.Context, blockNumOrHash rpc.BlockNumberOrHash) (common.ExchangeRates, error)
+	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
+	BlockedFeeCurrencies() []common.Address
+}
+
+// celoNodeStats is the Celo specific information to report about the local node.
+type celoNodeStats struct {
+	ExchangeRates        map[common.Address]string `json:"exchangeRates"`
+	BlockedFeeCurrencies []common.Address          `json:"blockedFeeCurrencies"`
+}
+
+// celoBlockStats is the Celo specific information to report about individual
+// blocks.
+type celoBlockStats struct {
+	FeeCurrencyGas []feeCurrencyGasStats `json:"feeCurrencyGas"`
+}
+
+// feeCurrencyGasStats is the gas used by the transactions of a block paying
+// in one fee currency. A nil fee currency stands for CELO.
+type feeCurrencyGasStats struct {
+	FeeCurrency *common.Address `json:"feeCurrency"`
+	GasUsed     uint64          `json:"gasUsed"`
+}
+
+// assembleCeloNodeStats retrieves the exchange rates at the chain head and the
+// fee currencies blocked by the local miner. It returns nil if the backend
+// doesn't support Celo reporting.
+func (s *Service) assembleCeloNodeStats() *celoNodeStats {
+	backend, ok := s.backend.(celoBackend)
+	if !ok {
+		return nil
+	}
+	stats := &celoNodeStats{
+		ExchangeRates:        make(map[common.Address]string),
+		BlockedFeeCurrencies: backend.BlockedFeeCurrencies(),
+	}
+	if stats.BlockedFeeCurrencies == nil {
+		stats.BlockedFeeCurrencies = []common.Address{}
+	}
+	rates, err := backend.GetExchangeRates(context.Background(), rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	if err != nil {
+		log.Debug("Failed to retrieve exchange rates for ethstats", "err", err)
+		return stats
+	}
+	for currency, rate := range rates {
+		stats.ExchangeRates[currency] = rate.FloatString(exchangeRatePrecision)
+	}
+	return stats
+}
+
+// assembleCeloBlockStats retrieves the receipts of the block and splits its
+// gas usage by fee currency. It returns nil if the backend doesn't support
+// Celo reporting or the receipts are not available.
+func (s *Service) assembleCeloBlockStats(block *types.Block) *celoBlockStats {
+	backend, ok := s.backend.(celoBackend)
+	if !ok {
+		return nil
+	}
+	receipts, err := backend.GetReceipts(context.Background(), block.Hash())
+	if err != nil || len(receipts) != len(block.Transactions()) {
+		log.Debug("Failed to retrieve receipts for ethstats", "number", block.Number(), "hash", block.Hash(), "err", err)
+		return nil
+	}
+	return &celoBlockStats{FeeCurrencyGas: feeCurrencyGasUsed(block.Transactions(), receipts)}
+}
+
+// feeCurrencyGasUsed sums up the gas used by the transactions per fee currency.
+// CELO comes first, followed by the fee currencies in address order. Only fee
+// currencies with transactions are included.
+func feeCurrencyGasUsed(txs types.Transactions, receipts types.Receipts) []feeCurrencyGasStats {
+	var (
+		celoGas uint64
+		gas     = make(map[common.Address]uint64)
+	)
+	for i, tx := range txs {
+		if currency := tx.FeeCurrency(); currency != nil {
+			gas[*currency] += receipts[i].GasUsed
+		} else {
+			celoGas += receipts[i].GasUsed
+		}
+	}
+	currencies := make([]common.Address, 0, len(gas))
+	for currency := range gas {
+		currencies = append(currencies, currency)
+	}
+	slices.SortFunc(currencies, common.Address.Cmp)
+
+	stats := []feeCurrencyGasStats{{GasUsed: celoGas}}
+	for _, currency := range currencies {
+		currency := currency
+		stats = append(stats, feeCurrencyGasStats{FeeCurrency: &currency, GasUsed: gas[currency]})
+	}
+	return stats
+}
@@ -0,0 +1,34 @@
+package ethstats
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestFeeCurrencyGasUsed(t *testing.T) {
+	var (
+		currency1 = common.HexToAddress("0x00000000000000000000000000000000000fee01")
+		currency2 = common.HexToAddress("0x00000000000000000000000000000000000fee02")
+	)
+	txs := types.Transactions{
+		types.NewTx(&types.CeloDynamicFeeTxV2{FeeCurrency: &currency2}),
+		types.NewTx(&types.DynamicFeeTx{}),
+		types.NewTx(&types.CeloDynamicFeeTxV2{FeeCurrency: &currency1}),
+		types.NewTx(&types.CeloDynamicFeeTxV2{FeeCurrency: &currency2}),
+	}
+	receipts := types.Receipts{{GasUsed: 50000}, {GasUsed: 21000}, {GasUsed: 60000}, {GasUsed: 70000}}
+
+	blob, err := json.Marshal(feeCurrencyGasUsed(txs, receipts))
+	if err != nil {
+		t.Fatalf("failed to marshal stats: %v", err)
+	}
+	want := `[{"feeCurrency":null,"gasUsed":21000},` +
+		`{"feeCurrency":"0x00000000000000000000000000000000000fee01","gasUsed":60000},` +
+		`{"feeCurrency":"0x00000000000000000000000000000000000fee02","gasUsed":120000}]`
+	if string(blob) != want {
+		t.Errorf("fee currency gas mismatch:\nhave %s\nwant %s", blob, want)
+	}
+}
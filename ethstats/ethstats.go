@@ -583,6 +583,8 @@ type blockStats struct {
 	TxHash     common.Hash    `json:"transactionsRoot"`
 	Root       common.Hash    `json:"stateRoot"`
 	Uncles     uncleStats     `json:"uncles"`
+
+	Celo *celoBlockStats `json:"celo,omitempty"`
 }
 
 // txStats is the information to report about individual transactions.
@@ -632,6 +634,7 @@ func (s *Service) assembleBlockStats(block *types.Block) *blockStats {
 		td     *big.Int
 		txs    []txStats
 		uncles []*types.Header
+		celo   *celoBlockStats
 	)
 
 	// check if backend is a full node
@@ -655,6 +658,7 @@ func (s *Service) assembleBlockStats(block *types.Block) *blockStats {
 			txs[i].Hash = tx.Hash()
 		}
 		uncles = block.Uncles()
+		celo = s.assembleCeloBlockStats(block)
 	} else {
 		// Light nodes would need on-demand lookups for transactions/uncles, skip
 		if block != nil {
@@ -683,6 +687,7 @@ func (s *Service) assembleBlockStats(block *types.Block) *blockStats {
 		TxHash:     header.TxHash,
 		Root:       header.Root,
 		Uncles:     uncles,
+		Celo:       celo,
 	}
 }
 
@@ -775,6 +780,8 @@ type nodeStats struct {
 	Peers    int  `json:"peers"`
 	GasPrice int  `json:"gasPrice"`
 	Uptime   int  `json:"uptime"`
+
+	Celo *celoNodeStats `json:"celo,omitempty"`
 }
 
 // reportStats retrieves various stats about the node at the networking layer
@@ -810,6 +817,7 @@ func (s *Service) reportStats(conn *connWrapper) error {
 			GasPrice: gasprice,
 			Syncing:  syncing,
 			Uptime:   100,
+			Celo:     s.assembleCeloNodeStats(),
 		},
 	}
 	report := map[string][]interface{}{
@@ -156,6 +156,17 @@ type CallMsg struct {
 	// For BlobTxType
 	BlobGasFeeCap *big.Int
 	BlobHashes    []common.Hash
+
+	// Celo specific
+	FeeCurrency         *common.Address // fee currency to pay gas in (nil = native currency)
+	MaxFeeInFeeCurrency *big.Int        // CIP-66 cap of the fee in fee currency, fees are denominated in native currency if set
+}
+
+// FeeCurrencyGasPricer wraps the Celo gas price oracle, which suggests prices
+// converted to a fee currency.
+type FeeCurrencyGasPricer interface {
+	SuggestGasPriceForCurrency(ctx context.Context, feeCurrency *common.Address) (*big.Int, error)
+	SuggestGasTipCapForCurrency(ctx context.Context, feeCurrency *common.Address) (*big.Int, error)
 }
 
 // A ContractCaller provides contract calls, essentially transactions that are executed by
@@ -75,3 +75,12 @@ func (b *CeloAPIBackend) ConvertToCelo(ctx context.Context, blockNumOrHash rpc.B
 	}
 	return exchange.ConvertCurrencyToCelo(er, fromFeeCurrency, currencyAmount)
 }
+
+// BlockedFeeCurrencies returns the fee currencies the local miner temporarily
+// excludes from block building, if the wrapped backend has a miner.
+func (b *CeloAPIBackend) BlockedFeeCurrencies() []common.Address {
+	if mb, ok := b.Backend.(interface{ BlockedFeeCurrencies() []common.Address }); ok {
+		return mb.BlockedFeeCurrencies()
+	}
+	return nil
+}
@@ -1151,6 +1151,39 @@ func (diff *BlockOverrides) Apply(blockCtx *vm.BlockContext) {
 	}
 }
 
+// MakeHeader returns a new header object with the overridden
+// fields.
+// Note: MakeHeader ignores BlobBaseFee if set. That's because
+// header has no such field.
+func (diff *BlockOverrides) MakeHeader(header *types.Header) *types.Header {
+	if diff == nil {
+		return header
+	}
+	h := types.CopyHeader(header)
+	if diff.Number != nil {
+		h.Number = diff.Number.ToInt()
+	}
+	if diff.Difficulty != nil {
+		h.Difficulty = diff.Difficulty.ToInt()
+	}
+	if diff.Time != nil {
+		h.Time = uint64(*diff.Time)
+	}
+	if diff.GasLimit != nil {
+		h.GasLimit = uint64(*diff.GasLimit)
+	}
+	if diff.Coinbase != nil {
+		h.Coinbase = *diff.Coinbase
+	}
+	if diff.Random != nil {
+		h.MixDigest = *diff.Random
+	}
+	if diff.BaseFee != nil {
+		h.BaseFee = diff.BaseFee.ToInt()
+	}
+	return h
+}
+
 // ChainContextBackend provides methods required to implement ChainContext.
 type ChainContextBackend interface {
 	Engine() consensus.Engine
@@ -1209,7 +1242,11 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 	}
 	msg := args.ToMessage(blockCtx.BaseFee, blockCtx.FeeCurrencyContext.ExchangeRates)
 	evm := b.GetEVM(ctx, msg, state, header, &vm.Config{NoBaseFee: true}, &blockCtx)
+	gp := new(core.GasPool).AddGas(math.MaxUint64)
+	return applyMessageWithEVM(ctx, evm, msg, state, timeout, gp)
+}
 
+func applyMessageWithEVM(ctx context.Context, evm *vm.EVM, msg *core.Message, state *state.StateDB, timeout time.Duration, gp *core.GasPool) (*core.ExecutionResult, error) {
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
 	go func() {
@@ -1218,7 +1255,6 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 	}()
 
 	// Execute the message.
-	gp := new(core.GasPool).AddGas(math.MaxUint64)
 	result, err := core.ApplyMessage(evm, msg, gp)
 	if err := state.Error(); err != nil {
 		return nil, err
@@ -1286,6 +1322,47 @@ func (api *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockN
 	return result.Return(), result.Err
 }
 
+// SimulateV1 executes series of transactions on top of a base state.
+// The transactions are packed into blocks. For each block, block header
+// fields can be overridden. The state can also be overridden prior to
+// execution of each block.
+//
+// Transactions paying for gas in a fee currency are debited and credited
+// like on chain, using the fee currency context of the simulated block.
+//
+// Note, this function doesn't make any changes in the state/blockchain and is
+// useful to execute and retrieve values.
+func (api *BlockChainAPI) SimulateV1(ctx context.Context, opts simOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, &invalidParamsError{message: "empty input"}
+	} else if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, &clientLimitExceededError{message: "too many blocks"}
+	}
+	if blockNrOrHash == nil {
+		n := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &n
+	}
+	state, base, err := api.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if api.b.ChainConfig().IsOptimismPreBedrock(base.Number) {
+		return nil, errors.New("eth_simulateV1 is not supported for pre-bedrock blocks")
+	}
+	sim := &simulator{
+		b:           api.b,
+		state:       state,
+		base:        base,
+		chainConfig: api.b.ChainConfig(),
+		// Each tx and all the series of txes shouldn't consume more gas than cap
+		gp:             new(core.GasPool).AddGas(api.b.RPCGasCap()),
+		traceTransfers: opts.TraceTransfers,
+		validate:       opts.Validation,
+		fullTx:         opts.ReturnFullTransactions,
+	}
+	return sim.execute(ctx, opts.BlockStateCalls)
+}
+
 // DoEstimateGas returns the lowest possible gas limit that allows the transaction to run
 // successfully at block `blockNrOrHash`. It returns error if the transaction would revert, or if
 // there are unexpected failures. The gas limit is capped by both `args.Gas` (if non-nil &
@@ -45,6 +45,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -825,6 +826,8 @@ func (b testBackend) BloomStatus() (uint64, uint64) { panic("implement me") }
 func (b testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	panic("implement me")
 }
+func (b testBackend) LogIndex() *filtermaps.FilterMaps { panic("implement me") }
+func (b testBackend) HistoryPruningCutoff() uint64     { return 0 }
 func (b testBackend) HistoricalRPCService() *rpc.Client {
 	panic("implement me")
 }
@@ -17,10 +17,14 @@
 package ethapi
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/exchange"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/contracts"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/vm"
 )
 
@@ -76,3 +80,116 @@ func (e *TxIndexingError) ErrorCode() int {
 
 // ErrorData returns the hex encoded revert reason.
 func (e *TxIndexingError) ErrorData() interface{} { return "transaction indexing is in progress" }
+
+// PrunedHistoryError is an API error that indicates the requested block bodies
+// or receipts were pruned from the chain history of the node.
+type PrunedHistoryError struct{}
+
+// Error implement error interface, returning the error message.
+func (e *PrunedHistoryError) Error() string {
+	return "pruned history unavailable"
+}
+
+// ErrorCode returns the JSON error code for pruned history.
+func (e *PrunedHistoryError) ErrorCode() int {
+	return errCodePrunedHistory
+}
+
+type callError struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+	Data    string `json:"data,omitempty"`
+}
+
+type invalidTxError struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+func (e *invalidTxError) Error() string  { return e.Message }
+func (e *invalidTxError) ErrorCode() int { return e.Code }
+
+const (
+	errCodeNonceTooHigh            = -38011
+	errCodeNonceTooLow             = -38010
+	errCodeIntrinsicGas            = -38013
+	errCodeInsufficientFunds       = -38014
+	errCodeBlockGasLimitReached    = -38015
+	errCodeBlockNumberInvalid      = -38020
+	errCodeBlockTimestampInvalid   = -38021
+	errCodeSenderIsNotEOA          = -38024
+	errCodeMaxInitCodeSizeExceeded = -38025
+	errCodeClientLimitExceeded     = -38026
+	errCodeInternalError           = -32603
+	errCodeInvalidParams           = -32602
+	errCodeReverted                = -32000
+	errCodeVMError                 = -32015
+	errCodeFeeCurrencyInvalid      = -38030
+	errCodePrunedHistory           = 4444
+)
+
+func txValidationError(err error) *invalidTxError {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(err, core.ErrNonceTooHigh):
+		return &invalidTxError{Message: err.Error(), Code: errCodeNonceTooHigh}
+	case errors.Is(err, core.ErrNonceTooLow):
+		return &invalidTxError{Message: err.Error(), Code: errCodeNonceTooLow}
+	case errors.Is(err, core.ErrSenderNoEOA):
+		return &invalidTxError{Message: err.Error(), Code: errCodeSenderIsNotEOA}
+	case errors.Is(err, core.ErrFeeCapVeryHigh):
+		return &invalidTxError{Message: err.Error(), Code: errCodeInvalidParams}
+	case errors.Is(err, core.ErrTipVeryHigh):
+		return &invalidTxError{Message: err.Error(), Code: errCodeInvalidParams}
+	case errors.Is(err, core.ErrTipAboveFeeCap):
+		return &invalidTxError{Message: err.Error(), Code: errCodeInvalidParams}
+	case errors.Is(err, core.ErrFeeCapTooLow):
+		return &invalidTxError{Message: err.Error(), Code: errCodeInvalidParams}
+	case errors.Is(err, core.ErrInsufficientFunds):
+		return &invalidTxError{Message: err.Error(), Code: errCodeInsufficientFunds}
+	case errors.Is(err, core.ErrIntrinsicGas):
+		return &invalidTxError{Message: err.Error(), Code: errCodeIntrinsicGas}
+	case errors.Is(err, core.ErrInsufficientFundsForTransfer):
+		return &invalidTxError{Message: err.Error(), Code: errCodeInsufficientFunds}
+	case errors.Is(err, core.ErrMaxInitCodeSizeExceeded):
+		return &invalidTxError{Message: err.Error(), Code: errCodeMaxInitCodeSizeExceeded}
+	// Celo specific
+	case errors.Is(err, core.ErrCel2NotEnabled):
+		return &invalidTxError{Message: err.Error(), Code: errCodeFeeCurrencyInvalid}
+	case errors.Is(err, exchange.ErrUnregisteredFeeCurrency):
+		return &invalidTxError{Message: err.Error(), Code: errCodeFeeCurrencyInvalid}
+	case errors.Is(err, contracts.ErrFeeCurrencyEVMCall):
+		return &invalidTxError{Message: err.Error(), Code: errCodeFeeCurrencyInvalid}
+	}
+	return &invalidTxError{
+		Message: err.Error(),
+		Code:    errCodeInternalError,
+	}
+}
+
+type invalidParamsError struct{ message string }
+
+func (e *invalidParamsError) Error() string  { return e.message }
+func (e *invalidParamsError) ErrorCode() int { return errCodeInvalidParams }
+
+type clientLimitExceededError struct{ message string }
+
+func (e *clientLimitExceededError) Error() string  { return e.message }
+func (e *clientLimitExceededError) ErrorCode() int { return errCodeClientLimitExceeded }
+
+type invalidBlockNumberError struct{ message string }
+
+func (e *invalidBlockNumberError) Error() string  { return e.message }
+func (e *invalidBlockNumberError) ErrorCode() int { return errCodeBlockNumberInvalid }
+
+type invalidBlockTimestampError struct{ message string }
+
+func (e *invalidBlockTimestampError) Error() string  { return e.message }
+func (e *invalidBlockTimestampError) ErrorCode() int { return errCodeBlockTimestampInvalid }
+
+type blockGasLimitReachedError struct{ message string }
+
+func (e *blockGasLimitReachedError) Error() string  { return e.message }
+func (e *blockGasLimitReachedError) ErrorCode() int { return errCodeBlockGasLimitReached }
@@ -0,0 +1,262 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func newInt(n int64) *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(n))
+}
+
+func TestSimulateSanitizeBlockOrder(t *testing.T) {
+	type result struct {
+		number    uint64
+		timestamp uint64
+	}
+	for i, tc := range []struct {
+		baseNumber    int
+		baseTimestamp uint64
+		blocks        []simBlock
+		expected      []result
+		err           string
+	}{
+		{
+			baseNumber:    10,
+			baseTimestamp: 50,
+			blocks:        []simBlock{{}, {}, {}},
+			expected:      []result{{number: 11, timestamp: 51}, {number: 12, timestamp: 52}, {number: 13, timestamp: 53}},
+		},
+		{
+			baseNumber:    10,
+			baseTimestamp: 50,
+			blocks:        []simBlock{{BlockOverrides: &BlockOverrides{Number: newInt(13), Time: newUint64(70)}}, {}},
+			expected:      []result{{number: 11, timestamp: 51}, {number: 12, timestamp: 52}, {number: 13, timestamp: 70}, {number: 14, timestamp: 71}},
+		},
+		{
+			baseNumber:    10,
+			baseTimestamp: 50,
+			blocks:        []simBlock{{BlockOverrides: &BlockOverrides{Number: newInt(11)}}, {BlockOverrides: &BlockOverrides{Number: newInt(14)}}, {}},
+			expected:      []result{{number: 11, timestamp: 51}, {number: 12, timestamp: 52}, {number: 13, timestamp: 53}, {number: 14, timestamp: 54}, {number: 15, timestamp: 55}},
+		},
+		{
+			baseNumber:    10,
+			baseTimestamp: 50,
+			blocks:        []simBlock{{BlockOverrides: &BlockOverrides{Number: newInt(13)}}, {BlockOverrides: &BlockOverrides{Number: newInt(12)}}},
+			err:           "block numbers must be in order: 12 <= 13",
+		},
+		{
+			baseNumber:    10,
+			baseTimestamp: 50,
+			blocks:        []simBlock{{BlockOverrides: &BlockOverrides{Number: newInt(13), Time: newUint64(52)}}},
+			err:           "block timestamps must be in order: 52 <= 52",
+		},
+		{
+			baseNumber:    10,
+			baseTimestamp: 50,
+			blocks:        []simBlock{{BlockOverrides: &BlockOverrides{Number: newInt(11), Time: newUint64(60)}}, {BlockOverrides: &BlockOverrides{Number: newInt(12), Time: newUint64(55)}}},
+			err:           "block timestamps must be in order: 55 <= 60",
+		},
+	} {
+		sim := &simulator{base: &types.Header{Number: big.NewInt(int64(tc.baseNumber)), Time: tc.baseTimestamp}}
+		res, err := sim.sanitizeChain(tc.blocks)
+		if err != nil {
+			if err.Error() == tc.err {
+				continue
+			} else {
+				t.Fatalf("testcase %d: error mismatch. Want '%s', have '%s'", i, tc.err, err.Error())
+			}
+		}
+		if err == nil && tc.err != "" {
+			t.Fatalf("testcase %d: expected err", i)
+		}
+		if len(res) != len(tc.expected) {
+			t.Errorf("testcase %d: mismatch number of blocks. Want %d, have %d", i, len(tc.expected), len(res))
+		}
+		for bi, b := range res {
+			if b.BlockOverrides == nil {
+				t.Fatalf("testcase %d: block overrides nil", i)
+			}
+			if b.BlockOverrides.Number == nil {
+				t.Fatalf("testcase %d: block number not set", i)
+			}
+			if b.BlockOverrides.Time == nil {
+				t.Fatalf("testcase %d: block time not set", i)
+			}
+			if uint64(*b.BlockOverrides.Time) != tc.expected[bi].timestamp {
+				t.Errorf("testcase %d: block timestamp mismatch. Want %d, have %d", i, tc.expected[bi].timestamp, uint64(*b.BlockOverrides.Time))
+			}
+			have := b.BlockOverrides.Number.ToInt().Uint64()
+			if have != tc.expected[bi].number {
+				t.Errorf("testcase %d: block number mismatch. Want %d, have %d", i, tc.expected[bi].number, have)
+			}
+		}
+	}
+}
+
+func newUint64(n uint64) *hexutil.Uint64 {
+	return (*hexutil.Uint64)(&n)
+}
+
+func TestSimulateV1(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(3)
+		genesis  = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: types.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		genBlocks = 5
+		value     = big.NewInt(1000)
+	)
+	api := NewBlockChainAPI(newTestBackend(t, genBlocks, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	}))
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	// The transfer of the first block funds the sender of the second one,
+	// which only works if the state is carried over.
+	results, err := api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{
+			{Calls: []TransactionArgs{{From: &accounts[0].addr, To: &accounts[1].addr, Value: (*hexutil.Big)(value)}}},
+			{Calls: []TransactionArgs{{From: &accounts[1].addr, To: &accounts[2].addr, Value: (*hexutil.Big)(value)}}},
+		},
+		TraceTransfers:         true,
+		ReturnFullTransactions: true,
+	}, &latest)
+	if err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("block count mismatch: have %d, want 2", len(results))
+	}
+	var parentHash common.Hash
+	for i, block := range results {
+		if have, want := block["number"].(*hexutil.Big).ToInt().Uint64(), uint64(genBlocks+i+1); have != want {
+			t.Errorf("block %d: number mismatch: have %d, want %d", i, have, want)
+		}
+		if i > 0 && block["parentHash"].(common.Hash) != parentHash {
+			t.Errorf("block %d: parent hash mismatch", i)
+		}
+		parentHash = block["hash"].(common.Hash)
+
+		calls := block["calls"].([]simCallResult)
+		if len(calls) != 1 {
+			t.Fatalf("block %d: call count mismatch: have %d, want 1", i, len(calls))
+		}
+		if calls[0].Status != hexutil.Uint64(types.ReceiptStatusSuccessful) {
+			t.Errorf("block %d: call failed: %v", i, calls[0].Error)
+		}
+		if uint64(calls[0].GasUsed) != params.TxGas {
+			t.Errorf("block %d: gas used mismatch: have %d, want %d", i, calls[0].GasUsed, params.TxGas)
+		}
+		// The value transfer is reported as a log
+		if len(calls[0].Logs) != 1 {
+			t.Fatalf("block %d: log count mismatch: have %d, want 1", i, len(calls[0].Logs))
+		}
+		log := calls[0].Logs[0]
+		if log.Address != transferAddress || log.Topics[0] != transferTopic || new(big.Int).SetBytes(log.Data).Cmp(value) != 0 {
+			t.Errorf("block %d: unexpected transfer log: %v", i, log)
+		}
+		if log.BlockHash != parentHash {
+			t.Errorf("block %d: log block hash mismatch", i)
+		}
+		tx := block["transactions"].([]interface{})[0].(*RPCTransaction)
+		if tx.From != accounts[i].addr {
+			t.Errorf("block %d: sender mismatch: have %v, want %v", i, tx.From, accounts[i].addr)
+		}
+	}
+
+	// Validation mode checks the nonces
+	nonce := hexutil.Uint64(1)
+	_, err = api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{
+			{Calls: []TransactionArgs{{From: &accounts[0].addr, To: &accounts[1].addr, Nonce: &nonce}}},
+		},
+		Validation: true,
+	}, &latest)
+	var txErr *invalidTxError
+	if !errors.As(err, &txErr) || txErr.Code != errCodeNonceTooHigh {
+		t.Errorf("expected nonce too high error, have %v", err)
+	}
+}
+
+func TestSimulateV1FeeCurrency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		genesis     = core.DeveloperGenesisBlock(30_000_000, nil)
+		feeCurrency = core.DevFeeCurrencyAddr
+		to          = common.HexToAddress("0xdeadbeef")
+		// balanceOf(DevAddr)
+		balanceOf = hexutil.Bytes(append(hexutil.MustDecode("0x70a08231"), common.LeftPadBytes(core.DevAddr.Bytes(), 32)...))
+	)
+	api := NewBlockChainAPI(newTestBackend(t, 0, genesis, beacon.New(ethash.NewFaker()), nil))
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	results, err := api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{
+			{Calls: []TransactionArgs{{To: &feeCurrency, Input: &balanceOf}}},
+			{Calls: []TransactionArgs{{
+				From:                 &core.DevAddr,
+				To:                   &to,
+				Value:                newInt(1),
+				MaxFeePerGas:         newInt(100 * params.GWei),
+				MaxPriorityFeePerGas: newInt(params.GWei),
+				FeeCurrency:          &feeCurrency,
+			}}},
+			{Calls: []TransactionArgs{{To: &feeCurrency, Input: &balanceOf}}},
+		},
+	}, &latest)
+	if err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	calls := make([]simCallResult, len(results))
+	for i, block := range results {
+		calls[i] = block["calls"].([]simCallResult)[0]
+		if calls[i].Status != hexutil.Uint64(types.ReceiptStatusSuccessful) {
+			t.Fatalf("block %d: call failed: %v", i, calls[i].Error)
+		}
+	}
+	// The fees of the fee currency transaction are debited from the sender's
+	// fee currency balance. Without validation the base fee is zero, so only
+	// the tip is paid.
+	before := new(big.Int).SetBytes(calls[0].ReturnValue)
+	after := new(big.Int).SetBytes(calls[2].ReturnValue)
+	if after.Cmp(before) >= 0 {
+		t.Errorf("fee currency balance not debited: before %d, after %d", before, after)
+	}
+	if uint64(calls[1].GasUsed) <= params.TxGas {
+		t.Errorf("fee currency intrinsic gas not charged: gas used %d", calls[1].GasUsed)
+	}
+}
@@ -0,0 +1,117 @@
+package sequencerapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// MaxConditionalCost is the maximum cost of the preconditions of a single
+	// conditional transaction.
+	MaxConditionalCost = 1000
+
+	// conditionalBurstFactor is the number of maximum cost requests which can
+	// be served in a burst, allowing conditional transactions to queue up.
+	conditionalBurstFactor = 3
+
+	errCodeConditionalRejected     = -32003 // Transaction rejected
+	errCodeConditionalCostExceeded = -32005 // Limit exceeded
+)
+
+var (
+	sendRawTxConditionalRequestsCounter = metrics.NewRegisteredCounter("sequencer/sendRawTransactionConditional/requests", nil)
+	sendRawTxConditionalAcceptedCounter = metrics.NewRegisteredCounter("sequencer/sendRawTransactionConditional/accepted", nil)
+	sendRawTxConditionalCostMeter       = metrics.NewRegisteredMeter("sequencer/sendRawTransactionConditional/cost", nil)
+)
+
+// conditionalError is an RPC error rejecting a conditional transaction.
+type conditionalError struct {
+	code    int
+	message string
+}
+
+func (e *conditionalError) Error() string  { return e.message }
+func (e *conditionalError) ErrorCode() int { return e.code }
+
+// ConditionalAPI offers the submission of transactions with preconditions for
+// their inclusion.
+type ConditionalAPI struct {
+	b       ethapi.Backend
+	seqRPC  *rpc.Client // Sequencer to forward transactions to, nil on the sequencer itself
+	limiter *rate.Limiter
+}
+
+// NewConditionalAPI creates a new conditional transaction API. Transactions are
+// forwarded to the sequencer if seqRPC is set, and added to the local pool
+// otherwise. The cost of the preconditions is limited to costRateLimit per
+// second.
+func NewConditionalAPI(b ethapi.Backend, seqRPC *rpc.Client, costRateLimit rate.Limit) *ConditionalAPI {
+	return &ConditionalAPI{
+		b:       b,
+		seqRPC:  seqRPC,
+		limiter: rate.NewLimiter(costRateLimit, conditionalBurstFactor*MaxConditionalCost),
+	}
+}
+
+// SendRawTransactionConditional submits a signed transaction, which is only
+// included in a block if the preconditions hold. The preconditions are checked
+// against the latest block on submission, and against the block being built
+// before inclusion.
+func (api *ConditionalAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, cond types.TransactionConditional) (common.Hash, error) {
+	sendRawTxConditionalRequestsCounter.Inc(1)
+
+	cost := cond.Cost()
+	sendRawTxConditionalCostMeter.Mark(int64(cost))
+	if cost > MaxConditionalCost {
+		return common.Hash{}, &conditionalError{errCodeConditionalCostExceeded, fmt.Sprintf("conditional cost %d exceeds maximum %d", cost, MaxConditionalCost)}
+	}
+	if err := cond.Validate(); err != nil {
+		return common.Hash{}, &conditionalError{errCodeConditionalRejected, fmt.Sprintf("invalid conditional: %v", err)}
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	// Check the preconditions before doing any further work
+	state, header, err := api.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := header.CheckTransactionConditional(&cond); err != nil {
+		return common.Hash{}, &conditionalError{errCodeConditionalRejected, fmt.Sprintf("failed header check: %v", err)}
+	}
+	if err := state.CheckTransactionConditional(&cond); err != nil {
+		return common.Hash{}, &conditionalError{errCodeConditionalRejected, fmt.Sprintf("failed state check: %v", err)}
+	}
+	if err := api.limiter.WaitN(ctx, cost); err != nil {
+		return common.Hash{}, &conditionalError{errCodeConditionalCostExceeded, fmt.Sprintf("conditional cost %d rate limited", cost)}
+	}
+	if api.seqRPC != nil {
+		var hash common.Hash
+		if err := api.seqRPC.CallContext(ctx, &hash, "eth_sendRawTransactionConditional", input, cond); err != nil {
+			return common.Hash{}, err
+		}
+		log.Debug("Forwarded conditional transaction to sequencer", "hash", hash)
+		sendRawTxConditionalAcceptedCounter.Inc(1)
+		return hash, nil
+	}
+	tx.SetTime(time.Now())
+	tx.SetConditional(&cond)
+
+	hash, err := ethapi.SubmitTransaction(ctx, api.b, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	sendRawTxConditionalAcceptedCounter.Inc(1)
+	return hash, nil
+}
@@ -0,0 +1,38 @@
+package miner
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// reservedGasGaugeName tracks the gas used by a fee currency's transactions
+	// included through its reservation in the last built block.
+	reservedGasGaugeName = "miner/feecurrency/%s/reserved/gas"
+	// reservedTxsCounterName counts the transactions of a fee currency included
+	// through its reservation.
+	reservedTxsCounterName = "miner/feecurrency/%s/reserved/txs"
+	// exhaustedCounterName counts the built blocks in which transactions of a
+	// fee currency were left out, because its MultiGasPool limit was reached.
+	exhaustedCounterName = "miner/feecurrency/%s/exhausted"
+)
+
+// feeCurrencyMetricName returns the name of a fee currency metric. The number
+// of currencies with their own metrics is capped, see core.FeeCurrencyMetricKey.
+func feeCurrencyMetricName(name string, currency common.Address) string {
+	return fmt.Sprintf(name, core.FeeCurrencyMetricKey(currency))
+}
+
+// reportExhaustedFeeCurrencies updates the exhaustion metrics with the fee
+// currencies whose block gas ran out while filling the block.
+func reportExhaustedFeeCurrencies(env *environment) {
+	if !metrics.Enabled {
+		return
+	}
+	for currency := range env.feeCurrencyExhausted {
+		metrics.GetOrRegisterCounter(feeCurrencyMetricName(exhaustedCounterName, currency), nil).Inc(1)
+	}
+}
@@ -0,0 +1,273 @@
+package miner
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	minutes uint64 = 60
+	hours   uint64 = 60 * minutes
+)
+
+// FeeCurrencyOutcome is the outcome of executing a transaction paying for gas
+// in a fee currency, as far as the fee currency is concerned.
+type FeeCurrencyOutcome int
+
+const (
+	// FeeCurrencySuccess means the fees were debited and credited within
+	// the intrinsic gas of the fee currency.
+	FeeCurrencySuccess FeeCurrencyOutcome = iota
+	// FeeCurrencyOverrun means the fees were debited and credited, but the
+	// calls used more gas than the intrinsic gas of the fee currency.
+	FeeCurrencyOverrun
+	// FeeCurrencyFailure means the debit or credit call failed.
+	FeeCurrencyFailure
+)
+
+// FeeCurrencyHealthConfig are the parameters for scoring the health of fee
+// currencies and temporarily blocking unhealthy ones.
+type FeeCurrencyHealthConfig struct {
+	Window           uint64  // Sliding window (in seconds of block time) over which outcomes are scored
+	MinSamples       uint64  // Minimum number of outcomes in the window before a fee currency can be blocked
+	BlockThreshold   float64 // Score above which a fee currency is blocked
+	RecoverThreshold float64 // Score below which a fee currency is considered recovered
+	OverrunWeight    float64 // Weight of a gas overrun in the score, relative to a failure
+	BlockTimeout     uint64  // Time (in seconds) a fee currency is blocked for the first time
+	MaxBlockTimeout  uint64  // Maximum time (in seconds) a fee currency is blocked
+}
+
+// DefaultFeeCurrencyHealthConfig contains the default fee currency health
+// scoring parameters.
+var DefaultFeeCurrencyHealthConfig = FeeCurrencyHealthConfig{
+	Window:           10 * minutes,
+	MinSamples:       1,
+	BlockThreshold:   0.5,
+	RecoverThreshold: 0.1,
+	OverrunWeight:    0.25,
+	BlockTimeout:     1 * minutes,
+	MaxBlockTimeout:  2 * hours,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *FeeCurrencyHealthConfig) sanitize() FeeCurrencyHealthConfig {
+	conf := *config
+	if conf.Window == 0 {
+		log.Warn("Sanitizing invalid fee currency health window", "provided", conf.Window, "updated", DefaultFeeCurrencyHealthConfig.Window)
+		conf.Window = DefaultFeeCurrencyHealthConfig.Window
+	}
+	if conf.MinSamples == 0 {
+		log.Warn("Sanitizing invalid fee currency health min samples", "provided", conf.MinSamples, "updated", DefaultFeeCurrencyHealthConfig.MinSamples)
+		conf.MinSamples = DefaultFeeCurrencyHealthConfig.MinSamples
+	}
+	if conf.BlockThreshold <= 0 || conf.BlockThreshold > 1 {
+		log.Warn("Sanitizing invalid fee currency health block threshold", "provided", conf.BlockThreshold, "updated", DefaultFeeCurrencyHealthConfig.BlockThreshold)
+		conf.BlockThreshold = DefaultFeeCurrencyHealthConfig.BlockThreshold
+	}
+	if conf.RecoverThreshold < 0 || conf.RecoverThreshold > conf.BlockThreshold {
+		log.Warn("Sanitizing invalid fee currency health recover threshold", "provided", conf.RecoverThreshold, "updated", conf.BlockThreshold)
+		conf.RecoverThreshold = conf.BlockThreshold
+	}
+	if conf.OverrunWeight < 0 || conf.OverrunWeight > 1 {
+		log.Warn("Sanitizing invalid fee currency health overrun weight", "provided", conf.OverrunWeight, "updated", DefaultFeeCurrencyHealthConfig.OverrunWeight)
+		conf.OverrunWeight = DefaultFeeCurrencyHealthConfig.OverrunWeight
+	}
+	if conf.BlockTimeout == 0 {
+		log.Warn("Sanitizing invalid fee currency health block timeout", "provided", conf.BlockTimeout, "updated", DefaultFeeCurrencyHealthConfig.BlockTimeout)
+		conf.BlockTimeout = DefaultFeeCurrencyHealthConfig.BlockTimeout
+	}
+	if conf.MaxBlockTimeout < conf.BlockTimeout {
+		log.Warn("Sanitizing invalid fee currency health max block timeout", "provided", conf.MaxBlockTimeout, "updated", conf.BlockTimeout)
+		conf.MaxBlockTimeout = conf.BlockTimeout
+	}
+	return conf
+}
+
+// feeCurrencyBucket counts the outcomes of a fee currency within one block.
+type feeCurrencyBucket struct {
+	time      uint64
+	successes uint64
+	overruns  uint64
+	failures  uint64
+}
+
+// feeCurrencyScore tracks the recent outcomes and the blocking state of a
+// single fee currency.
+type feeCurrencyScore struct {
+	buckets      []feeCurrencyBucket // Outcomes within the window, ordered by time
+	blockedUntil uint64              // Time until which the fee currency is blocked, 0 if not blocked
+	backoff      uint                // Number of consecutive blocks without recovering in between
+}
+
+// FeeCurrencyHealth scores fee currencies by the outcomes of their debit and
+// credit calls over a sliding window, and temporarily blocks the ones with a
+// bad score from block building. Repeatedly blocked fee currencies are blocked
+// for exponentially longer periods, until they recover.
+//
+// Blocking is local to the sequencer and not consensus critical. All times are
+// block timestamps.
+type FeeCurrencyHealth struct {
+	mux        sync.RWMutex
+	config     FeeCurrencyHealthConfig
+	currencies map[common.Address]*feeCurrencyScore
+}
+
+// NewFeeCurrencyHealth creates a fee currency health tracker.
+func NewFeeCurrencyHealth(config FeeCurrencyHealthConfig) *FeeCurrencyHealth {
+	return &FeeCurrencyHealth{
+		config:     config.sanitize(),
+		currencies: make(map[common.Address]*feeCurrencyScore),
+	}
+}
+
+// Record adds the outcome of a transaction executed at the given time to the
+// score of the fee currency. It returns whether the fee currency is blocked
+// as a result.
+func (h *FeeCurrencyHealth) Record(currency common.Address, time uint64, outcome FeeCurrencyOutcome) bool {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	s, ok := h.currencies[currency]
+	if !ok {
+		s = new(feeCurrencyScore)
+		h.currencies[currency] = s
+	}
+	if s.blockedUntil > time {
+		return true
+	}
+	h.prune(s, time)
+
+	if n := len(s.buckets); n == 0 || s.buckets[n-1].time != time {
+		s.buckets = append(s.buckets, feeCurrencyBucket{time: time})
+	}
+	bucket := &s.buckets[len(s.buckets)-1]
+	switch outcome {
+	case FeeCurrencySuccess:
+		bucket.successes++
+	case FeeCurrencyOverrun:
+		bucket.overruns++
+	case FeeCurrencyFailure:
+		bucket.failures++
+	}
+	score, samples := h.score(s)
+	if samples < h.config.MinSamples {
+		return false
+	}
+	if score > h.config.BlockThreshold {
+		timeout := h.config.BlockTimeout
+		for i := uint(0); i < s.backoff && timeout < h.config.MaxBlockTimeout; i++ {
+			timeout *= 2
+		}
+		s.blockedUntil = time + min(timeout, h.config.MaxBlockTimeout)
+		s.backoff++
+		// Give the fee currency a clean slate once it's unblocked
+		s.buckets = nil
+		return true
+	}
+	if score < h.config.RecoverThreshold {
+		s.backoff = 0
+	}
+	return false
+}
+
+// IsBlocked returns whether the fee currency is blocked at the given time.
+func (h *FeeCurrencyHealth) IsBlocked(currency common.Address, time uint64) bool {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	s, ok := h.currencies[currency]
+	return ok && s.blockedUntil > time
+}
+
+// Blocked returns the fee currencies which are blocked at the given time.
+func (h *FeeCurrencyHealth) Blocked(time uint64) []common.Address {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	blocked := []common.Address{}
+	for currency, s := range h.currencies {
+		if s.blockedUntil > time {
+			blocked = append(blocked, currency)
+		}
+	}
+	slices.SortFunc(blocked, common.Address.Cmp)
+	return blocked
+}
+
+// Score returns the current score of the fee currency between 0 (healthy) and
+// 1 (unhealthy), and the number of outcomes it is based on.
+func (h *FeeCurrencyHealth) Score(currency common.Address, time uint64) (float64, uint64) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	s, ok := h.currencies[currency]
+	if !ok {
+		return 0, 0
+	}
+	h.prune(s, time)
+	return h.score(s)
+}
+
+// FilterAllowlist returns the fee currencies of the allowlist which are not
+// blocked at the given time.
+func (h *FeeCurrencyHealth) FilterAllowlist(allowlist common.AddressSet, time uint64) common.AddressSet {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	filtered := common.AddressSet{}
+	for a := range allowlist {
+		if s, ok := h.currencies[a]; !ok || s.blockedUntil <= time {
+			filtered[a] = struct{}{}
+		}
+	}
+	return filtered
+}
+
+// Unblock lifts the blocks which expired at the given time and returns the
+// affected fee currencies. Fee currencies without any recent outcomes are
+// forgotten.
+func (h *FeeCurrencyHealth) Unblock(time uint64) []common.Address {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	unblocked := []common.Address{}
+	for currency, s := range h.currencies {
+		if s.blockedUntil != 0 && s.blockedUntil <= time {
+			s.blockedUntil = 0
+			unblocked = append(unblocked, currency)
+		}
+		h.prune(s, time)
+		if s.blockedUntil == 0 && s.backoff == 0 && len(s.buckets) == 0 {
+			delete(h.currencies, currency)
+		}
+	}
+	return unblocked
+}
+
+// prune drops the outcomes which are no longer within the window.
+func (h *FeeCurrencyHealth) prune(s *feeCurrencyScore, time uint64) {
+	var i int
+	for i < len(s.buckets) && s.buckets[i].time+h.config.Window <= time {
+		i++
+	}
+	s.buckets = s.buckets[i:]
+}
+
+// score calculates the weighted failure rate over the buckets in the window.
+func (h *FeeCurrencyHealth) score(s *feeCurrencyScore) (float64, uint64) {
+	var successes, overruns, failures uint64
+	for _, b := range s.buckets {
+		successes += b.successes
+		overruns += b.overruns
+		failures += b.failures
+	}
+	samples := successes + overruns + failures
+	if samples == 0 {
+		return 0, 0
+	}
+	return (float64(failures) + h.config.OverrunWeight*float64(overruns)) / float64(samples), samples
+}
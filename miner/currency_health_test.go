@@ -0,0 +1,125 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	feeCurrency1 = common.BigToAddress(big.NewInt(1))
+	feeCurrency2 = common.BigToAddress(big.NewInt(2))
+	now          = uint64(1111111111111)
+)
+
+func testHealthConfig() FeeCurrencyHealthConfig {
+	return FeeCurrencyHealthConfig{
+		Window:           100,
+		MinSamples:       4,
+		BlockThreshold:   0.5,
+		RecoverThreshold: 0.1,
+		OverrunWeight:    0.5,
+		BlockTimeout:     10,
+		MaxBlockTimeout:  35,
+	}
+}
+
+func TestHealthSingleFailure(t *testing.T) {
+	h := NewFeeCurrencyHealth(testHealthConfig())
+	for i := 0; i < 10; i++ {
+		assert.False(t, h.Record(feeCurrency1, now, FeeCurrencySuccess))
+	}
+	// a single failure among many successes doesn't block the currency
+	assert.False(t, h.Record(feeCurrency1, now+1, FeeCurrencyFailure))
+	assert.False(t, h.IsBlocked(feeCurrency1, now+1))
+
+	score, samples := h.Score(feeCurrency1, now+1)
+	assert.Equal(t, uint64(11), samples)
+	assert.InDelta(t, 1.0/11, score, 1e-9)
+}
+
+func TestHealthMinSamples(t *testing.T) {
+	h := NewFeeCurrencyHealth(testHealthConfig())
+	for i := 0; i < 3; i++ {
+		assert.False(t, h.Record(feeCurrency1, now, FeeCurrencyFailure))
+	}
+	assert.True(t, h.Record(feeCurrency1, now, FeeCurrencyFailure))
+	assert.True(t, h.IsBlocked(feeCurrency1, now))
+	assert.False(t, h.IsBlocked(feeCurrency2, now))
+
+	filtered := h.FilterAllowlist(common.NewAddressSet(feeCurrency1, feeCurrency2), now+1)
+	assert.Equal(t, common.NewAddressSet(feeCurrency2), filtered)
+	assert.Equal(t, []common.Address{feeCurrency1}, h.Blocked(now+1))
+	assert.Empty(t, h.Blocked(now+10))
+}
+
+func TestHealthOverruns(t *testing.T) {
+	h := NewFeeCurrencyHealth(testHealthConfig())
+	// overruns only count partially, so they alone don't exceed the threshold
+	for i := 0; i < 10; i++ {
+		assert.False(t, h.Record(feeCurrency1, now, FeeCurrencyOverrun))
+	}
+	score, _ := h.Score(feeCurrency1, now)
+	assert.InDelta(t, 0.5, score, 1e-9)
+
+	// but in combination with failures they do
+	assert.True(t, h.Record(feeCurrency1, now, FeeCurrencyFailure))
+}
+
+func TestHealthWindow(t *testing.T) {
+	config := testHealthConfig()
+	h := NewFeeCurrencyHealth(config)
+	for i := 0; i < 3; i++ {
+		assert.False(t, h.Record(feeCurrency1, now, FeeCurrencyFailure))
+	}
+	// the failures drop out of the window
+	assert.False(t, h.Record(feeCurrency1, now+config.Window, FeeCurrencyFailure))
+	_, samples := h.Score(feeCurrency1, now+config.Window)
+	assert.Equal(t, uint64(1), samples)
+
+	// and the currency is forgotten once it has no recent outcomes
+	assert.Empty(t, h.Unblock(now+2*config.Window))
+	assert.Empty(t, h.currencies)
+}
+
+func TestHealthBackoff(t *testing.T) {
+	config := testHealthConfig()
+	h := NewFeeCurrencyHealth(config)
+
+	block := func(time uint64) {
+		t.Helper()
+		for i := 0; i < 10 && !h.Record(feeCurrency1, time, FeeCurrencyFailure); i++ {
+		}
+		assert.True(t, h.IsBlocked(feeCurrency1, time))
+	}
+	// each consecutive block doubles the timeout, up to the maximum
+	time := now
+	for _, timeout := range []uint64{10, 20, 35, 35} {
+		block(time)
+		assert.True(t, h.IsBlocked(feeCurrency1, time+timeout-1))
+		assert.Empty(t, h.Unblock(time+timeout-1))
+		assert.Equal(t, []common.Address{feeCurrency1}, h.Unblock(time+timeout))
+		assert.False(t, h.IsBlocked(feeCurrency1, time+timeout))
+		time += timeout
+	}
+	// recovering resets the backoff
+	for i := uint64(0); i < config.MinSamples; i++ {
+		assert.False(t, h.Record(feeCurrency1, time, FeeCurrencySuccess))
+	}
+	block(time + 1)
+	assert.False(t, h.IsBlocked(feeCurrency1, time+1+10))
+}
+
+func TestHealthSanitize(t *testing.T) {
+	h := NewFeeCurrencyHealth(FeeCurrencyHealthConfig{})
+	assert.Equal(t, DefaultFeeCurrencyHealthConfig.Window, h.config.Window)
+	assert.Equal(t, DefaultFeeCurrencyHealthConfig.MinSamples, h.config.MinSamples)
+	assert.Equal(t, DefaultFeeCurrencyHealthConfig.BlockThreshold, h.config.BlockThreshold)
+	assert.Equal(t, DefaultFeeCurrencyHealthConfig.BlockTimeout, h.config.BlockTimeout)
+	assert.Equal(t, h.config.BlockTimeout, h.config.MaxBlockTimeout)
+
+	// a single success must not block the currency with an empty config
+	assert.False(t, h.Record(feeCurrency1, now, FeeCurrencySuccess))
+}
@@ -0,0 +1,149 @@
+package miner
+
+import (
+	"slices"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// feeCurrencyReservations returns the amount of block gas reserved for each
+// allowlisted fee currency, or nil if no gas is reserved. If the reserved
+// fractions add up to more than the whole block, they are scaled down
+// proportionally so that every currency gets its fair share.
+func (miner *Miner) feeCurrencyReservations(env *environment) map[common.Address]uint64 {
+	miner.confMu.RLock()
+	defer miner.confMu.RUnlock()
+
+	var (
+		shares map[common.Address]float64
+		total  float64
+	)
+	for currency := range env.feeCurrencyAllowlist {
+		share, ok := miner.config.FeeCurrencyReserved[currency]
+		if !ok {
+			share = miner.config.FeeCurrencyReservedDefault
+		}
+		if share <= 0 {
+			continue
+		}
+		if shares == nil {
+			shares = make(map[common.Address]float64)
+		}
+		shares[currency] = min(share, 1)
+		total += min(share, 1)
+	}
+	if shares == nil {
+		return nil
+	}
+	scale := 1.0
+	if total > 1 {
+		scale = 1 / total
+	}
+	reservations := make(map[common.Address]uint64, len(shares))
+	for currency, share := range shares {
+		reservations[currency] = uint64(float64(env.header.GasLimit) * share * scale)
+	}
+	return reservations
+}
+
+// commitReservedTransactions fills the reserved block gas of each fee currency
+// with its pending transactions, ordered by price. Only the transactions of an
+// account up to the first one paying with another currency are considered, to
+// keep the nonce order. Included transactions are removed from pending, and
+// the used gas from the reservations.
+//
+// The reservation of a fee currency is capped by its MultiGasPool limit. Any
+// reserved gas that's not used remains available to all transactions.
+func (miner *Miner) commitReservedTransactions(env *environment, pending map[common.Address][]*txpool.LazyTransaction, reservations map[common.Address]uint64, interrupt *atomic.Int32) error {
+	currencies := make([]common.Address, 0, len(reservations))
+	for currency := range reservations {
+		currencies = append(currencies, currency)
+	}
+	slices.SortFunc(currencies, common.Address.Cmp)
+
+	// Reserved gas is summed up per metric, as currencies beyond the cap on
+	// the number of metrics share theirs
+	reservedGas := make(map[string]int64)
+	defer func() {
+		for name, used := range reservedGas {
+			metrics.GetOrRegisterGauge(name, nil).Update(used)
+		}
+	}()
+	for _, currency := range currencies {
+		reserved := reservations[currency]
+		if reserved == 0 {
+			continue
+		}
+		txs := make(map[common.Address][]*txpool.LazyTransaction)
+		for from, accTxs := range pending {
+			var n int
+			for n < len(accTxs) && accTxs[n].FeeCurrency != nil && *accTxs[n].FeeCurrency == currency {
+				n++
+			}
+			if n > 0 {
+				txs[from] = accTxs[:n]
+			}
+		}
+		if len(txs) == 0 {
+			continue
+		}
+		// Temporarily limit the currency's pool to the reservation
+		var (
+			pool      = env.multiGasPool.PoolFor(&currency)
+			available = pool.Gas()
+			limit     = min(available, reserved)
+			included  = len(env.txs)
+		)
+		pool.SetGas(limit)
+		err := miner.commitTransactions(env,
+			newTransactionsByPriceAndNonce(env.signer, txs, env.header.BaseFee, env.exchangeRates),
+			newTransactionsByPriceAndNonce(env.signer, nil, env.header.BaseFee, env.exchangeRates),
+			interrupt,
+		)
+		// The pool is emptied if the currency gets blocked, so the used gas
+		// is taken from the receipts.
+		var used uint64
+		for _, receipt := range env.receipts[included:] {
+			used += receipt.GasUsed
+		}
+		if !miner.feeCurrencyHealth.IsBlocked(currency, env.header.Time) {
+			pool.SetGas(available - used)
+		}
+		reservations[currency] = reserved - used
+
+		// Running out of reserved gas doesn't exhaust the currency's block gas
+		delete(env.feeCurrencyExhausted, currency)
+
+		if metrics.Enabled {
+			reservedGas[feeCurrencyMetricName(reservedGasGaugeName, currency)] += int64(used)
+			metrics.GetOrRegisterCounter(feeCurrencyMetricName(reservedTxsCounterName, currency), nil).Inc(int64(len(env.txs) - included))
+		}
+
+		// Drop the included transactions from the pending set
+		hashes := make(map[common.Hash]struct{}, len(env.txs)-included)
+		for _, tx := range env.txs[included:] {
+			hashes[tx.Hash()] = struct{}{}
+		}
+		for from := range txs {
+			accTxs := pending[from]
+			for len(accTxs) > 0 {
+				if _, ok := hashes[accTxs[0].Hash]; !ok {
+					break
+				}
+				accTxs = accTxs[1:]
+			}
+			if len(accTxs) == 0 {
+				delete(pending, from)
+			} else {
+				pending[from] = accTxs
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
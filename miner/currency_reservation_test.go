@@ -0,0 +1,131 @@
+package miner
+
+import (
+	"maps"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// devKey is the key of core.DevAddr, which holds balances in the dev fee currencies.
+var devKey, _ = crypto.HexToECDSA("2771aff413cac48d9f8c114fabddd9195a2129f3c2c436caa07e27bb7f58ead5")
+
+func TestReservedFeeCurrencyGas(t *testing.T) {
+	t.Run("no-reservation", func(t *testing.T) { testReservedFeeCurrencyGas(t, 0, 0) })
+	t.Run("reservation", func(t *testing.T) { testReservedFeeCurrencyGas(t, 0.3, 3) })
+}
+
+func testReservedFeeCurrencyGas(t *testing.T, reserved float64, wantFeeCurrencyTxs int) {
+	t.Parallel()
+
+	gspec := core.DeveloperGenesisBlock(1_000_000, &testBankAddress)
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("core.NewBlockChain failed: %v", err)
+	}
+	defer chain.Stop()
+
+	pool := legacypool.New(testTxPoolConfig, chain)
+	txpool, _ := txpool.New(testTxPoolConfig.PriceLimit, chain, []txpool.SubPool{pool})
+	defer txpool.Close()
+
+	// Fill the pool with more well paying CELO transactions than fit into the
+	// block, and a few badly paying fee currency transactions.
+	var (
+		signer   = types.LatestSigner(gspec.Config)
+		to       = common.HexToAddress("0xdeadbeef")
+		currency = core.DevFeeCurrencyAddr2
+		txs      []*types.Transaction
+	)
+	for nonce := uint64(0); nonce < 60; nonce++ {
+		txs = append(txs, types.MustSignNewTx(testBankKey, signer, &types.DynamicFeeTx{
+			ChainID:   gspec.Config.ChainID,
+			Nonce:     nonce,
+			To:        &to,
+			Gas:       params.TxGas,
+			GasFeeCap: big.NewInt(100 * params.GWei),
+			GasTipCap: big.NewInt(params.GWei),
+		}))
+	}
+	for nonce := uint64(0); nonce < 5; nonce++ {
+		txs = append(txs, types.MustSignNewTx(devKey, signer, &types.CeloDynamicFeeTxV2{
+			ChainID:     gspec.Config.ChainID,
+			Nonce:       nonce,
+			To:          &to,
+			Gas:         100_000,
+			GasFeeCap:   big.NewInt(100 * params.GWei),
+			GasTipCap:   big.NewInt(1),
+			FeeCurrency: &currency,
+		}))
+	}
+	for i, err := range txpool.Add(txs, true, true) {
+		if err != nil {
+			t.Fatalf("failed to add tx %d: %v", i, err)
+		}
+	}
+
+	config := testConfig
+	config.FeeCurrencyDefault = DefaultFeeCurrencyLimit
+	config.FeeCurrencyReservedDefault = reserved
+	miner := New(&testWorkerBackend{chain: chain, txPool: txpool}, config, ethash.NewFaker())
+
+	result := miner.generateWork(&generateParams{
+		parentHash:  chain.CurrentBlock().Hash(),
+		timestamp:   chain.CurrentBlock().Time + 1,
+		withdrawals: types.Withdrawals{},
+		beaconRoot:  &common.Hash{},
+	})
+	if result.err != nil {
+		t.Fatalf("failed to generate block: %v", result.err)
+	}
+	var feeCurrencyTxs int
+	for _, tx := range result.block.Transactions() {
+		if tx.FeeCurrency() != nil {
+			feeCurrencyTxs++
+		}
+	}
+	if feeCurrencyTxs != wantFeeCurrencyTxs {
+		t.Errorf("fee currency txs mismatch: have %d, want %d", feeCurrencyTxs, wantFeeCurrencyTxs)
+	}
+	// The remaining gas must still be filled by CELO transactions
+	if left := result.block.GasLimit() - result.block.GasUsed(); left >= params.TxGas {
+		t.Errorf("block not filled: %d gas left", left)
+	}
+}
+
+func TestFeeCurrencyReservations(t *testing.T) {
+	var (
+		currency1 = common.HexToAddress("0xfee1")
+		currency2 = common.HexToAddress("0xfee2")
+		currency3 = common.HexToAddress("0xfee3")
+	)
+	miner := &Miner{config: &Config{
+		FeeCurrencyReservedDefault: 0.4,
+		FeeCurrencyReserved:        map[common.Address]float64{currency2: 0.6},
+	}}
+	env := &environment{
+		header:               &types.Header{GasLimit: 1_000_000},
+		feeCurrencyAllowlist: common.NewAddressSet(currency1, currency2),
+	}
+	want := map[common.Address]uint64{currency1: 400_000, currency2: 600_000}
+	if have := miner.feeCurrencyReservations(env); !maps.Equal(have, want) {
+		t.Errorf("reservations mismatch: have %v, want %v", have, want)
+	}
+	// Overcommitted reservations are scaled down proportionally
+	env.feeCurrencyAllowlist = common.NewAddressSet(currency1, currency2, currency3)
+	miner.config.FeeCurrencyReserved[currency3] = 1
+	want = map[common.Address]uint64{currency1: 200_000, currency2: 300_000, currency3: 500_000}
+	if have := miner.feeCurrencyReservations(env); !maps.Equal(have, want) {
+		t.Errorf("reservations mismatch: have %v, want %v", have, want)
+	}
+}
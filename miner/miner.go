@@ -61,6 +61,10 @@ type Config struct {
 	// Celo:
 	FeeCurrencyDefault float64                    // Default fraction of block gas limit
 	FeeCurrencyLimits  map[common.Address]float64 // Fee currency-to-limit fraction mapping
+	FeeCurrencyHealth  FeeCurrencyHealthConfig    // Parameters for temporarily blocking unhealthy fee currencies
+
+	FeeCurrencyReservedDefault float64                    // Default fraction of block gas limit reserved for each fee currency with pending TXs (0 = none)
+	FeeCurrencyReserved        map[common.Address]float64 // Fee currency-to-reserved fraction mapping
 }
 
 // DefaultConfig contains default settings for miner.
@@ -75,6 +79,7 @@ var DefaultConfig = Config{
 	Recommit: 2 * time.Second,
 
 	FeeCurrencyDefault: DefaultFeeCurrencyLimit,
+	FeeCurrencyHealth:  DefaultFeeCurrencyHealthConfig,
 }
 
 // Miner is the main object which takes care of submitting new work to consensus
@@ -91,7 +96,7 @@ type Miner struct {
 
 	backend Backend
 
-	feeCurrencyBlocklist *AddressBlocklist
+	feeCurrencyHealth *FeeCurrencyHealth
 }
 
 // New creates a new miner with provided config.
@@ -105,7 +110,7 @@ func New(eth Backend, config Config, engine consensus.Engine) *Miner {
 		chain:       eth.BlockChain(),
 		pending:     &pending{},
 
-		feeCurrencyBlocklist: NewAddressBlocklist(),
+		feeCurrencyHealth: NewFeeCurrencyHealth(config.FeeCurrencyHealth),
 	}
 }
 
@@ -160,6 +165,12 @@ func (miner *Miner) SetGasTip(tip *big.Int) error {
 	return nil
 }
 
+// BlockedFeeCurrencies returns the fee currencies which are temporarily
+// excluded from block building, because they are unhealthy.
+func (miner *Miner) BlockedFeeCurrencies() []common.Address {
+	return miner.feeCurrencyHealth.Blocked(miner.chain.CurrentBlock().Time)
+}
+
 // BuildPayload builds the payload according to the provided parameters.
 func (miner *Miner) BuildPayload(args *BuildPayloadArgs) (*Payload, error) {
 	return miner.buildPayload(args)
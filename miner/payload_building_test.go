@@ -233,6 +233,46 @@ func testBuildPayload(t *testing.T, noTxPool, interrupt bool) {
 	}
 }
 
+// Tests that conditional transactions are only included if their preconditions
+// hold for the block being built.
+func TestBuildPayloadConditional(t *testing.T) {
+	t.Parallel()
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		signer = types.LatestSigner(params.TestChainConfig)
+	)
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), db, 0)
+
+	// The conditional holds at the head, but not for the next block
+	tx := types.MustSignNewTx(testBankKey, signer, &types.AccessListTx{
+		ChainID:  params.TestChainConfig.ChainID,
+		Nonce:    1,
+		To:       &testUserAddress,
+		Value:    big.NewInt(1000),
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(params.InitialBaseFee),
+	})
+	tx.SetConditional(&types.TransactionConditional{BlockNumberMax: big.NewInt(0)})
+	if err := b.txPool.Add([]*types.Transaction{tx}, true, true)[0]; err != nil {
+		t.Fatalf("Failed to add conditional transaction: %v", err)
+	}
+	payload, err := w.buildPayload(&BuildPayloadArgs{
+		Parent:    b.chain.CurrentBlock().Hash(),
+		Timestamp: uint64(time.Now().Unix()),
+	})
+	if err != nil {
+		t.Fatalf("Failed to build payload %v", err)
+	}
+	payload.WaitFull()
+	full := payload.ResolveFull()
+	if txs := len(full.ExecutionPayload.Transactions); txs != len(pendingTxs) {
+		t.Fatalf("Unexpect transaction set: got %d, expected %d", txs, len(pendingTxs))
+	}
+	if !tx.Rejected() {
+		t.Fatal("Conditional transaction not rejected")
+	}
+}
+
 func genTxs(startNonce, count uint64) types.Transactions {
 	txs := make(types.Transactions, 0, count)
 	signer := types.LatestSigner(params.TestChainConfig)
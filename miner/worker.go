@@ -31,11 +31,13 @@ import (
 	"github.com/ethereum/go-ethereum/contracts"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
 )
@@ -51,6 +53,9 @@ var (
 	errBlockInterruptedByRecommit = errors.New("recommit interrupt while building block")
 	errBlockInterruptedByTimeout  = errors.New("timeout while building block")
 	errBlockInterruptedByResolve  = errors.New("payload resolution while building block")
+
+	txConditionalRejectedCounter = metrics.NewRegisteredCounter("miner/transactionConditional/rejected", nil)
+	txConditionalMinedCounter    = metrics.NewRegisteredCounter("miner/transactionConditional/mined", nil)
 )
 
 // environment is the worker's current environment and holds all
@@ -62,6 +67,7 @@ type environment struct {
 	gasPool              *core.GasPool      // available gas used to pack transactions
 	multiGasPool         *core.MultiGasPool // available per-fee-currency gas used to pack transactions
 	feeCurrencyAllowlist common.AddressSet
+	feeCurrencyExhausted common.AddressSet // fee currencies whose block gas ran out while filling the block
 	exchangeRates        common.ExchangeRates
 	coinbase             common.Address
 
@@ -263,16 +269,15 @@ func (miner *Miner) prepareWork(genParams *generateParams) (*environment, error)
 		return nil, err
 	}
 	context := core.NewEVMBlockContext(header, miner.chain, nil, miner.chainConfig, env.state)
-	if evicted := miner.feeCurrencyBlocklist.Evict(parent); len(evicted) > 0 {
+	if unblocked := miner.feeCurrencyHealth.Unblock(header.Time); len(unblocked) > 0 {
 		log.Warn(
-			"Evicted temporarily blocked fee-currencies from local block-list",
-			"evicted-fee-currencies", evicted,
-			"eviction-timeout-seconds", EvictionTimeoutSeconds,
+			"Unblocked temporarily blocked fee-currencies in local txpools",
+			"unblocked-fee-currencies", unblocked,
 		)
 	}
-	env.feeCurrencyAllowlist = miner.feeCurrencyBlocklist.FilterAllowlist(
+	env.feeCurrencyAllowlist = miner.feeCurrencyHealth.FilterAllowlist(
 		common.CurrencyAllowlist(context.FeeCurrencyContext.ExchangeRates),
-		header,
+		header.Time,
 	)
 	env.exchangeRates = context.FeeCurrencyContext.ExchangeRates
 	if header.ParentBeaconRoot != nil {
@@ -315,19 +320,36 @@ func (miner *Miner) commitTransaction(env *environment, tx *types.Transaction) e
 	if tx.Type() == types.BlobTxType {
 		return miner.commitBlobTransaction(env, tx)
 	}
-	receipt, err := miner.applyTransaction(env, tx)
+	var (
+		vmConfig vm.Config
+		outcome  = FeeCurrencySuccess
+	)
+	if tx.FeeCurrency() != nil {
+		// Watch the debit and credit calls for gas overruns
+		vmConfig.Tracer = &tracing.Hooks{
+			OnFeeCurrencyGas: func(feeCurrency common.Address, gasUsed, intrinsicGas uint64) {
+				if gasUsed > intrinsicGas {
+					outcome = FeeCurrencyOverrun
+				}
+			},
+		}
+	}
+	receipt, err := miner.applyTransaction(env, tx, vmConfig)
 	if err != nil {
 		if errors.Is(err, contracts.ErrFeeCurrencyEVMCall) {
 			log.Warn(
-				"fee-currency EVM execution error, temporarily blocking fee-currency in local txpools",
+				"fee-currency EVM execution error",
 				"tx-hash", tx.Hash(),
 				"fee-currency", tx.FeeCurrency(),
 				"error", err.Error(),
 			)
-			miner.blockFeeCurrency(env, *tx.FeeCurrency(), err)
+			miner.recordFeeCurrencyOutcome(env, *tx.FeeCurrency(), FeeCurrencyFailure)
 		}
 		return err
 	}
+	if tx.FeeCurrency() != nil {
+		miner.recordFeeCurrencyOutcome(env, *tx.FeeCurrency(), outcome)
+	}
 	env.txs = append(env.txs, tx)
 	env.receipts = append(env.receipts, receipt)
 	env.tcount++
@@ -346,7 +368,7 @@ func (miner *Miner) commitBlobTransaction(env *environment, tx *types.Transactio
 	if (env.blobs+len(sc.Blobs))*params.BlobTxBlobGasPerBlob > params.MaxBlobGasPerBlock {
 		return errors.New("max data blobs reached")
 	}
-	receipt, err := miner.applyTransaction(env, tx)
+	receipt, err := miner.applyTransaction(env, tx, vm.Config{})
 	if err != nil {
 		return err
 	}
@@ -360,12 +382,12 @@ func (miner *Miner) commitBlobTransaction(env *environment, tx *types.Transactio
 }
 
 // applyTransaction runs the transaction. If execution fails, state and gas pool are reverted.
-func (miner *Miner) applyTransaction(env *environment, tx *types.Transaction) (*types.Receipt, error) {
+func (miner *Miner) applyTransaction(env *environment, tx *types.Transaction, vmConfig vm.Config) (*types.Receipt, error) {
 	var (
 		snap = env.state.Snapshot()
 		gp   = env.gasPool.Gas()
 	)
-	receipt, err := core.ApplyTransaction(miner.chainConfig, miner.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, vm.Config{})
+	receipt, err := core.ApplyTransaction(miner.chainConfig, miner.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, vmConfig)
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
 		env.gasPool.SetGas(gp)
@@ -452,6 +474,12 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 				"currency", ltx.FeeCurrency, "hash", ltx.Hash,
 				"left", left, "needed", ltx.Gas,
 			)
+			if ltx.FeeCurrency != nil {
+				if env.feeCurrencyExhausted == nil {
+					env.feeCurrencyExhausted = common.AddressSet{}
+				}
+				env.feeCurrencyExhausted[*ltx.FeeCurrency] = struct{}{}
+			}
 			txs.Pop()
 			continue
 		}
@@ -462,6 +490,21 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 			txs.Pop()
 			continue
 		}
+		// Re-check the preconditions of a conditional transaction against the
+		// block being built. Rejected transactions are dropped by the pool.
+		if cond := tx.Conditional(); cond != nil {
+			err := env.header.CheckTransactionConditional(cond)
+			if err == nil {
+				err = env.state.CheckTransactionConditional(cond)
+			}
+			if err != nil {
+				log.Debug("Rejecting conditional transaction", "hash", ltx.Hash, "err", err)
+				txConditionalRejectedCounter.Inc(1)
+				tx.SetRejected()
+				txs.Pop()
+				continue
+			}
+		}
 		// Error may be ignored here. The error has already been checked
 		// during transaction acceptance in the transaction pool.
 		from, _ := types.Sender(env.signer, tx)
@@ -503,6 +546,9 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 				// included in the consensus this is fine.
 			}
 
+			if tx.Conditional() != nil {
+				txConditionalMinedCounter.Inc(1)
+			}
 			// Everything ok, collect the logs and shift in the next transaction from the same account
 			txs.Shift()
 
@@ -523,6 +569,7 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 	miner.confMu.RLock()
 	tip := miner.config.GasPrice
 	miner.confMu.RUnlock()
+	defer reportExhaustedFeeCurrencies(env)
 
 	// Retrieve the pending transactions pre-filtered by the 1559/4844 dynamic fees
 	filter := txpool.PendingFilter{
@@ -554,6 +601,15 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 			localBlobTxs[account] = txs
 		}
 	}
+	// Fill the gas reserved for fee currencies first, so that their transactions
+	// aren't crowded out by better paying ones.
+	if reservations := miner.feeCurrencyReservations(env); reservations != nil {
+		for _, pending := range []map[common.Address][]*txpool.LazyTransaction{localPlainTxs, remotePlainTxs} {
+			if err := miner.commitReservedTransactions(env, pending, reservations, interrupt); err != nil {
+				return err
+			}
+		}
+	}
 	// Fill the block with all available pending transactions.
 	if len(localPlainTxs) > 0 || len(localBlobTxs) > 0 {
 		plainTxs := newTransactionsByPriceAndNonce(env.signer, localPlainTxs, env.header.BaseFee, env.exchangeRates)
@@ -633,14 +689,16 @@ func (miner *Miner) validateParams(genParams *generateParams) (time.Duration, er
 	return time.Duration(blockTime) * time.Second, nil
 }
 
-func (miner *Miner) blockFeeCurrency(env *environment, feeCurrency common.Address, err error) {
+// recordFeeCurrencyOutcome updates the health score of the fee currency and
+// blocks it for the rest of the block if it became unhealthy.
+func (miner *Miner) recordFeeCurrencyOutcome(env *environment, feeCurrency common.Address, outcome FeeCurrencyOutcome) {
+	if !miner.feeCurrencyHealth.Record(feeCurrency, env.header.Time, outcome) {
+		return
+	}
+	log.Warn("Unhealthy fee-currency, temporarily blocking fee-currency in local txpools", "fee-currency", feeCurrency)
 	// the fee-currency is still in the allowlist of this environment,
 	// so set the fee-currency block gas limit to 0 to prevent other
-	// transactions.
-	pool := env.multiGasPool.PoolFor(&feeCurrency)
-	pool.SetGas(0)
-	// also add the fee-currency to a worker-wide blocklist,
-	// so that they are not allowlisted in the following blocks
-	// (only locally in the txpool, not consensus-critical)
-	miner.feeCurrencyBlocklist.Add(feeCurrency, *env.header)
+	// transactions. The following blocks won't allowlist it until the
+	// block expires (only locally in the txpool, not consensus-critical)
+	env.multiGasPool.PoolFor(&feeCurrency).SetGas(0)
 }
@@ -1,5 +1,120 @@
 package params
 
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/addresses"
+)
+
 const (
 	DefaultGasLimit uint64 = 20000000 // Gas limit of the blocks before BlockchainParams contract is loaded.
+
+	FeeCurrencyExchangeRateGas     uint64 = 200 // Gas needed to read the exchange rate of a fee currency from the fee currency context
+	FeeCurrencyIntrinsicGasCostGas uint64 = 100 // Gas needed to read the intrinsic gas cost of a fee currency from the fee currency context
 )
+
+// CeloTokenAddress returns the address of the CELO token contract, which is the
+// only caller allowed to use the token duality transfer precompile. The address
+// configured in CeloConfig takes precedence, otherwise the default for the
+// chain ID is returned, falling back to the mainnet address.
+func (c *ChainConfig) CeloTokenAddress() common.Address {
+	if c.Celo != nil && c.Celo.CeloTokenAddress != nil {
+		return *c.Celo.CeloTokenAddress
+	}
+	if c.ChainID != nil {
+		if addr, ok := addresses.CeloTokenAddresses[c.ChainID.Uint64()]; ok {
+			return addr
+		}
+	}
+	return addresses.CeloTokenAddress
+}
+
+// CeloEIP1559Config are the EIP-1559 parameters of a Celo chain, activated at
+// the given time.
+type CeloEIP1559Config struct {
+	Time         uint64 `json:"time"`         // Activation time of the parameters
+	BaseFeeFloor uint64 `json:"baseFeeFloor"` // Minimum base fee
+	Elasticity   uint64 `json:"elasticity"`   // Ratio of the gas limit to the gas target
+	Denominator  uint64 `json:"denominator"`  // Bounds the base fee change between blocks
+}
+
+// CeloEIP1559Config returns the scheduled EIP-1559 parameters active at the
+// given time, or nil if none are active.
+func (c *ChainConfig) CeloEIP1559Config(time uint64) *CeloEIP1559Config {
+	if c.Celo == nil {
+		return nil
+	}
+	var active *CeloEIP1559Config
+	for i := range c.Celo.EIP1559Schedule {
+		if c.Celo.EIP1559Schedule[i].Time > time {
+			break
+		}
+		active = &c.Celo.EIP1559Schedule[i]
+	}
+	return active
+}
+
+// BaseFeeFloor returns the minimum base fee of blocks at the given time, or 0
+// if there is none.
+func (c *ChainConfig) BaseFeeFloor(time uint64) uint64 {
+	if celo := c.CeloEIP1559Config(time); celo != nil {
+		return celo.BaseFeeFloor
+	}
+	if c.Celo != nil {
+		return c.Celo.EIP1559BaseFeeFloor
+	}
+	return 0
+}
+
+// BaseFeeElasticityMultiplier returns the ratio of the gas limit to the gas
+// target used for the base fee calculation of blocks at the given time.
+func (c *ChainConfig) BaseFeeElasticityMultiplier(time uint64) uint64 {
+	if celo := c.CeloEIP1559Config(time); celo != nil {
+		return celo.Elasticity
+	}
+	return c.ElasticityMultiplier()
+}
+
+// checkEIP1559Schedule checks that the EIP-1559 schedule is ordered by time and
+// all parameters are usable.
+func (o *CeloConfig) checkEIP1559Schedule() error {
+	for i, entry := range o.EIP1559Schedule {
+		if i > 0 && entry.Time <= o.EIP1559Schedule[i-1].Time {
+			return fmt.Errorf("invalid celo eip1559 schedule: entry %d at time %d, but entry %d at time %d", i-1, o.EIP1559Schedule[i-1].Time, i, entry.Time)
+		}
+		if entry.Elasticity == 0 {
+			return fmt.Errorf("invalid celo eip1559 schedule: zero elasticity in entry %d", i)
+		}
+		if entry.Denominator == 0 {
+			return fmt.Errorf("invalid celo eip1559 schedule: zero denominator in entry %d", i)
+		}
+	}
+	return nil
+}
+
+// eip1559ScheduleDiff returns the activation times of the first differing
+// entries of two EIP-1559 schedules. A nil time means the schedule has no such
+// entry, and two nil times that the schedules are equal.
+func (o *CeloConfig) eip1559ScheduleDiff(other *CeloConfig) (*uint64, *uint64) {
+	var stored, updated []CeloEIP1559Config
+	if o != nil {
+		stored = o.EIP1559Schedule
+	}
+	if other != nil {
+		updated = other.EIP1559Schedule
+	}
+	for i := 0; i < len(stored) || i < len(updated); i++ {
+		var storedtime, newtime *uint64
+		if i < len(stored) {
+			storedtime = newUint64(stored[i].Time)
+		}
+		if i < len(updated) {
+			newtime = newUint64(updated[i].Time)
+		}
+		if storedtime == nil || newtime == nil || stored[i] != updated[i] {
+			return storedtime, newtime
+		}
+	}
+	return nil, nil
+}
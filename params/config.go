@@ -442,6 +442,7 @@ type ChainConfig struct {
 
 	Cel2Time         *uint64  `json:"cel2Time,omitempty"`         // Cel2 switch time (nil = no fork, 0 = already on optimism cel2)
 	GingerbreadBlock *big.Int `json:"gingerbreadBlock,omitempty"` // Gingerbread switch block (nil = no fork, 0 = already activated)
+	HazelnutTime     *uint64  `json:"hazelnutTime,omitempty"`     // Hazelnut switch time (nil = no fork, 0 = already on hazelnut)
 
 	// TerminalTotalDifficulty is the amount of total difficulty reached by
 	// the network that triggers the consensus upgrade.
@@ -502,11 +503,22 @@ func (o *OptimismConfig) String() string {
 
 type CeloConfig struct {
 	EIP1559BaseFeeFloor uint64 `json:"eip1559BaseFeeFloor"`
+	// EIP1559Schedule lists time-scheduled changes of the EIP-1559 parameters,
+	// ordered by activation time. Until the first entry is activated, the
+	// EIP1559BaseFeeFloor and the Optimism EIP-1559 parameters apply.
+	EIP1559Schedule []CeloEIP1559Config `json:"eip1559Schedule,omitempty"`
+	// CeloTokenAddress overrides the chain ID based default of the CELO token
+	// address, see ChainConfig.CeloTokenAddress.
+	CeloTokenAddress *common.Address `json:"celoTokenAddress,omitempty"`
 }
 
 // String implements the stringer interface, returning the celo config details.
 func (o *CeloConfig) String() string {
-	return fmt.Sprintf("celo(eip1559BaseFeeFloor: %d)", o.EIP1559BaseFeeFloor)
+	celoTokenStr := "default"
+	if o.CeloTokenAddress != nil {
+		celoTokenStr = o.CeloTokenAddress.Hex()
+	}
+	return fmt.Sprintf("celo(eip1559BaseFeeFloor: %d, eip1559Schedule: %d entries, celoTokenAddress: %s)", o.EIP1559BaseFeeFloor, len(o.EIP1559Schedule), celoTokenStr)
 }
 
 // Description returns a human-readable description of ChainConfig.
@@ -626,6 +638,9 @@ func (c *ChainConfig) Description() string {
 	if c.Cel2Time != nil {
 		banner += fmt.Sprintf(" - Cel2:                        @%-10v\n", *c.Cel2Time)
 	}
+	if c.HazelnutTime != nil {
+		banner += fmt.Sprintf(" - Hazelnut:                    @%-10v\n", *c.HazelnutTime)
+	}
 	return banner
 }
 
@@ -771,6 +786,12 @@ func (c *ChainConfig) IsCel2(time uint64) bool {
 	return isTimestampForked(c.Cel2Time, time)
 }
 
+// IsHazelnut returns whether time is either equal to the Hazelnut fork time or greater.
+// Hazelnut activates the additional Celo precompiles, see vm.PrecompiledCeloContractsHazelnut.
+func (c *ChainConfig) IsHazelnut(time uint64) bool {
+	return isTimestampForked(c.HazelnutTime, time)
+}
+
 // IsGingerbread returns whether num represents a block number after the Gingerbread fork
 func (c *ChainConfig) IsGingerbread(num *big.Int) bool {
 	return isBlockForked(c.GingerbreadBlock, num)
@@ -905,6 +926,20 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	// Hazelnut extends the Celo precompiles, which only exist from Cel2 on
+	if c.HazelnutTime != nil {
+		if c.Cel2Time == nil {
+			return fmt.Errorf("unsupported fork ordering: cel2Time not enabled, but hazelnutTime enabled at timestamp %v", *c.HazelnutTime)
+		}
+		if *c.Cel2Time > *c.HazelnutTime {
+			return fmt.Errorf("unsupported fork ordering: cel2Time enabled at timestamp %v, but hazelnutTime enabled at timestamp %v", *c.Cel2Time, *c.HazelnutTime)
+		}
+	}
+	if c.Celo != nil {
+		if err := c.Celo.checkEIP1559Schedule(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1000,12 +1035,21 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.InteropTime, newcfg.InteropTime, headTimestamp, genesisTimestamp) {
 		return newTimestampCompatError("Interop fork timestamp", c.InteropTime, newcfg.InteropTime)
 	}
+	if isForkTimestampIncompatible(c.HazelnutTime, newcfg.HazelnutTime, headTimestamp, genesisTimestamp) {
+		return newTimestampCompatError("Hazelnut fork timestamp", c.HazelnutTime, newcfg.HazelnutTime)
+	}
+	if storedtime, newtime := c.Celo.eip1559ScheduleDiff(newcfg.Celo); isTimestampForked(storedtime, headTimestamp) || isTimestampForked(newtime, headTimestamp) {
+		return newTimestampCompatError("Celo EIP-1559 schedule", storedtime, newtime)
+	}
 	return nil
 }
 
 // BaseFeeChangeDenominator bounds the amount the base fee can change between blocks.
 // The time parameters is the timestamp of the block to determine if Canyon is active or not
 func (c *ChainConfig) BaseFeeChangeDenominator(time uint64) uint64 {
+	if celo := c.CeloEIP1559Config(time); celo != nil {
+		return celo.Denominator
+	}
 	if c.Optimism != nil {
 		if c.IsCanyon(time) {
 			if c.Optimism.EIP1559DenominatorCanyon == nil || *c.Optimism.EIP1559DenominatorCanyon == 0 {
@@ -1195,7 +1239,7 @@ type Rules struct {
 	IsOptimismBedrock, IsOptimismRegolith                   bool
 	IsOptimismCanyon, IsOptimismFjord                       bool
 	IsOptimismGranite, IsOptimismHolocene                   bool
-	IsCel2                                                  bool
+	IsCel2, IsHazelnut                                      bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -1234,6 +1278,7 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsOptimismGranite:  isMerge && c.IsOptimismGranite(timestamp),
 		IsOptimismHolocene: isMerge && c.IsOptimismHolocene(timestamp),
 		// Celo
-		IsCel2: c.IsCel2(timestamp),
+		IsCel2:     c.IsCel2(timestamp),
+		IsHazelnut: c.IsCel2(timestamp) && c.IsHazelnut(timestamp),
 	}
 }
@@ -23,7 +23,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/contracts/addresses"
 	"github.com/stretchr/testify/require"
 )
 
@@ -144,6 +146,34 @@ func TestCheckCompatible(t *testing.T) {
 			genesisTimestamp: newUint64(24),
 			wantErr:          nil,
 		},
+		{
+			stored:        &ChainConfig{Celo: &CeloConfig{EIP1559Schedule: []CeloEIP1559Config{{Time: 10, Elasticity: 2, Denominator: 8}}}},
+			new:           &ChainConfig{Celo: &CeloConfig{EIP1559Schedule: []CeloEIP1559Config{{Time: 10, Elasticity: 2, Denominator: 8}, {Time: 30, Elasticity: 4, Denominator: 8}}}},
+			headTimestamp: 25,
+			wantErr:       nil,
+		},
+		{
+			stored:        &ChainConfig{Celo: &CeloConfig{EIP1559Schedule: []CeloEIP1559Config{{Time: 10, Elasticity: 2, Denominator: 8}}}},
+			new:           &ChainConfig{Celo: &CeloConfig{EIP1559Schedule: []CeloEIP1559Config{{Time: 10, Elasticity: 2, Denominator: 50}}}},
+			headTimestamp: 25,
+			wantErr: &ConfigCompatError{
+				What:         "Celo EIP-1559 schedule",
+				StoredTime:   newUint64(10),
+				NewTime:      newUint64(10),
+				RewindToTime: 9,
+			},
+		},
+		{
+			stored:        &ChainConfig{Celo: &CeloConfig{}},
+			new:           &ChainConfig{Celo: &CeloConfig{EIP1559Schedule: []CeloEIP1559Config{{Time: 20, Elasticity: 2, Denominator: 8}}}},
+			headTimestamp: 25,
+			wantErr: &ConfigCompatError{
+				What:         "Celo EIP-1559 schedule",
+				StoredTime:   nil,
+				NewTime:      newUint64(20),
+				RewindToTime: 19,
+			},
+		},
 	}
 
 	for i, test := range tests {
@@ -230,3 +260,89 @@ func TestConfigRulesCel2(t *testing.T) {
 		t.Errorf("expected %v to be Cel2", stamp)
 	}
 }
+
+func TestCeloTokenAddress(t *testing.T) {
+	custom := common.HexToAddress("0x000000000000000000000000000000000000ce10")
+	tests := []struct {
+		config *ChainConfig
+		want   common.Address
+	}{
+		{&ChainConfig{}, addresses.CeloTokenAddress},
+		{&ChainConfig{ChainID: big.NewInt(1337)}, addresses.CeloTokenAddress},
+		{&ChainConfig{ChainID: new(big.Int).SetUint64(addresses.MainnetChainID)}, addresses.CeloTokenAddress},
+		{&ChainConfig{ChainID: new(big.Int).SetUint64(addresses.AlfajoresChainID)}, addresses.CeloTokenAlfajoresAddress},
+		{&ChainConfig{ChainID: new(big.Int).SetUint64(addresses.BaklavaChainID)}, addresses.CeloTokenBaklavaAddress},
+		{&ChainConfig{ChainID: new(big.Int).SetUint64(addresses.AlfajoresChainID), Celo: &CeloConfig{}}, addresses.CeloTokenAlfajoresAddress},
+		{&ChainConfig{ChainID: new(big.Int).SetUint64(addresses.AlfajoresChainID), Celo: &CeloConfig{CeloTokenAddress: &custom}}, custom},
+	}
+	for i, test := range tests {
+		if have := test.config.CeloTokenAddress(); have != test.want {
+			t.Errorf("test %d: CeloTokenAddress mismatch: have %v, want %v", i, have, test.want)
+		}
+	}
+}
+
+func TestCeloEIP1559Schedule(t *testing.T) {
+	config := &ChainConfig{
+		Optimism: &OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50},
+		Celo: &CeloConfig{
+			EIP1559BaseFeeFloor: 100,
+			EIP1559Schedule: []CeloEIP1559Config{
+				{Time: 10, BaseFeeFloor: 200, Elasticity: 2, Denominator: 8},
+				{Time: 20, BaseFeeFloor: 50, Elasticity: 4, Denominator: 250},
+			},
+		},
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		t.Fatalf("valid schedule rejected: %v", err)
+	}
+	for _, test := range []struct {
+		time                           uint64
+		floor, elasticity, denominator uint64
+	}{
+		{9, 100, 6, 50},
+		{10, 200, 2, 8},
+		{19, 200, 2, 8},
+		{20, 50, 4, 250},
+	} {
+		if have := config.BaseFeeFloor(test.time); have != test.floor {
+			t.Errorf("time %d: base fee floor mismatch: have %d, want %d", test.time, have, test.floor)
+		}
+		if have := config.BaseFeeElasticityMultiplier(test.time); have != test.elasticity {
+			t.Errorf("time %d: elasticity mismatch: have %d, want %d", test.time, have, test.elasticity)
+		}
+		if have := config.BaseFeeChangeDenominator(test.time); have != test.denominator {
+			t.Errorf("time %d: denominator mismatch: have %d, want %d", test.time, have, test.denominator)
+		}
+	}
+
+	for i, schedule := range [][]CeloEIP1559Config{
+		{{Time: 20, Elasticity: 2, Denominator: 8}, {Time: 10, Elasticity: 2, Denominator: 8}},
+		{{Time: 10, Elasticity: 2, Denominator: 8}, {Time: 10, Elasticity: 2, Denominator: 8}},
+		{{Time: 10, Elasticity: 0, Denominator: 8}},
+		{{Time: 10, Elasticity: 2, Denominator: 0}},
+	} {
+		config := &ChainConfig{Celo: &CeloConfig{EIP1559Schedule: schedule}}
+		if err := config.CheckConfigForkOrder(); err == nil {
+			t.Errorf("test %d: invalid schedule accepted", i)
+		}
+	}
+}
+
+func TestHazelnutForkOrder(t *testing.T) {
+	for i, test := range []struct {
+		cel2, hazelnut *uint64
+		valid          bool
+	}{
+		{newUint64(0), nil, true},
+		{newUint64(10), newUint64(10), true},
+		{newUint64(10), newUint64(20), true},
+		{newUint64(20), newUint64(10), false},
+		{nil, newUint64(10), false},
+	} {
+		config := &ChainConfig{Cel2Time: test.cel2, HazelnutTime: test.hazelnut}
+		if err := config.CheckConfigForkOrder(); (err == nil) != test.valid {
+			t.Errorf("test %d: unexpected result: %v", i, err)
+		}
+	}
+}
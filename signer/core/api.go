@@ -495,6 +495,14 @@ func logDiff(original *SignTxRequest, new *SignTxResponse) bool {
 		log.Info("maxFeePerGas changed by UI", "was", a, "is", b)
 		modified = true
 	}
+	if f0, f1 := original.Transaction.FeeCurrency, new.Transaction.FeeCurrency; !reflect.DeepEqual(f0, f1) {
+		log.Info("feeCurrency changed by UI", "was", f0, "is", f1)
+		modified = true
+	}
+	if a, b := original.Transaction.MaxFeeInFeeCurrency, new.Transaction.MaxFeeInFeeCurrency; intPtrModified(a, b) {
+		log.Info("maxFeeInFeeCurrency changed by UI", "was", a, "is", b)
+		modified = true
+	}
 	if v0, v1 := big.Int(original.Transaction.Value), big.Int(new.Transaction.Value); v0.Cmp(&v1) != 0 {
 		modified = true
 		log.Info("Value changed by UI", "was", v0, "is", v1)
@@ -550,6 +558,10 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args apitypes.SendTxA
 	if err != nil {
 		return nil, err
 	}
+	// Unsupported fee currency transactions are rejected before being shown
+	if err := args.ValidateFeeCurrency(); err != nil {
+		return nil, err
+	}
 	// If we are in 'rejectMode', then reject rather than show the user warnings
 	if api.rejectMode {
 		if err := msgs.GetWarnings(); err != nil {
@@ -0,0 +1,225 @@
+package apitypes
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// CeloTxDomainName is the EIP-712 domain name identifying typed data which
+// represents a Celo fee currency transaction (CIP-64).
+//
+// Such typed data is not signed according to EIP-712. Instead, the transaction
+// it describes is signed, the typed data only serves to present the
+// transaction to the user in a human-readable form.
+const CeloTxDomainName = "Celo Transaction"
+
+// CeloTxDomainVersion is the version of the Celo transaction typed data schema.
+const CeloTxDomainVersion = "1"
+
+// CeloTxTyped is the content type for signing typed data representing a Celo
+// fee currency transaction. It is signed as a transaction, so there is no
+// EIP-191 version byte.
+var CeloTxTyped = SigFormat{Mime: "data/typed-celo-tx"}
+
+var (
+	celoTxDomainType = []Type{
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+	}
+	celoAccessTupleType = []Type{
+		{Name: "address", Type: "address"},
+		{Name: "storageKeys", Type: "bytes32[]"},
+	}
+	celoDynamicFeeTxV2Type = []Type{
+		{Name: "chainId", Type: "uint256"},
+		{Name: "nonce", Type: "uint64"},
+		{Name: "maxPriorityFeePerGas", Type: "uint256"},
+		{Name: "maxFeePerGas", Type: "uint256"},
+		{Name: "gas", Type: "uint64"},
+		{Name: "to", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "data", Type: "bytes"},
+		{Name: "accessList", Type: "AccessTuple[]"},
+		{Name: "feeCurrency", Type: "address"},
+	}
+)
+
+// celoDynamicFeeTxV2PrimaryType is the primary type name of the typed data
+// representation of CIP-64 transactions.
+const celoDynamicFeeTxV2PrimaryType = "CeloDynamicFeeTxV2"
+
+// IsCeloTx returns whether the typed data represents a Celo fee currency
+// transaction, in which case it must be signed as a transaction.
+func (typedData *TypedData) IsCeloTx() bool {
+	return typedData.Domain.Name == CeloTxDomainName
+}
+
+// CeloTxTypedData converts a CIP-64 transaction into the canonical typed data
+// representation for displaying it to the user.
+func CeloTxTypedData(tx *types.Transaction) (TypedData, error) {
+	switch tx.Type() {
+	case types.CeloDynamicFeeTxV2Type:
+	case types.CeloDenominatedTxType:
+		return TypedData{}, ErrCIP66Unsupported
+	default:
+		return TypedData{}, fmt.Errorf("unsupported transaction type %#x", tx.Type())
+	}
+	// Contract creation can't be represented, as the address type isn't nullable
+	if tx.To() == nil {
+		return TypedData{}, errors.New("contract creation not supported")
+	}
+	accessList := make([]interface{}, 0, len(tx.AccessList()))
+	for _, tuple := range tx.AccessList() {
+		keys := make([]interface{}, 0, len(tuple.StorageKeys))
+		for _, key := range tuple.StorageKeys {
+			keys = append(keys, key.Hex())
+		}
+		accessList = append(accessList, map[string]interface{}{
+			"address":     tuple.Address.Hex(),
+			"storageKeys": keys,
+		})
+	}
+	message := TypedDataMessage{
+		"chainId":              tx.ChainId().String(),
+		"nonce":                fmt.Sprintf("%d", tx.Nonce()),
+		"maxPriorityFeePerGas": tx.GasTipCap().String(),
+		"maxFeePerGas":         tx.GasFeeCap().String(),
+		"gas":                  fmt.Sprintf("%d", tx.Gas()),
+		"to":                   tx.To().Hex(),
+		"value":                tx.Value().String(),
+		"data":                 hexutil.Bytes(tx.Data()).String(),
+		"accessList":           accessList,
+		"feeCurrency":          tx.FeeCurrency().Hex(),
+	}
+	return TypedData{
+		// Copy the schema, so callers can't modify the canonical one
+		Types: Types{
+			"EIP712Domain":                append([]Type{}, celoTxDomainType...),
+			"AccessTuple":                 append([]Type{}, celoAccessTupleType...),
+			celoDynamicFeeTxV2PrimaryType: append([]Type{}, celoDynamicFeeTxV2Type...),
+		},
+		PrimaryType: celoDynamicFeeTxV2PrimaryType,
+		Domain: TypedDataDomain{
+			Name:    CeloTxDomainName,
+			Version: CeloTxDomainVersion,
+			ChainId: (*math.HexOrDecimal256)(tx.ChainId()),
+		},
+		Message: message,
+	}, nil
+}
+
+// CeloTxFromTypedData converts the typed data representation of a CIP-64
+// transaction back into the transaction. The typed data must match the
+// canonical representation as created by CeloTxTypedData.
+func CeloTxFromTypedData(typedData TypedData) (*types.Transaction, error) {
+	if !typedData.IsCeloTx() {
+		return nil, errors.New("not a celo transaction")
+	}
+	var (
+		msg  = typedData.Message
+		errs []error
+	)
+	integer := func(name string, encType string) *big.Int {
+		v, err := parseInteger(encType, msg[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			return new(big.Int)
+		}
+		return v
+	}
+	address := func(name string, value interface{}) common.Address {
+		s, ok := value.(string)
+		if !ok || !common.IsHexAddress(s) {
+			errs = append(errs, fmt.Errorf("%s: %w", name, dataMismatchError("address", value)))
+		}
+		return common.HexToAddress(s)
+	}
+	hash := func(name string, value interface{}) common.Hash {
+		b, ok := parseBytes(value)
+		if !ok || len(b) != common.HashLength {
+			errs = append(errs, fmt.Errorf("%s: %w", name, dataMismatchError("bytes32", value)))
+		}
+		return common.BytesToHash(b)
+	}
+	slice := func(name string, value interface{}) []interface{} {
+		s, err := convertDataToSlice(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		return s
+	}
+	data, ok := parseBytes(msg["data"])
+	if !ok {
+		errs = append(errs, fmt.Errorf("data: %w", dataMismatchError("bytes", msg["data"])))
+	}
+	accessList := types.AccessList{}
+	for _, entry := range slice("accessList", msg["accessList"]) {
+		tuple, ok := entry.(map[string]interface{})
+		if !ok {
+			errs = append(errs, fmt.Errorf("accessList: %w", dataMismatchError("AccessTuple", entry)))
+			continue
+		}
+		keys := []common.Hash{}
+		for _, key := range slice("storageKeys", tuple["storageKeys"]) {
+			keys = append(keys, hash("storageKeys", key))
+		}
+		accessList = append(accessList, types.AccessTuple{
+			Address:     address("address", tuple["address"]),
+			StorageKeys: keys,
+		})
+	}
+	var (
+		to          = address("to", msg["to"])
+		feeCurrency = address("feeCurrency", msg["feeCurrency"])
+		txdata      types.TxData
+	)
+	switch typedData.PrimaryType {
+	case celoDynamicFeeTxV2PrimaryType:
+		txdata = &types.CeloDynamicFeeTxV2{
+			ChainID:     integer("chainId", "uint256"),
+			Nonce:       integer("nonce", "uint64").Uint64(),
+			GasTipCap:   integer("maxPriorityFeePerGas", "uint256"),
+			GasFeeCap:   integer("maxFeePerGas", "uint256"),
+			Gas:         integer("gas", "uint64").Uint64(),
+			To:          &to,
+			Value:       integer("value", "uint256"),
+			Data:        data,
+			AccessList:  accessList,
+			FeeCurrency: &feeCurrency,
+		}
+	case "CeloDenominatedTx":
+		return nil, ErrCIP66Unsupported
+	default:
+		return nil, fmt.Errorf("unsupported primary type %q", typedData.PrimaryType)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	tx := types.NewTx(txdata)
+
+	// Make sure the user is shown exactly what is signed: the schema, domain
+	// and message must be identical to the canonical representation.
+	canonical, err := CeloTxTypedData(tx)
+	if err != nil {
+		return nil, err
+	}
+	want, _, err := TypedDataAndHash(canonical)
+	if err != nil {
+		return nil, err
+	}
+	have, _, err := TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	if string(have) != string(want) {
+		return nil, errors.New("typed data does not match the canonical celo transaction representation")
+	}
+	return tx, nil
+}
@@ -0,0 +1,122 @@
+package apitypes
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestCeloTxTypedData(t *testing.T) {
+	var (
+		chainID     = big.NewInt(44787)
+		to          = common.HexToAddress("0x000000000000000000000000000000000000dead")
+		feeCurrency = common.HexToAddress("0x000000000000000000000000000000000000ce16")
+		signer      = types.LatestSignerForChainID(chainID)
+	)
+	tx := types.NewTx(&types.CeloDynamicFeeTxV2{
+		ChainID:     chainID,
+		Nonce:       7,
+		GasTipCap:   big.NewInt(2),
+		GasFeeCap:   big.NewInt(1_000_000_000),
+		Gas:         100000,
+		To:          &to,
+		Value:       big.NewInt(1),
+		Data:        []byte{0xca, 0xfe},
+		AccessList:  types.AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}}}},
+		FeeCurrency: &feeCurrency,
+	})
+	typedData, err := CeloTxTypedData(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Pass the typed data through JSON, the way it arrives over the API
+	enc, err := json.Marshal(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded TypedData
+	if err := json.Unmarshal(enc, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.IsCeloTx() {
+		t.Fatal("typed data not recognized as celo transaction")
+	}
+	if _, err := decoded.Format(); err != nil {
+		t.Fatalf("failed to format typed data: %v", err)
+	}
+	have, err := CeloTxFromTypedData(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have.Hash() != tx.Hash() {
+		t.Fatalf("transaction mismatch: have %v, want %v", have.Hash(), tx.Hash())
+	}
+	// The signing hash must be identical to the one of the transaction signer
+	if signer.Hash(have) != signer.Hash(tx) {
+		t.Fatalf("signing hash mismatch: have %v, want %v", signer.Hash(have), signer.Hash(tx))
+	}
+}
+
+func TestCeloTxTypedDataInvalid(t *testing.T) {
+	var (
+		chainID     = big.NewInt(44787)
+		to          = common.HexToAddress("0x000000000000000000000000000000000000dead")
+		feeCurrency = common.HexToAddress("0x000000000000000000000000000000000000ce16")
+	)
+	tx := types.NewTx(&types.CeloDynamicFeeTxV2{
+		ChainID: chainID, Gas: 21000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), To: &to, FeeCurrency: &feeCurrency,
+	})
+	for i, tc := range []struct {
+		modify func(td *TypedData)
+		want   string
+	}{
+		{
+			modify: func(td *TypedData) { td.Domain.ChainId = (*math.HexOrDecimal256)(big.NewInt(1)) },
+			want:   "typed data does not match the canonical celo transaction representation",
+		},
+		{
+			modify: func(td *TypedData) { td.Message["feeCurrency"] = "0x1234" },
+			want:   "feeCurrency: provided data '0x1234' doesn't match type 'address'",
+		},
+		{
+			modify: func(td *TypedData) { td.Types[td.PrimaryType][4].Type = "uint256" },
+			want:   "typed data does not match the canonical celo transaction representation",
+		},
+		{
+			modify: func(td *TypedData) { td.PrimaryType = "Mail" },
+			want:   `unsupported primary type "Mail"`,
+		},
+		{
+			modify: func(td *TypedData) { td.PrimaryType = "CeloDenominatedTx" },
+			want:   ErrCIP66Unsupported.Error(),
+		},
+	} {
+		typedData, err := CeloTxTypedData(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tc.modify(&typedData)
+		if _, err := CeloTxFromTypedData(typedData); err == nil || err.Error() != tc.want {
+			t.Errorf("test %d: have error %v, want %q", i, err, tc.want)
+		}
+	}
+	// Contract creation and non-celo transactions can't be represented
+	if _, err := CeloTxTypedData(types.NewTx(&types.CeloDynamicFeeTxV2{ChainID: chainID, FeeCurrency: &feeCurrency})); err == nil {
+		t.Error("expected error for contract creation")
+	}
+	if _, err := CeloTxTypedData(types.NewTx(&types.DynamicFeeTx{ChainID: chainID, To: &to})); err == nil {
+		t.Error("expected error for dynamic fee transaction")
+	}
+	// CIP-66 transactions are not supported yet
+	cip66 := types.NewTx(&types.CeloDenominatedTx{
+		ChainID: chainID, Gas: 21000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), To: &to, FeeCurrency: &feeCurrency, MaxFeeInFeeCurrency: big.NewInt(1),
+	})
+	if _, err := CeloTxTypedData(cip66); !errors.Is(err, ErrCIP66Unsupported) {
+		t.Errorf("expected ErrCIP66Unsupported, got %v", err)
+	}
+}
@@ -42,6 +42,10 @@ import (
 
 var typedDataReferenceTypeRegexp = regexp.MustCompile(`^[A-Za-z](\w*)(\[\])?$`)
 
+// ErrCIP66Unsupported is returned for fee currency transactions with fees
+// denominated in CELO (CIP-66), which can't be signed yet.
+var ErrCIP66Unsupported = errors.New("CIP-66 transactions (maxFeeInFeeCurrency) are not supported")
+
 type ValidationInfo struct {
 	Typ     string `json:"type"`
 	Message string `json:"message"`
@@ -111,6 +115,10 @@ type SendTxArgs struct {
 	Blobs       []kzg4844.Blob       `json:"blobs,omitempty"`
 	Commitments []kzg4844.Commitment `json:"commitments,omitempty"`
 	Proofs      []kzg4844.Proof      `json:"proofs,omitempty"`
+
+	// Celo specific
+	FeeCurrency         *common.Address `json:"feeCurrency,omitempty"`         // CIP-64, CIP-66
+	MaxFeeInFeeCurrency *hexutil.Big    `json:"maxFeeInFeeCurrency,omitempty"` // CIP-66
 }
 
 func (args SendTxArgs) String() string {
@@ -143,6 +151,9 @@ func (args *SendTxArgs) ToTransaction() (*types.Transaction, error) {
 	if err := args.validateTxSidecar(); err != nil {
 		return nil, err
 	}
+	if err := args.ValidateFeeCurrency(); err != nil {
+		return nil, err
+	}
 	var data types.TxData
 	switch {
 	case args.BlobHashes != nil:
@@ -176,16 +187,31 @@ func (args *SendTxArgs) ToTransaction() (*types.Transaction, error) {
 		if args.AccessList != nil {
 			al = *args.AccessList
 		}
-		data = &types.DynamicFeeTx{
-			To:         to,
-			ChainID:    (*big.Int)(args.ChainID),
-			Nonce:      uint64(args.Nonce),
-			Gas:        uint64(args.Gas),
-			GasFeeCap:  (*big.Int)(args.MaxFeePerGas),
-			GasTipCap:  (*big.Int)(args.MaxPriorityFeePerGas),
-			Value:      (*big.Int)(&args.Value),
-			Data:       args.data(),
-			AccessList: al,
+		if args.FeeCurrency != nil {
+			data = &types.CeloDynamicFeeTxV2{
+				To:          to,
+				ChainID:     (*big.Int)(args.ChainID),
+				Nonce:       uint64(args.Nonce),
+				Gas:         uint64(args.Gas),
+				GasFeeCap:   (*big.Int)(args.MaxFeePerGas),
+				GasTipCap:   (*big.Int)(args.MaxPriorityFeePerGas),
+				Value:       (*big.Int)(&args.Value),
+				Data:        args.data(),
+				AccessList:  al,
+				FeeCurrency: args.FeeCurrency,
+			}
+		} else {
+			data = &types.DynamicFeeTx{
+				To:         to,
+				ChainID:    (*big.Int)(args.ChainID),
+				Nonce:      uint64(args.Nonce),
+				Gas:        uint64(args.Gas),
+				GasFeeCap:  (*big.Int)(args.MaxFeePerGas),
+				GasTipCap:  (*big.Int)(args.MaxPriorityFeePerGas),
+				Value:      (*big.Int)(&args.Value),
+				Data:       args.data(),
+				AccessList: al,
+			}
 		}
 	case args.AccessList != nil:
 		data = &types.AccessListTx{
@@ -212,6 +238,38 @@ func (args *SendTxArgs) ToTransaction() (*types.Transaction, error) {
 	return types.NewTx(data), nil
 }
 
+// IsFeeCurrencyDenominated returns whether the gas-price related
+// fields are denominated in the given fee currency (CIP-64), rather than
+// in CELO (CIP-66).
+func (args *SendTxArgs) IsFeeCurrencyDenominated() bool {
+	return args.FeeCurrency != nil && args.MaxFeeInFeeCurrency == nil
+}
+
+// ValidateFeeCurrency validates the Celo fee currency fields, if present.
+// Only CIP-64 transactions are supported, as the signer can't sign CIP-66
+// transactions yet.
+func (args *SendTxArgs) ValidateFeeCurrency() error {
+	if args.MaxFeeInFeeCurrency != nil {
+		if args.FeeCurrency == nil {
+			return errors.New("feeCurrency must be set when maxFeeInFeeCurrency is given")
+		}
+		return ErrCIP66Unsupported
+	}
+	if args.FeeCurrency == nil {
+		return nil
+	}
+	if args.BlobHashes != nil || args.Blobs != nil {
+		return errors.New("feeCurrency can't be used in blob transactions")
+	}
+	if args.MaxFeePerGas == nil || args.MaxPriorityFeePerGas == nil {
+		return errors.New("maxFeePerGas and maxPriorityFeePerGas must be set for fee currency transactions")
+	}
+	if args.MaxFeePerGas.ToInt().Cmp(args.MaxPriorityFeePerGas.ToInt()) < 0 {
+		return fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", args.MaxFeePerGas, args.MaxPriorityFeePerGas)
+	}
+	return nil
+}
+
 // validateTxSidecar validates blob data, if present
 func (args *SendTxArgs) validateTxSidecar() error {
 	// No blobs, we're done.
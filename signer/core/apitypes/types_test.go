@@ -107,6 +107,60 @@ func TestTxArgs(t *testing.T) {
 	*/
 }
 
+func TestCeloTxArgs(t *testing.T) {
+	for i, tc := range []struct {
+		data     []byte
+		wantType uint8
+		wantErr  string
+	}{
+		{
+			data:     []byte(`{"from":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","chainId":"0x7","gas":"0x124f8","input":"0x","maxFeePerGas":"0x6fc23ac00","maxPriorityFeePerGas":"0x3b9aca00","nonce":"0x0","to":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","value":"0x0","feeCurrency":"0x000000000000000000000000000000000000ce16"}`),
+			wantType: types.CeloDynamicFeeTxV2Type,
+		},
+		{
+			data:    []byte(`{"from":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","chainId":"0x7","gas":"0x124f8","input":"0x","maxFeePerGas":"0x6fc23ac00","maxPriorityFeePerGas":"0x3b9aca00","nonce":"0x0","to":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","value":"0x0","feeCurrency":"0x000000000000000000000000000000000000ce16","maxFeeInFeeCurrency":"0x1bc16d674ec80000"}`),
+			wantErr: ErrCIP66Unsupported.Error(),
+		},
+		{
+			data:    []byte(`{"from":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","chainId":"0x7","gas":"0x124f8","input":"0x","maxFeePerGas":"0x6fc23ac00","maxPriorityFeePerGas":"0x3b9aca00","nonce":"0x0","to":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","value":"0x0","maxFeeInFeeCurrency":"0x1bc16d674ec80000"}`),
+			wantErr: "feeCurrency must be set when maxFeeInFeeCurrency is given",
+		},
+		{
+			data:    []byte(`{"from":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","chainId":"0x7","gas":"0x124f8","gasPrice":"0x693d4ca8","input":"0x","nonce":"0x0","to":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","value":"0x0","feeCurrency":"0x000000000000000000000000000000000000ce16"}`),
+			wantErr: "maxFeePerGas and maxPriorityFeePerGas must be set for fee currency transactions",
+		},
+		{
+			data:    []byte(`{"from":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","chainId":"0x7","gas":"0x124f8","input":"0x","maxFeePerGas":"0x6fc23ac00","maxPriorityFeePerGas":"0x6fc23ac01","nonce":"0x0","to":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","value":"0x0","feeCurrency":"0x000000000000000000000000000000000000ce16"}`),
+			wantErr: "maxFeePerGas (0x6fc23ac00) < maxPriorityFeePerGas (0x6fc23ac01)",
+		},
+		{
+			data:    []byte(`{"from":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","chainId":"0x7","gas":"0x124f8","input":"0x","maxFeePerGas":"0x6fc23ac00","maxPriorityFeePerGas":"0x3b9aca00","maxFeePerBlobGas":"0x3b9aca00","nonce":"0x0","to":"0x1b442286e32ddcaa6e2570ce9ed85f4b4fc87425","value":"0x0","feeCurrency":"0x000000000000000000000000000000000000ce16","blobVersionedHashes":["0x010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c444014"]}`),
+			wantErr: "feeCurrency can't be used in blob transactions",
+		},
+	} {
+		var txArgs SendTxArgs
+		if err := json.Unmarshal(tc.data, &txArgs); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := txArgs.ToTransaction()
+		if tc.wantErr != "" {
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("test %d: have error %v, want %q", i, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if have := tx.Type(); have != tc.wantType {
+			t.Errorf("test %d, have type %d, want type %d", i, have, tc.wantType)
+		}
+		if have, want := *tx.FeeCurrency(), *txArgs.FeeCurrency; have != want {
+			t.Errorf("test %d: have fee currency %v, want %v", i, have, want)
+		}
+	}
+}
+
 func TestBlobTxs(t *testing.T) {
 	blob := kzg4844.Blob{0x1}
 	commitment, err := kzg4844.BlobToCommitment(&blob)
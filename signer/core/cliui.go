@@ -118,11 +118,19 @@ func (ui *CommandlineUI) ApproveTx(request *SignTxRequest) (SignTxResponse, erro
 	fmt.Printf("value:              %v wei\n", weival)
 	fmt.Printf("gas:                %v (%v)\n", request.Transaction.Gas, uint64(request.Transaction.Gas))
 	if request.Transaction.MaxFeePerGas != nil {
-		fmt.Printf("maxFeePerGas:          %v wei\n", request.Transaction.MaxFeePerGas.ToInt())
-		fmt.Printf("maxPriorityFeePerGas:  %v wei\n", request.Transaction.MaxPriorityFeePerGas.ToInt())
+		// CIP-64 transactions denominate the gas prices in the fee currency
+		unit := "wei"
+		if request.Transaction.IsFeeCurrencyDenominated() {
+			unit = "(in fee currency)"
+		}
+		fmt.Printf("maxFeePerGas:          %v %v\n", request.Transaction.MaxFeePerGas.ToInt(), unit)
+		fmt.Printf("maxPriorityFeePerGas:  %v %v\n", request.Transaction.MaxPriorityFeePerGas.ToInt(), unit)
 	} else {
 		fmt.Printf("gasprice: %v wei\n", request.Transaction.GasPrice.ToInt())
 	}
+	if feeCurrency := request.Transaction.FeeCurrency; feeCurrency != nil {
+		fmt.Printf("feeCurrency:           %v\n", feeCurrency.Hex())
+	}
 	fmt.Printf("nonce:    %v (%v)\n", request.Transaction.Nonce, uint64(request.Transaction.Nonce))
 	if chainId := request.Transaction.ChainID; chainId != nil {
 		fmt.Printf("chainid:  %v\n", chainId)
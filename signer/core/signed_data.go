@@ -75,6 +75,15 @@ func (api *SignerAPI) sign(req *SignDataRequest, legacyV bool) (hexutil.Bytes, e
 //
 // Different types of validation occur.
 func (api *SignerAPI) SignData(ctx context.Context, contentType string, addr common.MixedcaseAddress, data interface{}) (hexutil.Bytes, error) {
+	// Celo transactions in typed data form are signed as transactions, they go
+	// through the transaction approval instead of the data one
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == apitypes.CeloTxTyped.Mime {
+		typedData, err := parseTypedData(data)
+		if err != nil {
+			return nil, err
+		}
+		return api.signCeloTxTypedData(ctx, addr, typedData)
+	}
 	var req, transformV, err = api.determineSignatureFormat(ctx, contentType, addr, data)
 	if err != nil {
 		return nil, err
@@ -221,10 +230,15 @@ func cliqueHeaderHashAndRlp(header *types.Header) (hash, rlp []byte, err error)
 
 // SignTypedData signs EIP-712 conformant typed data
 // hash = keccak256("\x19${byteVersion}${domainSeparator}${hashStruct(message)}")
+// If the typed data represents a Celo fee currency transaction (see
+// apitypes.CeloTxTypedData), it is signed as a transaction instead.
 // It returns
 // - the signature,
 // - and/or any error
 func (api *SignerAPI) SignTypedData(ctx context.Context, addr common.MixedcaseAddress, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	if typedData.IsCeloTx() {
+		return api.signCeloTxTypedData(ctx, addr, typedData)
+	}
 	signature, _, err := api.signTypedData(ctx, addr, typedData, nil)
 	return signature, err
 }
@@ -260,19 +274,31 @@ func fromHex(data any) ([]byte, error) {
 	return nil, fmt.Errorf("wrong type %T", data)
 }
 
-// typedDataRequest tries to convert the data into a SignDataRequest.
-func typedDataRequest(data any) (*SignDataRequest, error) {
+// parseTypedData tries to interpret the data as typed data, either as is or
+// hex-encoded JSON.
+func parseTypedData(data any) (apitypes.TypedData, error) {
 	var typedData apitypes.TypedData
 	if td, ok := data.(apitypes.TypedData); ok {
-		typedData = td
-	} else { // Hex-encoded data
-		jsonData, err := fromHex(data)
-		if err != nil {
-			return nil, err
-		}
-		if err = json.Unmarshal(jsonData, &typedData); err != nil {
-			return nil, err
-		}
+		return td, nil
+	}
+	// Hex-encoded data
+	jsonData, err := fromHex(data)
+	if err != nil {
+		return typedData, err
+	}
+	err = json.Unmarshal(jsonData, &typedData)
+	return typedData, err
+}
+
+// typedDataRequest tries to convert the data into a SignDataRequest.
+func typedDataRequest(data any) (*SignDataRequest, error) {
+	typedData, err := parseTypedData(data)
+	if err != nil {
+		return nil, err
+	}
+	// Celo transactions must never be approved as plain typed data
+	if typedData.IsCeloTx() {
+		return nil, fmt.Errorf("celo transaction typed data must be signed with content type %s", apitypes.CeloTxTyped.Mime)
 	}
 	messages, err := typedData.Format()
 	if err != nil {
@@ -289,6 +315,53 @@ func typedDataRequest(data any) (*SignDataRequest, error) {
 		Hash:        sighash}, nil
 }
 
+// signCeloTxTypedData signs typed data representing a Celo fee currency
+// transaction. The transaction is subject to the same validation, rules and
+// approval as one passed to SignTransaction. The returned signature is the
+// transaction signature, with V in the 27/28 form.
+func (api *SignerAPI) signCeloTxTypedData(ctx context.Context, addr common.MixedcaseAddress, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	tx, err := apitypes.CeloTxFromTypedData(typedData)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		to    = common.NewMixedcaseAddress(*tx.To())
+		input = hexutil.Bytes(tx.Data())
+		al    = tx.AccessList()
+	)
+	args := apitypes.SendTxArgs{
+		From:                 addr,
+		To:                   &to,
+		Gas:                  hexutil.Uint64(tx.Gas()),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasFeeCap()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasTipCap()),
+		Value:                hexutil.Big(*tx.Value()),
+		Nonce:                hexutil.Uint64(tx.Nonce()),
+		Input:                &input,
+		AccessList:           &al,
+		ChainID:              (*hexutil.Big)(tx.ChainId()),
+		FeeCurrency:          tx.FeeCurrency(),
+	}
+	res, err := api.SignTransaction(ctx, args, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The UI may have modified the transaction, in which case the signature is
+	// useless to the caller, who only knows about the requested one
+	signer := types.LatestSignerForChainID(tx.ChainId())
+	if signer.Hash(res.Tx) != signer.Hash(tx) {
+		err := errors.New("celo transaction modified during approval")
+		api.UI.ShowError(err.Error())
+		return nil, err
+	}
+	v, r, s := res.Tx.RawSignatureValues()
+	signature := make([]byte, crypto.SignatureLength)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:64])
+	signature[64] = byte(v.Uint64()) + 27 // Transform V from 0/1 to 27/28 like other signed data
+	return signature, nil
+}
+
 // EcRecover recovers the address associated with the given sig.
 // Only compatible with `text/plain`
 func (api *SignerAPI) EcRecover(ctx context.Context, data hexutil.Bytes, sig hexutil.Bytes) (common.Address, error) {
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
@@ -31,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/signer/core"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
@@ -248,6 +250,102 @@ func TestSignData(t *testing.T) {
 	}
 }
 
+func TestSignCeloTxTypedData(t *testing.T) {
+	t.Parallel()
+	api, control := setup(t)
+	createAccount(control, api, t)
+	control.approveCh <- "A"
+	list, err := api.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := common.NewMixedcaseAddress(list[0])
+
+	var (
+		chainID     = big.NewInt(1337)
+		to          = common.HexToAddress("0x000000000000000000000000000000000000dead")
+		feeCurrency = common.HexToAddress("0x000000000000000000000000000000000000ce16")
+		signer      = types.LatestSignerForChainID(chainID)
+	)
+	tx := types.NewTx(&types.CeloDynamicFeeTxV2{
+		ChainID: chainID, Nonce: 1, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(1_000_000_000), Gas: 100000,
+		To: &to, Value: big.NewInt(1), Data: []byte{0xca, 0xfe}, FeeCurrency: &feeCurrency,
+	})
+	celoTypedData, err := apitypes.CeloTxTypedData(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := json.Marshal(celoTypedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSignature := func(signature hexutil.Bytes) {
+		t.Helper()
+		// The signature is over the transaction signing hash, in the 27/28 V format
+		signature[64] -= 27
+		signed, err := tx.WithSignature(signer, signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sender, err := types.Sender(signer, signed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sender != a.Address() {
+			t.Errorf("sender mismatch: have %v, want %v", sender, a.Address())
+		}
+	}
+	// Both the typed data and the data signing endpoints go through the
+	// transaction approval
+	control.approveCh <- "Y"
+	control.inputCh <- "a_long_password"
+	signature, err := api.SignTypedData(context.Background(), a, celoTypedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSignature(signature)
+
+	control.approveCh <- "Y"
+	control.inputCh <- "a_long_password"
+	signature, err = api.SignData(context.Background(), apitypes.CeloTxTyped.Mime, a, hexutil.Encode(enc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSignature(signature)
+
+	// Celo transactions can't be approved as plain typed data
+	if _, err := api.SignData(context.Background(), apitypes.DataTyped.Mime, a, hexutil.Encode(enc)); err == nil {
+		t.Error("expected error signing celo transaction as typed data")
+	}
+	// Transactions modified by the UI can't be used by the caller
+	control.approveCh <- "M"
+	control.inputCh <- "a_long_password"
+	if _, err := api.SignTypedData(context.Background(), a, celoTypedData); err == nil {
+		t.Error("expected error for modified transaction")
+	}
+	// Rejected transactions are not signed
+	control.approveCh <- "N"
+	if _, err := api.SignTypedData(context.Background(), a, celoTypedData); err != core.ErrRequestDenied {
+		t.Errorf("expected ErrRequestDenied, got %v", err)
+	}
+	// The chain ID must match the one of the signer
+	other, err := apitypes.CeloTxTypedData(types.NewTx(&types.CeloDynamicFeeTxV2{
+		ChainID: big.NewInt(44787), GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(1_000_000_000), Gas: 100000,
+		To: &to, FeeCurrency: &feeCurrency,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.SignTypedData(context.Background(), a, other); err == nil {
+		t.Error("expected error for chain ID mismatch")
+	}
+	// CIP-66 transactions are rejected
+	celoTypedData.PrimaryType = "CeloDenominatedTx"
+	if _, err := api.SignTypedData(context.Background(), a, celoTypedData); !errors.Is(err, apitypes.ErrCIP66Unsupported) {
+		t.Errorf("expected ErrCIP66Unsupported, got %v", err)
+	}
+}
+
 func TestDomainChainId(t *testing.T) {
 	t.Parallel()
 	withoutChainID := apitypes.TypedData{
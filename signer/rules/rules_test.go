@@ -196,6 +196,52 @@ func TestSignTxRequest(t *testing.T) {
 	}
 }
 
+func TestSignFeeCurrencyTxRequest(t *testing.T) {
+	t.Parallel()
+	js := `
+	function ApproveTx(r){
+		console.log("transaction.feeCurrency", r.transaction.feeCurrency);
+		if(r.transaction.feeCurrency === undefined){ return "Reject"}
+		if(r.transaction.feeCurrency.toLowerCase()!="0x000000000000000000000000000000000000ce16"){ return "Reject"}
+		return "Approve"
+	}`
+
+	r, err := initRuleEngine(js)
+	if err != nil {
+		t.Errorf("Couldn't create evaluator %v", err)
+		return
+	}
+	from, err := mixAddr("0000000000000000000000000000000000001337")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var (
+		allowed = common.HexToAddress("0x000000000000000000000000000000000000ce16")
+		other   = common.HexToAddress("0x000000000000000000000000000000000000beef")
+	)
+	for i, tc := range []struct {
+		feeCurrency *common.Address
+		approved    bool
+	}{
+		{nil, false},
+		{&allowed, true},
+		{&other, false},
+	} {
+		tx := apitypes.SendTxArgs{From: *from, FeeCurrency: tc.feeCurrency}
+		resp, err := r.ApproveTx(&core.SignTxRequest{
+			Transaction: tx,
+			Meta:        core.Metadata{Remote: "remoteip", Local: "localip", Scheme: "inproc"},
+		})
+		if err != nil {
+			t.Errorf("test %d: unexpected error %v", i, err)
+		}
+		if resp.Approved != tc.approved {
+			t.Errorf("test %d: have approved %v, want %v", i, resp.Approved, tc.approved)
+		}
+	}
+}
+
 type dummyUI struct {
 	calls []string
 }
@@ -0,0 +1,53 @@
+package celo
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/tests"
+)
+
+var celoTestDir = filepath.Join(".", "testdata")
+
+func TestCelo(t *testing.T) {
+	t.Parallel()
+
+	files, err := filepath.Glob(filepath.Join(celoTestDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test files found")
+	}
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var testsByName map[string]*CeloTest
+		if err := json.Unmarshal(src, &testsByName); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		names := make([]string, 0, len(testsByName))
+		for name := range testsByName {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			test := testsByName[name]
+			t.Run(filepath.Base(file)+"/"+name, func(t *testing.T) {
+				for _, scheme := range []string{rawdb.HashScheme, rawdb.PathScheme} {
+					err := test.Run(vm.Config{}, scheme, func(error, *tests.StateTestState) {})
+					if err != nil {
+						t.Errorf("%s: %v", scheme, err)
+					}
+				}
+			})
+		}
+	}
+}
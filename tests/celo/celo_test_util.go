@@ -0,0 +1,350 @@
+// Package celo runs test fixtures for Celo specific behaviour, which the
+// Ethereum state and blockchain tests do not cover.
+//
+// A fixture file maps test names to tests of the form
+//
+//	{
+//	  "fork": "Cel2",
+//	  "env": { "currentCoinbase", "currentGasLimit", "currentNumber", "currentTimestamp", "currentBaseFee", "feeCurrencyContext" },
+//	  "pre": { <genesis alloc> },
+//	  "feeCurrencyLimits": { "default": 0.5, "limits": { <fee currency>: 0.9 } },
+//	  "transactions": [ <RPC encoded transaction, optionally with "secretKey"> ],
+//	  "post": {
+//	    "stateRoot": <optional>,
+//	    "results": [ { "error" } or { "status", "gasUsed", "baseFee" } ],
+//	    "accounts": { <address>: { "balance", "nonce", "storage" } },
+//	    "tokenBalances": { <token>: { <holder>: <balance> } }
+//	  }
+//	}
+//
+// The feeCurrencyContext and feeCurrencyLimits are optional. Without them, the
+// fee currencies are read from the FeeCurrencyDirectory in the prestate and
+// no per fee currency block gas limits are applied.
+package celo
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/contracts"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/tests"
+)
+
+// ErrFeeCurrencyGasLimitReached is returned for transactions that do not fit
+// into the block gas limit of their fee currency.
+var ErrFeeCurrencyGasLimitReached = errors.New("fee currency block gas limit reached")
+
+// CeloTest checks Celo specific transaction processing: fee currency debits and
+// credits, token duality transfers, the per fee currency block gas limits of
+// the MultiGasPool and the Cel2 fork transition.
+//
+// A test applies a list of transactions on top of the prestate, as if they were
+// included in a single block, and verifies the outcome of each transaction and
+// selected parts of the post-state.
+type CeloTest struct {
+	json ctJSON
+}
+
+func (t *CeloTest) UnmarshalJSON(in []byte) error {
+	return json.Unmarshal(in, &t.json)
+}
+
+type ctJSON struct {
+	Fork              string               `json:"fork"`
+	Env               ctEnv                `json:"env"`
+	Pre               types.GenesisAlloc   `json:"pre"`
+	FeeCurrencyLimits *ctFeeCurrencyLimits `json:"feeCurrencyLimits,omitempty"`
+	Txs               []*ctTransaction     `json:"transactions"`
+	Post              ctPost               `json:"post"`
+}
+
+type ctEnv struct {
+	Coinbase  common.Address        `json:"currentCoinbase"`
+	GasLimit  math.HexOrDecimal64   `json:"currentGasLimit"`
+	Number    math.HexOrDecimal64   `json:"currentNumber"`
+	Timestamp math.HexOrDecimal64   `json:"currentTimestamp"`
+	BaseFee   *math.HexOrDecimal256 `json:"currentBaseFee"`
+
+	// FeeCurrencyContext overrides the exchange rates and intrinsic gas costs
+	// read from the FeeCurrencyDirectory in the prestate.
+	FeeCurrencyContext *common.FeeCurrencyContext `json:"feeCurrencyContext,omitempty"`
+}
+
+// ctFeeCurrencyLimits configures the MultiGasPool, with the limits given as a
+// fraction of the block gas limit, like the miner's fee currency limits.
+type ctFeeCurrencyLimits struct {
+	Default float64                    `json:"default"`
+	Limits  map[common.Address]float64 `json:"limits,omitempty"`
+}
+
+// ctTransaction is a transaction in its RPC JSON encoding. If a secretKey is
+// given, the transaction is signed with it before being applied.
+type ctTransaction struct {
+	tx  *types.Transaction
+	key *ecdsa.PrivateKey
+}
+
+func (t *ctTransaction) UnmarshalJSON(input []byte) error {
+	var meta struct {
+		Key *common.Hash `json:"secretKey"`
+	}
+	if err := json.Unmarshal(input, &meta); err != nil {
+		return err
+	}
+	if meta.Key != nil {
+		key, err := crypto.ToECDSA(meta.Key.Bytes())
+		if err != nil {
+			return err
+		}
+		t.key = key
+	}
+	t.tx = new(types.Transaction)
+	return json.Unmarshal(input, t.tx)
+}
+
+type ctPost struct {
+	Root          *common.Hash                                                `json:"stateRoot,omitempty"`
+	Results       []ctResult                                                  `json:"results"`
+	Accounts      map[common.Address]ctAccount                                `json:"accounts,omitempty"`
+	TokenBalances map[common.Address]map[common.Address]*math.HexOrDecimal256 `json:"tokenBalances,omitempty"`
+}
+
+// ctResult is the expected outcome of a transaction. Transactions which are not
+// included in the block must specify the expected error, a substring of the
+// actual error message.
+type ctResult struct {
+	Error   string                `json:"error,omitempty"`
+	Status  *math.HexOrDecimal64  `json:"status,omitempty"`
+	GasUsed *math.HexOrDecimal64  `json:"gasUsed,omitempty"`
+	BaseFee *math.HexOrDecimal256 `json:"baseFee,omitempty"`
+}
+
+type ctAccount struct {
+	Balance *math.HexOrDecimal256       `json:"balance,omitempty"`
+	Nonce   *math.HexOrDecimal64        `json:"nonce,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// TxResult is the outcome of applying a single transaction of a test.
+type TxResult struct {
+	Receipt *types.Receipt
+	Err     error
+}
+
+// Fork returns the fork the test is run on.
+func (t *CeloTest) Fork() string {
+	return t.json.Fork
+}
+
+// Run executes the test and verifies the transaction results and the post-state.
+func (t *CeloTest) Run(vmconfig vm.Config, scheme string, postCheck func(err error, st *tests.StateTestState)) (result error) {
+	st, root, results, err := t.RunNoVerify(vmconfig, scheme)
+	// Invoke the callback at the end of function for further analysis.
+	defer func() {
+		postCheck(result, &st)
+		st.Close()
+	}()
+	if err != nil {
+		return err
+	}
+	return t.verify(st.StateDB, root, results)
+}
+
+// RunNoVerify executes the test and returns the statedb, the post-state root
+// and the results of the individual transactions.
+// Remember to call state.Close after verifying the test result!
+func (t *CeloTest) RunNoVerify(vmconfig vm.Config, scheme string) (st tests.StateTestState, root common.Hash, results []TxResult, err error) {
+	config, eips, err := tests.GetChainConfig(t.json.Fork)
+	if err != nil {
+		return st, common.Hash{}, nil, tests.UnsupportedForkError{Name: t.json.Fork}
+	}
+	vmconfig.ExtraEips = eips
+
+	st = tests.MakePreState(rawdb.NewMemoryDatabase(), t.json.Pre, false, scheme)
+
+	header := t.header()
+	context := core.NewEVMBlockContext(header, nil, &header.Coinbase, config, st.StateDB)
+	context.GetHash = ctBlockHash
+	if t.json.Env.FeeCurrencyContext != nil {
+		context.FeeCurrencyContext = *t.json.Env.FeeCurrencyContext
+	}
+	var (
+		signer   = types.MakeSigner(config, header.Number, header.Time)
+		gaspool  = new(core.GasPool).AddGas(header.GasLimit)
+		multiGas *core.MultiGasPool
+		usedGas  uint64
+	)
+	if limits := t.json.FeeCurrencyLimits; limits != nil {
+		multiGas = core.NewMultiGasPool(
+			header.GasLimit,
+			common.CurrencyAllowlist(context.FeeCurrencyContext.ExchangeRates),
+			limits.Default,
+			limits.Limits,
+		)
+	}
+	for i, ctTx := range t.json.Txs {
+		tx, err := ctTx.sign(config)
+		if err != nil {
+			return st, common.Hash{}, nil, fmt.Errorf("tx %d: %v", i, err)
+		}
+		receipt, err := applyTransaction(config, context, vmconfig, st.StateDB, gaspool, multiGas, signer, tx, len(results), &usedGas)
+		results = append(results, TxResult{Receipt: receipt, Err: err})
+	}
+	// Commit state mutations into database.
+	root, err = st.StateDB.Commit(header.Number.Uint64(), config.IsEIP158(header.Number))
+	if err != nil {
+		return st, common.Hash{}, nil, err
+	}
+	st.StateDB, err = state.New(root, st.StateDB.Database(), st.Snapshots)
+	return st, root, results, err
+}
+
+// applyTransaction applies a transaction the way the miner does, including the
+// per fee currency block gas limits if a MultiGasPool is given.
+func applyTransaction(config *params.ChainConfig, context vm.BlockContext, vmconfig vm.Config, statedb *state.StateDB, gaspool *core.GasPool, multiGas *core.MultiGasPool, signer types.Signer, tx *types.Transaction, txIndex int, usedGas *uint64) (*types.Receipt, error) {
+	if multiGas != nil {
+		if left := multiGas.PoolFor(tx.FeeCurrency()).Gas(); left < tx.Gas() {
+			return nil, fmt.Errorf("%w: have %d, want %d", ErrFeeCurrencyGasLimitReached, left, tx.Gas())
+		}
+	}
+	msg, err := core.TransactionToMessage(tx, signer, context.BaseFee, context.FeeCurrencyContext.ExchangeRates)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		snapshot = statedb.Snapshot()
+		prevGas  = gaspool.Gas()
+		evm      = vm.NewEVM(context, core.NewEVMTxContext(msg), statedb, config, vmconfig)
+	)
+	statedb.SetTxContext(tx.Hash(), txIndex)
+	receipt, err := core.ApplyTransactionWithEVM(msg, config, gaspool, statedb, context.BlockNumber, common.Hash{}, tx, usedGas, evm)
+	if err != nil {
+		statedb.RevertToSnapshot(snapshot)
+		gaspool.SetGas(prevGas)
+		return nil, err
+	}
+	if multiGas != nil {
+		// Cannot fail, the gas left was checked above
+		multiGas.PoolFor(tx.FeeCurrency()).SubGas(receipt.GasUsed)
+	}
+	return receipt, nil
+}
+
+func (t *CeloTest) header() *types.Header {
+	header := &types.Header{
+		Coinbase:   t.json.Env.Coinbase,
+		Difficulty: new(big.Int),
+		Number:     new(big.Int).SetUint64(uint64(t.json.Env.Number)),
+		GasLimit:   uint64(t.json.Env.GasLimit),
+		Time:       uint64(t.json.Env.Timestamp),
+	}
+	if t.json.Env.BaseFee != nil {
+		header.BaseFee = (*big.Int)(t.json.Env.BaseFee)
+	}
+	return header
+}
+
+// sign signs the transaction with the latest signer of the chain config, so
+// that transaction types of later forks can be used to test fork transitions.
+func (t *ctTransaction) sign(config *params.ChainConfig) (*types.Transaction, error) {
+	if t.key == nil {
+		return t.tx, nil
+	}
+	return types.SignTx(t.tx, types.LatestSigner(config), t.key)
+}
+
+func (t *CeloTest) verify(statedb *state.StateDB, root common.Hash, results []TxResult) error {
+	post := t.json.Post
+	if post.Root != nil && root != *post.Root {
+		return fmt.Errorf("post state root mismatch: got %x, want %x", root, *post.Root)
+	}
+	if len(results) != len(post.Results) {
+		return fmt.Errorf("result count mismatch: got %d, want %d", len(results), len(post.Results))
+	}
+	for i, want := range post.Results {
+		if err := want.verify(results[i]); err != nil {
+			return fmt.Errorf("tx %d: %v", i, err)
+		}
+	}
+	for addr, want := range post.Accounts {
+		if err := want.verify(statedb, addr); err != nil {
+			return fmt.Errorf("account %x: %v", addr, err)
+		}
+	}
+	config, _, _ := tests.GetChainConfig(t.json.Fork)
+	backend := &contracts.CeloBackend{ChainConfig: config, State: statedb}
+	for token, balances := range post.TokenBalances {
+		for holder, want := range balances {
+			have, err := contracts.GetBalanceERC20(backend, holder, token)
+			if err != nil {
+				return fmt.Errorf("token %x: %v", token, err)
+			}
+			if have.Cmp((*big.Int)(want)) != 0 {
+				return fmt.Errorf("token %x: balance mismatch for %x: got %v, want %v", token, holder, have, (*big.Int)(want))
+			}
+		}
+	}
+	return nil
+}
+
+func (r *ctResult) verify(have TxResult) error {
+	if have.Err != nil {
+		if r.Error == "" {
+			return fmt.Errorf("unexpected error: %w", have.Err)
+		}
+		if !strings.Contains(have.Err.Error(), r.Error) {
+			return fmt.Errorf("error mismatch: got %q, want %q", have.Err, r.Error)
+		}
+		return nil
+	}
+	if r.Error != "" {
+		return fmt.Errorf("expected error %q, got no error", r.Error)
+	}
+	receipt := have.Receipt
+	if r.Status != nil && receipt.Status != uint64(*r.Status) {
+		return fmt.Errorf("status mismatch: got %d, want %d", receipt.Status, uint64(*r.Status))
+	}
+	if r.GasUsed != nil && receipt.GasUsed != uint64(*r.GasUsed) {
+		return fmt.Errorf("gas used mismatch: got %d, want %d", receipt.GasUsed, uint64(*r.GasUsed))
+	}
+	if r.BaseFee != nil && (receipt.BaseFee == nil || receipt.BaseFee.Cmp((*big.Int)(r.BaseFee)) != 0) {
+		return fmt.Errorf("receipt base fee mismatch: got %v, want %v", receipt.BaseFee, (*big.Int)(r.BaseFee))
+	}
+	return nil
+}
+
+func (a *ctAccount) verify(statedb *state.StateDB, addr common.Address) error {
+	if a.Balance != nil {
+		if have := statedb.GetBalance(addr).ToBig(); have.Cmp((*big.Int)(a.Balance)) != 0 {
+			return fmt.Errorf("balance mismatch: got %v, want %v", have, (*big.Int)(a.Balance))
+		}
+	}
+	if a.Nonce != nil {
+		if have := statedb.GetNonce(addr); have != uint64(*a.Nonce) {
+			return fmt.Errorf("nonce mismatch: got %d, want %d", have, uint64(*a.Nonce))
+		}
+	}
+	for key, want := range a.Storage {
+		if have := statedb.GetState(addr, key); have != want {
+			return fmt.Errorf("storage mismatch at %x: got %x, want %x", key, have, want)
+		}
+	}
+	return nil
+}
+
+func ctBlockHash(n uint64) common.Hash {
+	return common.BytesToHash(crypto.Keccak256([]byte(big.NewInt(int64(n)).String())))
+}